@@ -0,0 +1,244 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/pkg/lockedfile"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	responseCacheFileName = "responsecache.yaml"
+	cacheTimeLayout       = time.RFC3339
+)
+
+var (
+	// NoCache disables the response cache for clients created via
+	// NewWithConfig. It is set via the global --no-cache flag.
+	NoCache bool
+
+	// cacheDurations are the paths of the responses to cache, with the time
+	// a response is used without asking the API again.
+	cacheDurations = map[string]time.Duration{
+		"/v4/info/":     time.Hour,
+		"/v4/releases/": 10 * time.Minute,
+	}
+)
+
+// responseCache is the file structure of the response cache file.
+type responseCache struct {
+	// Endpoints maps API endpoints to their responses.
+	Endpoints map[string]*endpointResponses `yaml:"endpoints"`
+}
+
+// endpointResponses stores the responses of one API endpoint.
+type endpointResponses struct {
+	// Responses maps request paths to responses.
+	Responses map[string]*cachedResponse `yaml:"responses"`
+}
+
+// cachedResponse is a response stored in the cache. ETag and LastModified
+// are used to ask the API whether the response is still valid after the
+// expiry date.
+type cachedResponse struct {
+	Expiry       string `yaml:"expiry"`
+	ETag         string `yaml:"etag,omitempty"`
+	LastModified string `yaml:"last_modified,omitempty"`
+	ContentType  string `yaml:"content_type,omitempty"`
+	Body         string `yaml:"body"`
+}
+
+// cacheTransport is a http.RoundTripper which serves responses rarely
+// changing, like the installation info and the releases, from a file in
+// the config directory. The file is locked while being accessed, so that
+// several transports and several gsctl processes can use it at the same
+// time.
+type cacheTransport struct {
+	inner    http.RoundTripper
+	fs       afero.Fs
+	filePath string
+
+	// now returns the current time, to check the expiry.
+	now func() time.Time
+}
+
+// setCache wraps a transport so that responses get cached in a file in the
+// given directory.
+func setCache(inner http.RoundTripper, fs afero.Fs, dir string) http.RoundTripper {
+	return &cacheTransport{
+		inner:    inner,
+		fs:       fs,
+		filePath: path.Join(dir, responseCacheFileName),
+		now:      time.Now,
+	}
+}
+
+// cacheFileSystem returns the file system to store the response cache in.
+func cacheFileSystem() afero.Fs {
+	if config.FileSystem != nil {
+		return config.FileSystem
+	}
+
+	return afero.NewOsFs()
+}
+
+// RoundTrip implements http.RoundTripper. Responses which have expired are
+// revalidated via If-None-Match or If-Modified-Since, if the API sent an
+// ETag or Last-Modified header.
+func (t *cacheTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	duration, ok := cacheDurations[r.URL.Path]
+	if !ok || r.Method != http.MethodGet {
+		return t.inner.RoundTrip(r)
+	}
+
+	endpoint := r.URL.Scheme + "://" + r.URL.Host
+	key := r.URL.RequestURI()
+
+	cached := t.read().response(endpoint, key)
+	if cached != nil {
+		expiry, err := time.Parse(cacheTimeLayout, cached.Expiry)
+		if err == nil && t.now().Before(expiry) {
+			return cachedHTTPResponse(r, cached), nil
+		}
+
+		if cached.ETag != "" || cached.LastModified != "" {
+			r = r.Clone(r.Context())
+			if cached.ETag != "" {
+				r.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				r.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+	}
+
+	response, err := t.inner.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case response.StatusCode == http.StatusNotModified && cached != nil:
+		response.Body.Close()
+
+		cached.Expiry = t.now().Add(duration).Format(cacheTimeLayout)
+		t.store(endpoint, key, cached)
+
+		return cachedHTTPResponse(r, cached), nil
+
+	case response.StatusCode == http.StatusOK && !strings.Contains(response.Header.Get("Cache-Control"), "no-store"):
+		body, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		t.store(endpoint, key, &cachedResponse{
+			Expiry:       t.now().Add(duration).Format(cacheTimeLayout),
+			ETag:         response.Header.Get("ETag"),
+			LastModified: response.Header.Get("Last-Modified"),
+			ContentType:  response.Header.Get("Content-Type"),
+			Body:         string(body),
+		})
+	}
+
+	return response, nil
+}
+
+// read returns the content of the cache file, or an empty cache if the file
+// does not exist or cannot be parsed.
+func (t *cacheTransport) read() *responseCache {
+	unlock, err := lockedfile.Lock(t.fs, t.filePath)
+	if err != nil {
+		return &responseCache{Endpoints: map[string]*endpointResponses{}}
+	}
+	defer unlock()
+
+	return t.readUnlocked()
+}
+
+// readUnlocked reads the cache file without acquiring the lock.
+func (t *cacheTransport) readUnlocked() *responseCache {
+	cache := &responseCache{}
+
+	data, err := afero.ReadFile(t.fs, t.filePath)
+	if err == nil {
+		_ = yaml.Unmarshal(data, cache)
+	}
+	if cache.Endpoints == nil {
+		cache.Endpoints = map[string]*endpointResponses{}
+	}
+
+	return cache
+}
+
+// store adds a response to the cache file. The file is read again while
+// locked, so that responses stored in the meantime by other transports or
+// processes are kept. As the cache is only an optimization, errors are
+// ignored.
+func (t *cacheTransport) store(endpoint, key string, response *cachedResponse) {
+	unlock, err := lockedfile.Lock(t.fs, t.filePath)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	cache := t.readUnlocked()
+	cache.setResponse(endpoint, key, response)
+
+	data, err := yaml.Marshal(cache)
+	if err != nil {
+		return
+	}
+
+	_ = lockedfile.Write(t.fs, t.filePath, data, config.ConfigFilePermission)
+}
+
+func (c *responseCache) response(endpoint, key string) *cachedResponse {
+	responses, ok := c.Endpoints[endpoint]
+	if !ok || responses == nil {
+		return nil
+	}
+
+	return responses.Responses[key]
+}
+
+func (c *responseCache) setResponse(endpoint, key string, response *cachedResponse) {
+	responses, ok := c.Endpoints[endpoint]
+	if !ok || responses == nil || responses.Responses == nil {
+		responses = &endpointResponses{Responses: map[string]*cachedResponse{}}
+		c.Endpoints[endpoint] = responses
+	}
+
+	responses.Responses[key] = response
+}
+
+// cachedHTTPResponse creates a response from a cached one.
+func cachedHTTPResponse(r *http.Request, cached *cachedResponse) *http.Response {
+	header := http.Header{}
+	if cached.ContentType != "" {
+		header.Set("Content-Type", cached.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK)),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       r,
+	}
+}
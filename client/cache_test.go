@@ -0,0 +1,149 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// TestCacheTransport checks that responses are served from the cache until
+// they expire, and that expired responses are revalidated via ETag.
+func TestCacheTransport(t *testing.T) {
+	requests := map[string]int{}
+	notModified := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"general": {"installation_name": "codename", "provider": "aws"}}`))
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	transport := setCache(http.DefaultTransport, fs, "/config").(*cacheTransport)
+	transport.now = func() time.Time { return now }
+	httpClient := &http.Client{Transport: transport}
+
+	get := func(path string) string {
+		resp, err := httpClient.Get(mockServer.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", resp.StatusCode)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	first := get("/v4/info/")
+	second := get("/v4/info/")
+	if first != second {
+		t.Errorf("Expected cached response %q, got %q", first, second)
+	}
+	if requests["/v4/info/"] != 1 {
+		t.Errorf("Expected 1 request before expiry, got %d", requests["/v4/info/"])
+	}
+
+	// Paths not cached are always requested.
+	get("/v4/clusters/")
+	get("/v4/clusters/")
+	if requests["/v4/clusters/"] != 2 {
+		t.Errorf("Expected 2 requests for uncached path, got %d", requests["/v4/clusters/"])
+	}
+
+	now = now.Add(2 * time.Hour)
+	third := get("/v4/info/")
+	if third != first {
+		t.Errorf("Expected revalidated response %q, got %q", first, third)
+	}
+	if notModified != 1 {
+		t.Errorf("Expected 1 revalidation, got %d", notModified)
+	}
+
+	// After revalidation, the response is valid again.
+	get("/v4/info/")
+	if requests["/v4/info/"] != 2 {
+		t.Errorf("Expected 2 requests after revalidation, got %d", requests["/v4/info/"])
+	}
+
+	exists, _ := afero.Exists(fs, "/config/"+responseCacheFileName)
+	if !exists {
+		t.Error("Expected cache file to exist")
+	}
+}
+
+// TestCacheTransportErrors checks that error responses are not cached.
+func TestCacheTransportErrors(t *testing.T) {
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	httpClient := &http.Client{Transport: setCache(http.DefaultTransport, afero.NewMemMapFs(), "/config")}
+	for i := 0; i < 2; i++ {
+		resp, err := httpClient.Get(mockServer.URL + "/v4/releases/")
+		if err != nil {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+// TestCacheTransportConcurrent checks that several transports sharing one
+// cache file can be used concurrently, as in creating several clusters at
+// once. Run with -race.
+func TestCacheTransportConcurrent(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	paths := []string{"/v4/info/", "/v4/releases/"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			httpClient := &http.Client{Transport: setCache(http.DefaultTransport, fs, "/config")}
+			resp, err := httpClient.Get(mockServer.URL + paths[i%len(paths)])
+			if err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+				return
+			}
+			resp.Body.Close()
+		}(i)
+	}
+	wg.Wait()
+
+	cache := setCache(http.DefaultTransport, fs, "/config").(*cacheTransport).read()
+	for _, p := range paths {
+		if cache.response(mockServer.URL, p) == nil {
+			t.Errorf("Expected response for %s to be cached", p)
+		}
+	}
+}
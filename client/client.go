@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/url"
@@ -64,6 +65,34 @@ type Configuration struct {
 
 	// ActivityName identifies the user action through the according header.
 	ActivityName string
+
+	// DryRunFormat, if set to "json" or "yaml", prevents requests which would
+	// modify data from being sent. Instead, the request body is printed in the
+	// given format and a response pretending success is returned.
+	DryRunFormat string
+
+	// Retries is the number of times a request failing for a temporary
+	// reason is repeated.
+	Retries int
+
+	// RetryLog, if set, receives a message for every retry.
+	RetryLog io.Writer
+
+	// TraceLog, if set, receives all requests and responses, with secrets
+	// redacted.
+	TraceLog io.Writer
+
+	// RecordDir, if set, is a directory in which all requests and responses
+	// are stored as fixture files, with secrets redacted.
+	RecordDir string
+
+	// ReplayDir, if set, is a directory with fixture files as created via
+	// RecordDir. Responses are served from these files, no requests are sent.
+	ReplayDir string
+
+	// CacheDir, if set, is a directory in which responses rarely changing,
+	// like the installation info, are cached.
+	CacheDir string
 }
 
 // Wrapper is the structure holding representing our latest API client.
@@ -119,7 +148,30 @@ func New(conf *Configuration) (*Wrapper, error) {
 		Proxy:           http.ProxyFromEnvironment,
 		TLSClientConfig: tlsConfig,
 	}
+	if conf.ReplayDir != "" {
+		transport.Transport, err = newReplayTransport(conf.ReplayDir)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	} else if conf.RecordDir != "" {
+		transport.Transport, err = setRecord(transport.Transport, conf.RecordDir)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+	if conf.TraceLog != nil {
+		transport.Transport = setTrace(transport.Transport, conf.TraceLog)
+	}
 	transport.Transport = setUserAgent(transport.Transport, conf.UserAgent)
+	if conf.Retries > 0 {
+		transport.Transport = setRetry(transport.Transport, conf.Retries, conf.RetryLog)
+	}
+	if conf.CacheDir != "" {
+		transport.Transport = setCache(transport.Transport, cacheFileSystem(), conf.CacheDir)
+	}
+	if conf.DryRunFormat != "" {
+		transport.Transport = setDryRun(transport.Transport, conf.DryRunFormat)
+	}
 
 	rawClient := &http.Client{
 		Transport: transport.Transport,
@@ -142,8 +194,22 @@ func NewWithConfig(endpointString, token string) (*Wrapper, error) {
 	ClientConfig := &Configuration{
 		AuthHeaderGetter: config.Config.AuthHeaderGetter(endpoint, token),
 		Endpoint:         endpoint,
-		Timeout:          20 * time.Second,
+		Timeout:          RequestTimeout,
 		UserAgent:        config.UserAgent(),
+		DryRunFormat:     DryRunFormat,
+		Retries:          Retries,
+		RecordDir:        RecordDir,
+		ReplayDir:        ReplayDir,
+	}
+	if Verbose {
+		ClientConfig.RetryLog = os.Stderr
+	}
+	if Trace {
+		ClientConfig.TraceLog = os.Stderr
+	}
+	// Recording and replaying need all requests to be sent.
+	if !NoCache && RecordDir == "" && ReplayDir == "" {
+		ClientConfig.CacheDir = config.ConfigDirPath
 	}
 
 	return New(ClientConfig)
@@ -283,6 +349,20 @@ func setParams(p *AuxiliaryParams, w *Wrapper, params paramSetter) {
 	}
 }
 
+// newAPIError converts an error returned by gsclientgen into an APIError,
+// adding the request ID used according to setParams.
+func newAPIError(err error, p *AuxiliaryParams, w *Wrapper) *clienterror.APIError {
+	apiErr := clienterror.New(err)
+
+	if p != nil && p.RequestID != "" {
+		apiErr.RequestID = p.RequestID
+	} else if w != nil {
+		apiErr.RequestID = w.requestID
+	}
+
+	return apiErr
+}
+
 func getAuthorization(w *Wrapper) (runtime.ClientAuthInfoWriter, error) {
 	authHeader, err := w.conf.AuthHeaderGetter()
 	if err != nil {
@@ -302,7 +382,7 @@ func (w *Wrapper) CreateAuthToken(email, password string, p *AuxiliaryParams) (*
 
 	response, err := w.gsclient.AuthTokens.CreateAuthToken(params, nil)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -316,7 +396,7 @@ func (w *Wrapper) DeleteAuthToken(authToken string, p *AuxiliaryParams) (*auth_t
 
 	response, err := w.gsclient.AuthTokens.DeleteAuthToken(params, httptransport.APIKeyAuth("Authorization", "header", "giantswarm "+authToken))
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -334,7 +414,7 @@ func (w *Wrapper) CreateClusterV4(addClusterRequest *models.V4AddClusterRequest,
 
 	response, err := w.gsclient.Clusters.AddCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -352,7 +432,7 @@ func (w *Wrapper) CreateClusterV5(addClusterRequest *models.V5AddClusterRequest,
 
 	response, err := w.gsclient.Clusters.AddClusterV5(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -370,7 +450,7 @@ func (w *Wrapper) ModifyClusterV4(clusterID string, body *models.V4ModifyCluster
 
 	response, err := w.gsclient.Clusters.ModifyCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -388,7 +468,7 @@ func (w *Wrapper) ModifyClusterV5(clusterID string, body *models.V5ModifyCluster
 
 	response, err := w.gsclient.Clusters.ModifyClusterV5(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -406,7 +486,7 @@ func (w *Wrapper) DeleteCluster(clusterID string, p *AuxiliaryParams) (*clusters
 
 	response, err := w.gsclient.Clusters.DeleteCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -424,7 +504,7 @@ func (w *Wrapper) GetClusters(p *AuxiliaryParams) (*clusters.GetClustersOK, erro
 
 	response, err := w.gsclient.Clusters.GetClusters(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -442,7 +522,7 @@ func (w *Wrapper) GetClusterV4(clusterID string, p *AuxiliaryParams) (*clusters.
 
 	response, err := w.gsclient.Clusters.GetCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -460,7 +540,7 @@ func (w *Wrapper) GetClusterV5(clusterID string, p *AuxiliaryParams) (*clusters.
 
 	response, err := w.gsclient.Clusters.GetClusterV5(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -478,7 +558,7 @@ func (w *Wrapper) CreateNodePool(clusterID string, addNodePoolRequest *models.V5
 
 	response, err := w.gsclient.NodePools.AddNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -496,7 +576,7 @@ func (w *Wrapper) GetNodePool(clusterID, nodePoolID string, p *AuxiliaryParams)
 
 	response, err := w.gsclient.NodePools.GetNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -514,7 +594,7 @@ func (w *Wrapper) GetNodePools(clusterID string, p *AuxiliaryParams) (*node_pool
 
 	response, err := w.gsclient.NodePools.GetNodePools(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -532,7 +612,7 @@ func (w *Wrapper) ModifyNodePool(clusterID, nodePoolID string, modifyNodePoolReq
 
 	response, err := w.gsclient.NodePools.ModifyNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -550,7 +630,7 @@ func (w *Wrapper) DeleteNodePool(clusterID, nodePoolID string, p *AuxiliaryParam
 
 	response, err := w.gsclient.NodePools.DeleteNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -569,7 +649,7 @@ func (w *Wrapper) GetDefaultCluster(p *AuxiliaryParams) (string, error) {
 
 	response, err := w.gsclient.Clusters.GetClusters(params, authWriter)
 	if err != nil {
-		return "", clienterror.New(err)
+		return "", newAPIError(err, p, w)
 	}
 
 	if len(response.Payload) == 1 {
@@ -591,7 +671,7 @@ func (w *Wrapper) CreateKeyPair(clusterID string, addKeyPairRequest *models.V4Ad
 
 	response, err := w.gsclient.KeyPairs.AddKeyPair(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -609,7 +689,7 @@ func (w *Wrapper) GetKeyPairs(clusterID string, p *AuxiliaryParams) (*key_pairs.
 
 	response, err := w.gsclient.KeyPairs.GetKeyPairs(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -627,7 +707,7 @@ func (w *Wrapper) GetInfo(p *AuxiliaryParams) (*info.GetInfoOK, error) {
 
 	response, err := w.gsclient.Info.GetInfo(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -645,7 +725,7 @@ func (w *Wrapper) GetReleases(p *AuxiliaryParams) (*releases.GetReleasesOK, erro
 
 	response, err := w.gsclient.Releases.GetReleases(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -663,7 +743,7 @@ func (w *Wrapper) GetOrganizations(p *AuxiliaryParams) (*organizations.GetOrgani
 
 	response, err := w.gsclient.Organizations.GetOrganizations(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -681,7 +761,7 @@ func (w *Wrapper) GetCredential(organizationID string, credentialID string, p *A
 
 	response, err := w.gsclient.Organizations.GetCredential(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -699,7 +779,7 @@ func (w *Wrapper) SetCredentials(organizationID string, addCredentialsRequest *m
 
 	response, err := w.gsclient.Organizations.AddCredentials(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -717,7 +797,7 @@ func (w *Wrapper) GetClusterStatus(clusterID string, p *AuxiliaryParams) (*Clust
 
 	response, err := w.gsclient.Clusters.GetClusterStatus(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	m, err := json.Marshal(response.Payload)
@@ -749,7 +829,25 @@ func (w *Wrapper) CreateApp(clusterID string, appName string, addAppRequest *mod
 
 	response, err := w.gsclient.Apps.CreateClusterAppV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
+	}
+
+	return response, nil
+}
+
+// GetApps fetches all apps installed in a cluster using the gsclientgen client.
+func (w *Wrapper) GetApps(clusterID string, p *AuxiliaryParams) (*apps.GetClusterAppsV4OK, error) {
+	params := apps.NewGetClusterAppsV4Params().WithClusterID(clusterID)
+	setParams(p, w, params)
+
+	authWriter, err := getAuthorization(w)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	response, err := w.gsclient.Apps.GetClusterAppsV4(params, authWriter)
+	if err != nil {
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -769,7 +867,7 @@ func (w *Wrapper) GetApp(clusterID string, appName string, p *AuxiliaryParams) (
 
 	response, err := w.gsclient.Apps.GetClusterAppsV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	apps := response.Payload
@@ -797,7 +895,7 @@ func (w *Wrapper) GetAppStatus(clusterID string, appName string, p *AuxiliaryPar
 
 	response, err := w.gsclient.Apps.GetClusterAppsV4(params, authWriter)
 	if err != nil {
-		return "", clienterror.New(err)
+		return "", newAPIError(err, p, w)
 	}
 
 	// type V4GetClusterAppsResponse []*V4GetClusterAppsResponseItems
@@ -824,7 +922,7 @@ func (w *Wrapper) DeleteApp(clusterID string, appName string, p *AuxiliaryParams
 
 	response, err := w.gsclient.Apps.DeleteClusterAppV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -843,7 +941,7 @@ func (w *Wrapper) ModifyApp(clusterID string, appName string, body *models.V4Mod
 
 	response, err := w.gsclient.Apps.ModifyClusterAppV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -861,7 +959,7 @@ func (w *Wrapper) UpdateClusterLabels(clusterID string, body *models.V5SetCluste
 
 	response, err := w.gsclient.ClusterLabels.SetClusterLabels(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	return response, nil
@@ -879,7 +977,7 @@ func (w *Wrapper) GetClustersByLabel(body *models.V5ListClustersByLabelRequest,
 
 	response, err := w.gsclient.Clusters.GetV5ClustersByLabel(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, newAPIError(err, p, w)
 	}
 
 	// wrap this into a GetClustersOK to be compatible with GetClusters
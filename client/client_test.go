@@ -258,6 +258,38 @@ func TestUnauthorized(t *testing.T) { // Our test server.
 	}
 }
 
+// TestErrorRequestID tests that API errors carry the request ID sent, to be
+// shown in error output.
+func TestErrorRequestID(t *testing.T) {
+	var requestID string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get("X-Request-ID")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found"}`))
+	}))
+	defer ts.Close()
+
+	gsClient, err := New(&Configuration{Endpoint: ts.URL})
+	if err != nil {
+		t.Error(err)
+	}
+
+	ap := gsClient.DefaultAuxiliaryParams()
+	_, err = gsClient.GetClusterV4("cluster-id", ap)
+
+	clientAPIError, ok := err.(*clienterror.APIError)
+	if !ok {
+		t.Fatalf("Expected *clienterror.APIError, got %#v", err)
+	}
+	if requestID == "" || clientAPIError.RequestID != requestID {
+		t.Errorf("Expected request ID %q, got %q", requestID, clientAPIError.RequestID)
+	}
+	if clientAPIError.RequestID != ap.RequestID {
+		t.Errorf("Expected request ID %q from auxiliary params, got %q", ap.RequestID, clientAPIError.RequestID)
+	}
+}
+
 // TestAuxiliaryParams checks whether the client carries through our auxiliary
 // parameters.
 func TestAuxiliaryParams(t *testing.T) { // Our test server.
@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-openapi/runtime"
 
+	"github.com/giantswarm/gsclientgen/v2/client/apps"
 	"github.com/giantswarm/gsclientgen/v2/client/auth_tokens"
 	"github.com/giantswarm/gsclientgen/v2/client/clusters"
 	"github.com/giantswarm/gsclientgen/v2/client/info"
@@ -44,6 +45,10 @@ type APIError struct {
 	// HTTPMethod is the HTTP method used.
 	HTTPMethod string
 
+	// RequestID is the ID sent with the request in the X-Request-ID header,
+	// which helps support to find the request in the API logs.
+	RequestID string
+
 	// IsTimeout will be true if our error was a timeout error.
 	IsTimeout bool
 
@@ -574,6 +579,118 @@ func New(err error) *APIError {
 		}
 	}
 
+	// create app
+	if myerr, ok := err.(*apps.CreateClusterAppV4BadRequest); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusBadRequest,
+			OriginalError:  myerr,
+			ErrorMessage:   "Bad request",
+			ErrorDetails:   myerr.Payload.Message,
+		}
+	}
+	if myerr, ok := err.(*apps.CreateClusterAppV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  myerr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to install apps in this cluster.",
+		}
+	}
+	if myerr, ok := err.(*apps.CreateClusterAppV4Conflict); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusConflict,
+			OriginalError:  myerr,
+			ErrorMessage:   "App already exists",
+			ErrorDetails:   "An app with this name is already installed in this cluster.",
+		}
+	}
+	if myerr, ok := err.(*apps.CreateClusterAppV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: myerr.Code(),
+			OriginalError:  myerr,
+			ErrorMessage:   myerr.Error(),
+			ErrorDetails:   myerr.Payload.Message,
+		}
+	}
+
+	// get apps
+	if myerr, ok := err.(*apps.GetClusterAppsV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  myerr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to list apps in this cluster.",
+		}
+	}
+	if myerr, ok := err.(*apps.GetClusterAppsV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: myerr.Code(),
+			OriginalError:  myerr,
+			ErrorMessage:   myerr.Error(),
+			ErrorDetails:   myerr.Payload.Message,
+		}
+	}
+
+	// modify app
+	if myerr, ok := err.(*apps.ModifyClusterAppV4BadRequest); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusBadRequest,
+			OriginalError:  myerr,
+			ErrorMessage:   "Bad request",
+			ErrorDetails:   myerr.Payload.Message,
+		}
+	}
+	if myerr, ok := err.(*apps.ModifyClusterAppV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  myerr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to modify apps in this cluster.",
+		}
+	}
+	if myerr, ok := err.(*apps.ModifyClusterAppV4NotFound); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusNotFound,
+			OriginalError:  myerr,
+			ErrorMessage:   "Not found",
+			ErrorDetails:   "The cluster or app was not found or you don't have access to it.",
+		}
+	}
+	if myerr, ok := err.(*apps.ModifyClusterAppV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: myerr.Code(),
+			OriginalError:  myerr,
+			ErrorMessage:   myerr.Error(),
+			ErrorDetails:   myerr.Payload.Message,
+		}
+	}
+
+	// delete app
+	if myerr, ok := err.(*apps.DeleteClusterAppV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  myerr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to delete apps in this cluster.",
+		}
+	}
+	if myerr, ok := err.(*apps.DeleteClusterAppV4NotFound); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusNotFound,
+			OriginalError:  myerr,
+			ErrorMessage:   "Not found",
+			ErrorDetails:   "The cluster or app was not found or you don't have access to it.",
+		}
+	}
+	if myerr, ok := err.(*apps.DeleteClusterAppV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: myerr.Code(),
+			OriginalError:  myerr,
+			ErrorMessage:   myerr.Error(),
+			ErrorDetails:   myerr.Payload.Message,
+		}
+	}
+
 	// HTTP level error cases
 	if runtimeAPIError, ok := err.(*runtime.APIError); ok {
 		ae := &APIError{
@@ -707,6 +824,11 @@ func New(err error) *APIError {
 			return ae
 		}
 
+		// Any other error, e. g. from replaying recorded responses.
+		if urlError.Err != nil {
+			ae.ErrorMessage = urlError.Err.Error()
+		}
+
 		return ae
 	}
 
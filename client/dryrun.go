@@ -0,0 +1,190 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/formatting"
+)
+
+// DryRunFormat is used as the Configuration.DryRunFormat of clients
+// created via NewWithConfig. It is set via the global --dry-run flag.
+var DryRunFormat string
+
+// DryRunOutput receives the request bodies printed in dry run mode. If nil,
+// they are printed to stdout.
+var DryRunOutput io.Writer
+
+// dryRunID is the ID of resources pretended to be created in dry run mode,
+// so that commands can continue with requests referring to them.
+const dryRunID = "dryrun"
+
+// dryRunTransport is a http.RoundTripper which sends requests reading data
+// as usual, but prints the body of requests modifying data instead of
+// sending them. A response pretending success is returned, so that commands
+// sending several requests print all of them.
+type dryRunTransport struct {
+	inner  http.RoundTripper
+	format string
+
+	// out receives the request body, info receives additional information.
+	out  io.Writer
+	info io.Writer
+}
+
+// setDryRun wraps a transport so that no modifying requests are sent.
+func setDryRun(inner http.RoundTripper, format string) http.RoundTripper {
+	out := DryRunOutput
+	if out == nil {
+		out = os.Stdout
+	}
+
+	return &dryRunTransport{
+		inner:  inner,
+		format: format,
+		out:    out,
+		info:   os.Stderr,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *dryRunTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !isModifyingRequest(r) {
+		return t.inner.RoundTrip(r)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	formatted, err := formatDryRunBody(body, t.format)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	fmt.Fprintf(t.info, "Dry run, not sending request %s %s\n", r.Method, r.URL.Path)
+	if formatted != "" {
+		if t.format == formatting.OutputFormatYAML {
+			// Separate the bodies of several requests as YAML documents.
+			fmt.Fprintln(t.out, "---")
+		}
+		fmt.Fprintln(t.out, formatted)
+	}
+
+	return dryRunResponse(r, body), nil
+}
+
+// dryRunResponse returns a response pretending that a modifying request
+// has been successful, with the status code the API would return. Requests
+// creating something get a body with an 'id' and a Location header, as
+// commands use both to get the ID.
+func dryRunResponse(r *http.Request, requestBody []byte) *http.Response {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	status := http.StatusOK
+	body := []byte("{}")
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch r.Method {
+	case http.MethodPost:
+		// Key pairs are the only resources created with status 200.
+		if segments[len(segments)-1] != "key-pairs" {
+			status = http.StatusCreated
+		}
+		header.Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+dryRunID+"/")
+
+		// Request and response schemas differ, so only plain strings like
+		// the name and owner are echoed.
+		object := map[string]interface{}{"id": dryRunID}
+		var requestObject map[string]interface{}
+		if json.Unmarshal(requestBody, &requestObject) == nil {
+			for key, value := range requestObject {
+				if s, ok := value.(string); ok {
+					object[key] = s
+				}
+			}
+		}
+		body, _ = json.Marshal(object)
+
+	case http.MethodDelete:
+		// Deleting clusters and node pools takes a while, so the API
+		// only accepts the request.
+		if len(segments) >= 2 && (segments[len(segments)-2] == "clusters" || segments[len(segments)-2] == "nodepools") {
+			status = http.StatusAccepted
+		}
+		body = []byte(`{"code": "RESOURCE_DELETION_STARTED", "message": "Not deleted due to dry run mode."}`)
+
+	default:
+		if len(bytes.TrimSpace(requestBody)) > 0 && json.Valid(requestBody) {
+			body = requestBody
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
+
+// isModifyingRequest returns true if the request would modify data.
+// Requests creating or deleting auth tokens (login/logout) are exempt.
+func isModifyingRequest(r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+		return false
+	}
+	// Listing clusters by label uses POST, but doesn't modify anything.
+	if strings.HasSuffix(r.URL.Path, "/v5/clusters/by_label/") {
+		return false
+	}
+	if strings.HasPrefix(r.URL.Path, "/v4/auth-tokens/") {
+		return false
+	}
+
+	return true
+}
+
+// formatDryRunBody formats a JSON request body as indented JSON or as YAML.
+func formatDryRunBody(body []byte, format string) (string, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return "", nil
+	}
+
+	if format == formatting.OutputFormatYAML {
+		yamlBytes, err := formatting.JSONToYAML(body)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return string(yamlBytes), nil
+	}
+
+	var indented bytes.Buffer
+	err := json.Indent(&indented, bytes.TrimSpace(body), formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return indented.String(), nil
+}
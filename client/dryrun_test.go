@@ -0,0 +1,118 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/gsctl/formatting"
+)
+
+// TestFormatDryRunBody tests formatting request bodies as JSON and YAML.
+func TestFormatDryRunBody(t *testing.T) {
+	var testCases = []struct {
+		body     string
+		format   string
+		expected string
+	}{
+		{"", formatting.OutputFormatJSON, ""},
+		{`{"name":"foo"}`, formatting.OutputFormatJSON, "{\n  \"name\": \"foo\"\n}"},
+		{`{"name":"foo","workers":[{"id":"a"}]}`, formatting.OutputFormatYAML, "name: foo\nworkers:\n- id: a"},
+	}
+
+	for i, tc := range testCases {
+		out, err := formatDryRunBody([]byte(tc.body), tc.format)
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %#v", i, err)
+		}
+		if out != tc.expected {
+			t.Errorf("Case %d - Expected %q, got %q", i, tc.expected, out)
+		}
+	}
+}
+
+// TestDryRunTransport checks that requests reading data are sent, while
+// requests modifying data are printed instead, and answered with a response
+// pretending success.
+func TestDryRunTransport(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer mockServer.Close()
+
+	var out, info bytes.Buffer
+	transport := &dryRunTransport{
+		inner:  http.DefaultTransport,
+		format: formatting.OutputFormatJSON,
+		out:    &out,
+		info:   &info,
+	}
+	httpClient := &http.Client{Transport: transport}
+
+	// Reading requests must be passed through.
+	_, err := httpClient.Get(mockServer.URL + "/v4/clusters/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	_, err = httpClient.Post(mockServer.URL+"/v5/clusters/by_label/", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	// Modifying requests must be printed, not sent.
+	response, err := httpClient.Post(mockServer.URL+"/v5/clusters/", "application/json", strings.NewReader(`{"name":"foo"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if response.StatusCode != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, response.StatusCode)
+	}
+	if response.Header.Get("Location") != "/v5/clusters/dryrun/" {
+		t.Errorf("Unexpected Location header: %q", response.Header.Get("Location"))
+	}
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	if string(responseBody) != `{"id":"dryrun","name":"foo"}` {
+		t.Errorf("Unexpected response body: %q", string(responseBody))
+	}
+
+	// Requests following the first modifying one must be printed, too.
+	request, _ := http.NewRequest(http.MethodPut, mockServer.URL+"/v5/clusters/dryrun/labels/", strings.NewReader(`{"labels":{"a":"b"}}`))
+	response, err = httpClient.Do(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	request, _ = http.NewRequest(http.MethodDelete, mockServer.URL+"/v4/clusters/dryrun/", nil)
+	response, err = httpClient.Do(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if response.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected status %d, got %d", http.StatusAccepted, response.StatusCode)
+	}
+
+	expectedOut := "{\n  \"name\": \"foo\"\n}\n{\n  \"labels\": {\n    \"a\": \"b\"\n  }\n}\n"
+	if out.String() != expectedOut {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+	for _, expected := range []string{"POST /v5/clusters/", "PUT /v5/clusters/dryrun/labels/", "DELETE /v4/clusters/dryrun/"} {
+		if !strings.Contains(info.String(), expected) {
+			t.Errorf("Expected info output to contain %q, got %q", expected, info.String())
+		}
+	}
+
+	expectedRequests := []string{"GET /v4/clusters/", "POST /v5/clusters/by_label/"}
+	if strings.Join(requests, ",") != strings.Join(expectedRequests, ",") {
+		t.Errorf("Expected requests %v, got %v", expectedRequests, requests)
+	}
+}
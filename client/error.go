@@ -9,8 +9,15 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
 )
 
+// OutputFormat is the output format of the command. With 'json' or
+// 'yaml', HandleErrors leaves the errors to errors.HandleCommonErrors. It is
+// set via the --output flag of the commands.
+var OutputFormat string
+
 // clientNotInitializedError is used when the new client hasn't been initialized.
 var clientNotInitializedError = &microerror.Error{
 	Kind: "clientNotInitializedError",
@@ -63,20 +70,29 @@ func IsParseError(err error) bool {
 
 // HandleErrors handles the errors known to this package.
 // Handling normally means printing a user-readable error message
-// and exiting with code 1. If the given error is not recognized,
-// the function returns without action.
+// and exiting with the exit code of the error class. If the given error
+// is not recognized, the function returns without action.
+//
+// With '--output json' or '--output yaml', the function returns without
+// action as well, as errors are then printed as objects by
+// errors.HandleCommonErrors.
 func HandleErrors(err error) {
+	if OutputFormat == formatting.OutputFormatJSON || OutputFormat == formatting.OutputFormatYAML {
+		return
+	}
 
 	var headline = ""
 	var subtext = ""
 	var httpStatusCode int
 	var message string
 	var details string
+	code := exitcode.Error
 
 	if convertedErr, ok := microerror.Cause(err).(*clienterror.APIError); ok {
 		httpStatusCode = convertedErr.HTTPStatusCode
 		message = convertedErr.ErrorMessage
 		details = convertedErr.ErrorDetails
+		code = exitcode.FromHTTPStatus(httpStatusCode)
 
 		if clienterror.IsMalformedResponse(err) {
 			message = "Malformed response - No API access?"
@@ -89,8 +105,10 @@ func HandleErrors(err error) {
 		httpStatusCode = convertedErr.HTTPStatusCode
 		message = convertedErr.ErrorMessage
 		details = convertedErr.ErrorDetails
+		code = exitcode.FromHTTPStatus(httpStatusCode)
 	} else if IsEndpointNotSpecifiedError(err) {
 		// legacy client error handling
+		code = exitcode.Validation
 		headline = "No endpoint has been specified."
 		subtext = "Please use the '-e|--endpoint' flag or select an endpoint using 'gsctl select endpoint'."
 	}
@@ -116,5 +134,16 @@ func HandleErrors(err error) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(code)
+}
+
+// fixtureNotFoundError is returned when replaying recorded API interactions
+// and there is no fixture for a request.
+var fixtureNotFoundError = &microerror.Error{
+	Kind: "fixtureNotFoundError",
+}
+
+// IsFixtureNotFoundError asserts fixtureNotFoundError.
+func IsFixtureNotFoundError(err error) bool {
+	return microerror.Cause(err) == fixtureNotFoundError
 }
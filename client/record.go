@@ -0,0 +1,285 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/formatting"
+)
+
+var (
+	// RecordDir is used as the Configuration.RecordDir of clients created
+	// via NewWithConfig. It is set via the global --record flag.
+	RecordDir string
+
+	// ReplayDir is used as the Configuration.ReplayDir of clients created
+	// via NewWithConfig. It is set via the global --replay flag.
+	ReplayDir string
+
+	fixtureNameUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9_.]+`)
+)
+
+// fixture is one API interaction, as stored in a file when recording and
+// served when replaying. Secrets are redacted the same way as in traces.
+type fixture struct {
+	Request  fixtureRequest  `json:"request"`
+	Response fixtureResponse `json:"response"`
+}
+
+type fixtureRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type fixtureResponse struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+// recordTransport is a http.RoundTripper which stores every request and
+// response as a fixture file in a directory.
+type recordTransport struct {
+	inner http.RoundTripper
+	dir   string
+
+	mutex sync.Mutex
+	// count is the number of fixtures in the directory.
+	count int
+}
+
+// setRecord wraps a transport so that all interactions are stored in the
+// given directory. Fixtures already present are kept and new ones are
+// numbered after them.
+func setRecord(inner http.RoundTripper, dir string) (http.RoundTripper, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &recordTransport{
+		inner: inner,
+		dir:   dir,
+		count: len(files),
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *recordTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		requestBody, err = ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	response, err := t.inner.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	f := fixture{
+		Request: fixtureRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Body:   encodeFixtureBody(requestBody),
+		},
+		Response: fixtureResponse{
+			StatusCode: response.StatusCode,
+			Header:     http.Header{},
+			Body:       encodeFixtureBody(responseBody),
+		},
+	}
+	for name, values := range response.Header {
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization", "Set-Cookie", "Date", "Content-Length":
+			continue
+		}
+		f.Response.Header[name] = values
+	}
+
+	err = t.write(f)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return response, nil
+}
+
+// write stores a fixture in a new file, named after its position and the
+// request, like '0001-GET-v4-clusters.json'.
+func (t *recordTransport) write(f fixture) error {
+	data, err := json.MarshalIndent(f, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.count++
+	name := fmt.Sprintf("%04d-%s-%s.json", t.count, f.Request.Method, strings.Trim(fixtureNameUnsafeChars.ReplaceAllString(f.Request.Path, "-"), "-"))
+
+	err = ioutil.WriteFile(filepath.Join(t.dir, name), append(data, '\n'), 0600)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// replayTransport is a http.RoundTripper which serves responses from
+// fixture files instead of sending requests.
+type replayTransport struct {
+	mutex sync.Mutex
+	// fixtures are grouped by request, in the order of their files.
+	fixtures map[string][]fixture
+	// served counts how often a response has been served per request.
+	served map[string]int
+}
+
+// newReplayTransport loads all fixtures from a directory.
+func newReplayTransport(dir string) (http.RoundTripper, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if len(files) == 0 {
+		return nil, microerror.Maskf(fixtureNotFoundError, "no fixture files found in directory %s", dir)
+	}
+	sort.Strings(files)
+
+	t := &replayTransport{
+		fixtures: map[string][]fixture{},
+		served:   map[string]int{},
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		var f fixture
+		err = json.Unmarshal(data, &f)
+		if err != nil {
+			return nil, microerror.Maskf(fixtureNotFoundError, "file %s is not a valid fixture: %s", file, err.Error())
+		}
+
+		key := fixtureKey(f.Request.Method, f.Request.Path, f.Request.Query)
+		t.fixtures[key] = append(t.fixtures[key], f)
+	}
+
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper. Responses recorded for the same
+// request are served in the recorded order. Once all have been served,
+// the last one is repeated, e. g. when polling.
+func (t *replayTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		r.Body.Close()
+	}
+
+	key := fixtureKey(r.Method, r.URL.Path, r.URL.RawQuery)
+
+	t.mutex.Lock()
+	fixtures := t.fixtures[key]
+	index := t.served[key]
+	t.served[key]++
+	t.mutex.Unlock()
+
+	if len(fixtures) == 0 {
+		return nil, microerror.Maskf(fixtureNotFoundError, "no recorded response for %s", key)
+	}
+	if index >= len(fixtures) {
+		index = len(fixtures) - 1
+	}
+	f := fixtures[index]
+
+	body := decodeFixtureBody(f.Response.Body)
+	header := http.Header{}
+	for name, values := range f.Response.Header {
+		header[name] = values
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.StatusCode, http.StatusText(f.Response.StatusCode)),
+		StatusCode:    f.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}, nil
+}
+
+// fixtureKey identifies the request a fixture belongs to.
+func fixtureKey(method, path, query string) string {
+	key := method + " " + path
+	if query != "" {
+		key += "?" + query
+	}
+
+	return key
+}
+
+// encodeFixtureBody redacts secrets from a body and returns it as JSON.
+// Bodies which are not JSON are stored as a string.
+func encodeFixtureBody(body []byte) json.RawMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+
+	redactedBody := redactBody(body)
+	if json.Valid([]byte(redactedBody)) && !strings.HasPrefix(redactedBody, `"`) {
+		return json.RawMessage(redactedBody)
+	}
+
+	quoted, _ := json.Marshal(redactedBody)
+	return quoted
+}
+
+// decodeFixtureBody is the reverse of encodeFixtureBody.
+func decodeFixtureBody(body json.RawMessage) []byte {
+	if len(body) > 0 && body[0] == '"' {
+		var text string
+		if err := json.Unmarshal(body, &text); err == nil {
+			return []byte(text)
+		}
+	}
+
+	return body
+}
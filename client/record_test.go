@@ -0,0 +1,146 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRecordAndReplay records API interactions and replays them without
+// the server being available.
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gsctl-record")
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"general": {"installation_name": "codename", "provider": "aws"}}`))
+		case "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "Cluster number one", "owner": "acme"}]`))
+		case "/v4/clusters/f01r4/key-pairs/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "ab:cd", "certificate_authority_data": "CA", "client_certificate_data": "CERT", "client_key_data": "PRIVATE KEY"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found"}`))
+		}
+	}))
+
+	recordingClient, err := New(&Configuration{
+		Endpoint:         mockServer.URL,
+		AuthHeaderGetter: func() (string, error) { return "giantswarm secret-token", nil },
+		RecordDir:        dir,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	_, err = recordingClient.GetInfo(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	_, err = recordingClient.GetClusters(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	_, err = recordingClient.CreateKeyPair("f01r4", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	_, err = recordingClient.GetClusterV4("unknown", nil)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	mockServer.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 4 {
+		t.Fatalf("Expected 4 fixture files, got %v", files)
+	}
+	if filepath.Base(files[0]) != "0001-GET-v4-info.json" {
+		t.Errorf("Expected file name 0001-GET-v4-info.json, got %s", filepath.Base(files[0]))
+	}
+	for _, file := range files {
+		data, _ := ioutil.ReadFile(file)
+		if strings.Contains(string(data), "secret-token") || strings.Contains(string(data), "PRIVATE KEY") {
+			t.Errorf("Expected secrets to be redacted in %s, got\n%s", file, string(data))
+		}
+	}
+
+	replayingClient, err := New(&Configuration{
+		Endpoint:  "https://api.example.com",
+		ReplayDir: dir,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	// Responses can be served more than once.
+	for i := 0; i < 2; i++ {
+		clusters, err := replayingClient.GetClusters(nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if len(clusters.Payload) != 1 || clusters.Payload[0].Name != "Cluster number one" {
+			t.Errorf("Expected recorded cluster, got %#v", clusters.Payload)
+		}
+	}
+
+	keyPair, err := replayingClient.CreateKeyPair("f01r4", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if keyPair.Payload.ClientKeyData != "REDACTED" || keyPair.Payload.ClientCertificateData != "CERT" {
+		t.Errorf("Expected recorded key pair with redacted key, got %#v", keyPair.Payload)
+	}
+
+	_, err = replayingClient.GetClusterV4("unknown", nil)
+	if err == nil {
+		t.Error("Expected recorded error, got nil")
+	} else if IsFixtureNotFoundError(err) {
+		t.Errorf("Expected recorded error, got %#v", err)
+	}
+
+	// Requests which have not been recorded fail.
+	_, err = replayingClient.GetClusterV4("f01r4", nil)
+	if err == nil {
+		t.Error("Expected error, got nil")
+	} else if !strings.Contains(err.Error(), "no recorded response for GET /v4/clusters/f01r4/") {
+		t.Errorf("Expected missing fixture error, got %#v", err)
+	}
+
+	if requests != 4 {
+		t.Errorf("Expected 4 requests to the server, got %d", requests)
+	}
+}
+
+// TestReplayEmptyDir checks that replaying from a directory without
+// fixtures fails early.
+func TestReplayEmptyDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gsctl-replay")
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = New(&Configuration{
+		Endpoint:  "https://api.example.com",
+		ReplayDir: dir,
+	})
+	if !IsFixtureNotFoundError(err) {
+		t.Errorf("Expected fixtureNotFoundError, got %#v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// RequestTimeout is used as the Configuration.Timeout of clients created
+	// via NewWithConfig. It is set via the global --timeout flag.
+	RequestTimeout = 20 * time.Second
+
+	// Retries is used as the Configuration.Retries of clients created via
+	// NewWithConfig. It is set via the global --retries flag.
+	Retries int
+
+	// Verbose enables logging of retries to stderr for clients created via
+	// NewWithConfig. It is set via the global --verbose flag.
+	Verbose bool
+)
+
+const (
+	// retryBaseDelay is the delay before the first retry. It doubles with
+	// every further retry, up to retryMaxDelay.
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// retryTransport is a http.RoundTripper which repeats requests failing
+// for reasons which are likely temporary, waiting with exponential backoff
+// and jitter between attempts.
+type retryTransport struct {
+	inner   http.RoundTripper
+	retries int
+
+	baseDelay time.Duration
+	maxDelay  time.Duration
+
+	// log receives a line for every retry, if set.
+	log io.Writer
+}
+
+// setRetry wraps a transport so that requests are retried up to the given
+// number of times.
+func setRetry(inner http.RoundTripper, retries int, log io.Writer) http.RoundTripper {
+	return &retryTransport{
+		inner:     inner,
+		retries:   retries,
+		baseDelay: retryBaseDelay,
+		maxDelay:  retryMaxDelay,
+		log:       log,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// The body gets consumed by every attempt, so we need a way to restore it.
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		r.Body, _ = r.GetBody()
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := t.inner.RoundTrip(r)
+		if attempt >= t.retries || !shouldRetry(r, response, err) {
+			return response, err
+		}
+
+		delay := t.delay(attempt, response)
+		if t.log != nil {
+			reason := ""
+			if err != nil {
+				reason = err.Error()
+			} else {
+				reason = response.Status
+			}
+			fmt.Fprintf(t.log, "Request %s %s failed (%s), retrying in %s (%d/%d)\n", r.Method, r.URL.Path, reason, delay.Round(time.Millisecond), attempt+1, t.retries)
+		}
+
+		if response != nil {
+			// Drain the body so that the connection can be reused.
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		if err := sleep(r.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if r.GetBody != nil {
+			r.Body, err = r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// delay returns the time to wait before the next attempt. A Retry-After
+// header sent with the response takes precedence, within the maximum delay.
+func (t *retryTransport) delay(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			delay := time.Duration(seconds) * time.Second
+			if delay > t.maxDelay {
+				delay = t.maxDelay
+			}
+			return delay
+		}
+	}
+
+	delay := t.baseDelay << uint(attempt)
+	if delay <= 0 || delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+
+	// Wait between half and the full delay, so that clients failing at
+	// the same time don't retry at the same time.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// shouldRetry returns true if a request can be repeated after receiving
+// the given response or error. Requests reading data are retried on
+// network errors and on responses indicating a temporary problem. Requests
+// modifying data are only retried if the API signals that the request has
+// not been processed.
+func shouldRetry(r *http.Request, response *http.Response, err error) bool {
+	if r.Context().Err() != nil {
+		return false
+	}
+
+	idempotent := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+
+	if err != nil {
+		return idempotent
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+
+	return false
+}
+
+// sleep waits for the given duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRetryTransport checks which requests are retried on which responses.
+func TestRetryTransport(t *testing.T) {
+	var testCases = []struct {
+		method string
+		// statuses are returned by the server one after the other.
+		statuses         []int
+		expectedRequests int
+		expectedStatus   int
+	}{
+		{http.MethodGet, []int{http.StatusOK}, 1, http.StatusOK},
+		{http.MethodGet, []int{http.StatusBadGateway, http.StatusOK}, 2, http.StatusOK},
+		{http.MethodGet, []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusOK}, 3, http.StatusOK},
+		{http.MethodGet, []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, 3, http.StatusBadGateway},
+		{http.MethodGet, []int{http.StatusInternalServerError, http.StatusOK}, 1, http.StatusInternalServerError},
+		{http.MethodGet, []int{http.StatusNotFound, http.StatusOK}, 1, http.StatusNotFound},
+		{http.MethodPost, []int{http.StatusBadGateway, http.StatusCreated}, 1, http.StatusBadGateway},
+		{http.MethodPost, []int{http.StatusServiceUnavailable, http.StatusCreated}, 2, http.StatusCreated},
+		{http.MethodDelete, []int{http.StatusTooManyRequests, http.StatusAccepted}, 2, http.StatusAccepted},
+	}
+
+	for i, tc := range testCases {
+		var bodies []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			w.WriteHeader(tc.statuses[len(bodies)-1])
+		}))
+
+		var log bytes.Buffer
+		transport := &retryTransport{
+			inner:     http.DefaultTransport,
+			retries:   2,
+			baseDelay: time.Millisecond,
+			maxDelay:  5 * time.Millisecond,
+			log:       &log,
+		}
+		httpClient := &http.Client{Transport: transport}
+
+		req, err := http.NewRequest(tc.method, mockServer.URL+"/v4/clusters/", strings.NewReader(`{"name":"foo"}`))
+		if err != nil {
+			t.Fatalf("Case %d - Unexpected error: %#v", i, err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("Case %d - Unexpected error: %#v", i, err)
+		}
+		resp.Body.Close()
+		mockServer.Close()
+
+		if len(bodies) != tc.expectedRequests {
+			t.Errorf("Case %d - Expected %d requests, got %d", i, tc.expectedRequests, len(bodies))
+		}
+		if resp.StatusCode != tc.expectedStatus {
+			t.Errorf("Case %d - Expected status %d, got %d", i, tc.expectedStatus, resp.StatusCode)
+		}
+		for j, body := range bodies {
+			if body != `{"name":"foo"}` {
+				t.Errorf("Case %d - Request %d: expected the full body, got %q", i, j, body)
+			}
+		}
+		if retries := strings.Count(log.String(), "retrying"); retries != tc.expectedRequests-1 {
+			t.Errorf("Case %d - Expected %d retries to be logged, got %q", i, tc.expectedRequests-1, log.String())
+		}
+	}
+}
+
+// TestRetryDelay checks the backoff and the Retry-After header.
+func TestRetryDelay(t *testing.T) {
+	transport := &retryTransport{
+		baseDelay: 100 * time.Millisecond,
+		maxDelay:  time.Second,
+	}
+
+	for attempt, maxDelay := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		delay := transport.delay(attempt, nil)
+		if delay < maxDelay/2 || delay > maxDelay {
+			t.Errorf("Attempt %d - Expected delay between %s and %s, got %s", attempt, maxDelay/2, maxDelay, delay)
+		}
+	}
+
+	response := &http.Response{Header: http.Header{}}
+	response.Header.Set("Retry-After", "0")
+	if delay := transport.delay(3, response); delay != 0 {
+		t.Errorf("Expected delay from Retry-After header, got %s", delay)
+	}
+	response.Header.Set("Retry-After", "120")
+	if delay := transport.delay(0, response); delay != time.Second {
+		t.Errorf("Expected maximum delay, got %s", delay)
+	}
+}
+
+// TestClientRetries checks that the client retries requests according to
+// the configuration.
+func TestClientRetries(t *testing.T) {
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code": "INTERNAL_ERROR", "message": "Try again"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"general": {"installation_name": "codename"}}`))
+	}))
+	defer mockServer.Close()
+
+	gsClient, err := New(&Configuration{
+		Endpoint: mockServer.URL,
+		Retries:  1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	_, err = gsClient.GetInfo(nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
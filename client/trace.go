@@ -0,0 +1,176 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Trace enables logging the HTTP traffic of clients created via
+// NewWithConfig. It is set via the global --trace flag.
+var Trace bool
+
+const redacted = "REDACTED"
+
+// traceTransport is a http.RoundTripper which logs every request and
+// response, including headers and bodies. Secrets are redacted, so that
+// the output can be shared, e. g. in support requests.
+type traceTransport struct {
+	inner http.RoundTripper
+	log   io.Writer
+
+	// now returns the current time, to measure latency.
+	now func() time.Time
+}
+
+// setTrace wraps a transport so that all traffic gets logged.
+func setTrace(inner http.RoundTripper, log io.Writer) http.RoundTripper {
+	return &traceTransport{
+		inner: inner,
+		log:   log,
+		now:   time.Now,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *traceTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		requestBody, err = ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "> %s %s\n", r.Method, r.URL.String())
+	writeTraceHeaders(&out, "> ", r.Header)
+	writeTraceBody(&out, "> ", requestBody)
+
+	start := t.now()
+	response, err := t.inner.RoundTrip(r)
+	latency := t.now().Sub(start).Round(time.Millisecond)
+
+	if err != nil {
+		fmt.Fprintf(&out, "< error after %s: %s\n\n", latency, err.Error())
+		fmt.Fprint(t.log, out.String())
+		return nil, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		fmt.Fprintf(&out, "< error reading response after %s: %s\n\n", latency, err.Error())
+		fmt.Fprint(t.log, out.String())
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	fmt.Fprintf(&out, "< %s (%s)\n", response.Status, latency)
+	writeTraceHeaders(&out, "< ", response.Header)
+	writeTraceBody(&out, "< ", responseBody)
+	out.WriteString("\n")
+	fmt.Fprint(t.log, out.String())
+
+	return response, nil
+}
+
+// writeTraceHeaders writes HTTP headers sorted by name, with secrets redacted.
+func writeTraceHeaders(out io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(out, "%s%s: %s\n", prefix, name, redactHeader(name, value))
+		}
+	}
+}
+
+// writeTraceBody writes a request or response body, with secrets redacted.
+func writeTraceBody(out io.Writer, prefix string, body []byte) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "%s\n%s%s\n", prefix, prefix, redactBody(body))
+}
+
+// redactHeader replaces the credentials in an authorization header with
+// "REDACTED", keeping the scheme like "Bearer".
+func redactHeader(name, value string) string {
+	if !strings.EqualFold(name, "Authorization") {
+		return value
+	}
+
+	parts := strings.SplitN(value, " ", 2)
+	if len(parts) == 2 {
+		return parts[0] + " " + redacted
+	}
+
+	return redacted
+}
+
+// redactBody replaces the values of secret fields in a JSON body with
+// "REDACTED". Bodies which are not JSON are returned as they are.
+func redactBody(body []byte) string {
+	// JSON fields to redact, wherever they appear.
+	fieldsToRedact := map[string]bool{
+		"auth_token":              true,
+		"password":                true,
+		"password_base64":         true,
+		"current_password_base64": true,
+		"new_password_base64":     true,
+		"secret":                  true,
+		"secret_key":              true,
+		"client_key_data":         true,
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var data interface{}
+	err := decoder.Decode(&data)
+	if err != nil {
+		return string(body)
+	}
+
+	var redact func(v interface{})
+	redact = func(v interface{}) {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			for key, item := range value {
+				if fieldsToRedact[strings.ToLower(key)] && item != nil {
+					value[key] = redacted
+					continue
+				}
+				redact(item)
+			}
+		case []interface{}:
+			for _, item := range value {
+				redact(item)
+			}
+		}
+	}
+	redact(data)
+
+	redactedBody, err := json.Marshal(data)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redactedBody)
+}
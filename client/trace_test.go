@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRedactBody tests redacting secrets from JSON bodies.
+func TestRedactBody(t *testing.T) {
+	var testCases = []struct {
+		body     string
+		expected string
+	}{
+		{
+			`{"email": "user@example.com", "password_base64": "c2VjcmV0"}`,
+			`{"email":"user@example.com","password_base64":"REDACTED"}`,
+		},
+		{
+			`{"provider": "azure", "azure": {"credential": {"client_id": "abc", "secret_key": "s3cr3t"}}}`,
+			`{"azure":{"credential":{"client_id":"abc","secret_key":"REDACTED"}},"provider":"azure"}`,
+		},
+		{
+			`{"id": "ab:cd", "certificate_authority_data": "CA", "client_certificate_data": "CERT", "client_key_data": "KEY", "ttl_hours": 24}`,
+			`{"certificate_authority_data":"CA","client_certificate_data":"CERT","client_key_data":"REDACTED","id":"ab:cd","ttl_hours":24}`,
+		},
+		{
+			`[{"auth_token": "t0k3n"}, {"id": "cluster"}]`,
+			`[{"auth_token":"REDACTED"},{"id":"cluster"}]`,
+		},
+		{
+			`not json`,
+			`not json`,
+		},
+	}
+
+	for i, tc := range testCases {
+		out := redactBody([]byte(tc.body))
+		if out != tc.expected {
+			t.Errorf("Case %d - Expected %s, got %s", i, tc.expected, out)
+		}
+	}
+}
+
+// TestTraceTransport checks that requests and responses are logged with
+// secrets redacted, and that bodies are still passed on.
+func TestTraceTransport(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"auth_token": "t0k3n"}`))
+	}))
+	defer mockServer.Close()
+
+	var log bytes.Buffer
+	httpClient := &http.Client{Transport: setTrace(http.DefaultTransport, &log)}
+
+	req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/v4/auth-tokens/", strings.NewReader(`{"email":"user@example.com","password_base64":"c2VjcmV0"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	req.Header.Set("Authorization", "giantswarm s3cr3t-t0k3n")
+	req.Header.Set("X-Request-ID", "abcdef")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	var body bytes.Buffer
+	body.ReadFrom(resp.Body)
+	resp.Body.Close()
+
+	if body.String() != `{"auth_token": "t0k3n"}` {
+		t.Errorf("Expected the original response body, got %q", body.String())
+	}
+
+	out := log.String()
+	for _, expected := range []string{
+		"> POST " + mockServer.URL + "/v4/auth-tokens/\n",
+		"> Authorization: giantswarm REDACTED\n",
+		"> X-Request-Id: abcdef\n",
+		`> {"email":"user@example.com","password_base64":"REDACTED"}`,
+		"< 201 Created (",
+		`< {"auth_token":"REDACTED"}`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected trace to contain %q, got\n%s", expected, out)
+		}
+	}
+	for _, secret := range []string{"s3cr3t", "c2VjcmV0", "t0k3n"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected secret %q to be redacted, got\n%s", secret, out)
+		}
+	}
+}
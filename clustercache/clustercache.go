@@ -13,6 +13,7 @@ import (
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/pkg/lockedfile"
 	"github.com/giantswarm/gsctl/util"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/afero"
@@ -24,7 +25,10 @@ const (
 	clusterCacheFileName     = "clustercache.yaml"
 
 	cacheDuration = time.Hour * 24 * 7 // 7 days.
-	timeLayout    = time.RFC3339
+	// nameCacheDuration is how long cached names are used to find cluster
+	// IDs. It is short, as clusters can be renamed.
+	nameCacheDuration = time.Minute * 5
+	timeLayout        = time.RFC3339
 )
 
 // EndpointCache stores the IDs stored in an
@@ -32,6 +36,20 @@ const (
 type EndpointCache struct {
 	Expiry string   `yaml:"expiry"`
 	IDs    []string `yaml:"ids"`
+	// Clusters contains the details needed to resolve cluster names
+	// without calling the API.
+	Clusters []Cluster `yaml:"clusters,omitempty"`
+	// Updated is the time the clusters have last been listed.
+	Updated string `yaml:"updated,omitempty"`
+}
+
+// Cluster stores the details of a cluster in the cache.
+type Cluster struct {
+	ID             string `yaml:"id"`
+	Name           string `yaml:"name,omitempty"`
+	Owner          string `yaml:"owner,omitempty"`
+	ReleaseVersion string `yaml:"release_version,omitempty"`
+	CreateDate     string `yaml:"create_date,omitempty"`
 }
 
 // Endpoints stores a map with the keys being API endpoints,
@@ -45,6 +63,8 @@ type Cache struct {
 
 // GetID gets the cluster ID for a provided name/ID
 // by checking in both the user cache and on the API.
+// Names are always looked up via the API, so that commands modifying
+// a cluster don't act on the wrong one after a rename.
 func GetID(endpoint string, clusterNameOrID string, clientWrapper *client.Wrapper) (string, error) {
 	// Check if the cluster ID is already in the cache,
 	// and skip the API request if it is.
@@ -53,6 +73,30 @@ func GetID(endpoint string, clusterNameOrID string, clientWrapper *client.Wrappe
 		return clusterNameOrID, nil
 	}
 
+	return getIDFromAPI(endpoint, clusterNameOrID, clientWrapper)
+}
+
+// GetIDCached works like GetID, but also skips the API request for a name
+// belonging to exactly one cluster, if the clusters have been listed within
+// the last few minutes. It must only be used by commands which don't modify
+// anything.
+func GetIDCached(endpoint string, clusterNameOrID string, clientWrapper *client.Wrapper) (string, error) {
+	isInCache := IsInCache(endpoint, clusterNameOrID)
+	if isInCache {
+		return clusterNameOrID, nil
+	}
+
+	id, found := idForName(endpoint, clusterNameOrID)
+	if found {
+		return id, nil
+	}
+
+	return getIDFromAPI(endpoint, clusterNameOrID, clientWrapper)
+}
+
+// getIDFromAPI looks up the cluster ID for a name/ID via the API. If several
+// clusters have the name, the user is asked to pick one.
+func getIDFromAPI(endpoint string, clusterNameOrID string, clientWrapper *client.Wrapper) (string, error) {
 	auxParams := clientWrapper.DefaultAuxiliaryParams()
 	auxParams.ActivityName = listClustersActivityName
 
@@ -71,21 +115,16 @@ func GetID(endpoint string, clusterNameOrID string, clientWrapper *client.Wrappe
 		}
 	}
 
-	var (
-		// IDs that correspond to the same cluster name.
-		matchingIDs []string
-		// All IDs returned from the response.
-		allClusterIDs = make([]string, 0, len(response.Payload))
-	)
+	// IDs that correspond to the same cluster name.
+	var matchingIDs []string
 	for _, cluster := range response.Payload {
-		allClusterIDs = append(allClusterIDs, cluster.ID)
 		// Check if this is the cluster we're looking for.
 		if matchesValidation(clusterNameOrID, cluster) {
 			matchingIDs = append(matchingIDs, cluster.ID)
 		}
 	}
 
-	CacheIDs(endpoint, allClusterIDs)
+	CacheClusters(endpoint, response.Payload)
 
 	if matchingIDs == nil {
 		// There are no IDs that correspond to that cluster name
@@ -140,6 +179,34 @@ func IsInCache(endpoint string, ID string) bool {
 	return false
 }
 
+// idForName returns the ID of the cluster with the given name, if exactly
+// one cluster with this name is in the persistent cluster cache, and the
+// clusters have been listed recently.
+func idForName(endpoint string, name string) (string, bool) {
+	existing, err := read(config.FileSystem)
+	if err != nil {
+		return "", false
+	}
+
+	c := existing.Endpoints[endpoint]
+	updated, err := time.Parse(timeLayout, c.Updated)
+	if err != nil || time.Since(updated) > nameCacheDuration {
+		return "", false
+	}
+
+	var matchingIDs []string
+	for _, cluster := range c.Clusters {
+		if cluster.Name == name {
+			matchingIDs = append(matchingIDs, cluster.ID)
+		}
+	}
+	if len(matchingIDs) != 1 {
+		return "", false
+	}
+
+	return matchingIDs[0], true
+}
+
 // CacheIDs adds cluster IDs to a persistent cache,
 // which can be used for decreasing timeout in getting
 // cluster IDs, for commands that take both cluster names and IDs.
@@ -149,28 +216,132 @@ func CacheIDs(endpoint string, c []string) {
 		return
 	}
 
-	fs := config.FileSystem
+	_ = update(config.FileSystem, func(cache *Cache) {
+		// Add the cache to a certain endpoint.
+		cache.Endpoints[endpoint] = EndpointCache{
+			Expiry: time.Now().Add(cacheDuration).Format(timeLayout),
+			IDs:    c,
+		}
+	})
+}
 
-	var cache *Cache
-	{
-		// Create a new Cache object if there is no file there yet.
-		cache, _ = read(fs)
-		if cache == nil {
-			cache = New()
+// CacheClusters replaces the cached clusters of an endpoint with the given
+// list, as returned by the API. Besides the IDs, the names, owners, release
+// versions and creation dates are stored, so that cluster names can be
+// resolved without calling the API.
+func CacheClusters(endpoint string, clusters []*models.V4ClusterListItem) {
+	endpointCache := EndpointCache{
+		Expiry:   time.Now().Add(cacheDuration).Format(timeLayout),
+		IDs:      make([]string, 0, len(clusters)),
+		Clusters: make([]Cluster, 0, len(clusters)),
+		Updated:  time.Now().Format(timeLayout),
+	}
+	for _, cluster := range clusters {
+		// Clusters being deleted can't be used any more.
+		if cluster.DeleteDate != nil {
+			continue
 		}
+
+		endpointCache.IDs = append(endpointCache.IDs, cluster.ID)
+		endpointCache.Clusters = append(endpointCache.Clusters, Cluster{
+			ID:             cluster.ID,
+			Name:           cluster.Name,
+			Owner:          cluster.Owner,
+			ReleaseVersion: cluster.ReleaseVersion,
+			CreateDate:     cluster.CreateDate,
+		})
 	}
 
-	// Add the cache to a certain endpoint.
-	cache.Endpoints[endpoint] = EndpointCache{
-		Expiry: time.Now().Add(cacheDuration).Format(timeLayout),
-		IDs:    c,
+	_ = update(config.FileSystem, func(cache *Cache) {
+		cache.Endpoints[endpoint] = endpointCache
+	})
+}
+
+// CacheCluster adds a cluster to the persistent cache, e. g. after creating
+// it. If the cluster is already cached, its non-empty fields are updated.
+func CacheCluster(endpoint string, cluster Cluster) {
+	// In dry run mode, nothing has been created.
+	if client.DryRunFormat != "" {
+		return
 	}
 
-	// Write the cache file.
-	err := write(fs, cache)
-	if err != nil {
+	_ = update(config.FileSystem, func(cache *Cache) {
+		c := cache.Endpoints[endpoint]
+		if c.Expiry == "" {
+			c.Expiry = time.Now().Add(cacheDuration).Format(timeLayout)
+		}
+
+		isCached := false
+		for _, id := range c.IDs {
+			if id == cluster.ID {
+				isCached = true
+				break
+			}
+		}
+		if !isCached {
+			c.IDs = append(c.IDs, cluster.ID)
+		}
+
+		isCached = false
+		for i := range c.Clusters {
+			if c.Clusters[i].ID != cluster.ID {
+				continue
+			}
+			isCached = true
+
+			if cluster.Name != "" {
+				c.Clusters[i].Name = cluster.Name
+			}
+			if cluster.Owner != "" {
+				c.Clusters[i].Owner = cluster.Owner
+			}
+			if cluster.ReleaseVersion != "" {
+				c.Clusters[i].ReleaseVersion = cluster.ReleaseVersion
+			}
+			if cluster.CreateDate != "" {
+				c.Clusters[i].CreateDate = cluster.CreateDate
+			}
+		}
+		if !isCached {
+			c.Clusters = append(c.Clusters, cluster)
+		}
+
+		cache.Endpoints[endpoint] = c
+	})
+}
+
+// RemoveCluster removes a cluster from the persistent cache, e. g. after
+// deleting it.
+func RemoveCluster(endpoint string, clusterID string) {
+	// In dry run mode, nothing has been deleted.
+	if client.DryRunFormat != "" {
 		return
 	}
+
+	_ = update(config.FileSystem, func(cache *Cache) {
+		c, ok := cache.Endpoints[endpoint]
+		if !ok {
+			return
+		}
+
+		ids := make([]string, 0, len(c.IDs))
+		for _, id := range c.IDs {
+			if id != clusterID {
+				ids = append(ids, id)
+			}
+		}
+		c.IDs = ids
+
+		clusters := make([]Cluster, 0, len(c.Clusters))
+		for _, cluster := range c.Clusters {
+			if cluster.ID != clusterID {
+				clusters = append(clusters, cluster)
+			}
+		}
+		c.Clusters = clusters
+
+		cache.Endpoints[endpoint] = c
+	})
 }
 
 func matchesValidation(nameOrID string, cluster *models.V4ClusterListItem) bool {
@@ -236,6 +407,8 @@ func read(fs afero.Fs) (*Cache, error) {
 	return cache, nil
 }
 
+// write stores the cache file. The content is written to a temporary file
+// first and then moved, so that readers never see a partially written file.
 func write(fs afero.Fs, c *Cache) error {
 	filePath := path.Join(config.ConfigDirPath, clusterCacheFileName)
 	output, err := yaml.Marshal(c)
@@ -243,7 +416,31 @@ func write(fs afero.Fs, c *Cache) error {
 		return err
 	}
 
-	err = afero.WriteFile(fs, filePath, output, config.ConfigFilePermission)
+	err = lockedfile.Write(fs, filePath, output, config.ConfigFilePermission)
 
 	return err
 }
+
+// update reads the cache file, applies a modification and writes it again,
+// while holding a lock. This way, parallel gsctl processes don't overwrite
+// each other's changes.
+func update(fs afero.Fs, modify func(c *Cache)) error {
+	unlock, err := lockedfile.Lock(fs, path.Join(config.ConfigDirPath, clusterCacheFileName))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Create a new Cache object if there is no file there yet.
+	cache, _ := read(fs)
+	if cache == nil {
+		cache = New()
+	}
+	if cache.Endpoints == nil {
+		cache.Endpoints = Endpoints{}
+	}
+
+	modify(cache)
+
+	return write(fs, cache)
+}
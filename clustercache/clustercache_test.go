@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"path"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -377,3 +378,169 @@ func Test_CacheClusterIDs(t *testing.T) {
 		})
 	}
 }
+
+// Test_GetIDFromCachedName checks that cluster names are resolved via the
+// cache, without calling the API, and that deleted clusters are removed.
+func Test_GetIDFromCachedName(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer mockServer.Close()
+
+	clientWrapper, err := client.NewWithConfig(mockServer.URL, "test-token")
+	if err != nil {
+		t.Fatalf("Error in client creation: %s", err)
+	}
+
+	dd := strfmt.NewDateTime()
+	CacheClusters(mockServer.URL, []*models.V4ClusterListItem{
+		{ID: "fow72", Name: "Production", Owner: "acme", ReleaseVersion: "12.1.4", CreateDate: "2017-05-16T09:30:31Z"},
+		{ID: "9as2a", Name: "Twin", Owner: "acme"},
+		{ID: "d740d", Name: "Twin", Owner: "acme"},
+		{ID: "del01", Name: "Deleted", Owner: "acme", DeleteDate: &dd},
+	})
+	CacheCluster(mockServer.URL, Cluster{ID: "n3w01", Name: "New"})
+
+	for _, name := range []string{"Production", "New"} {
+		_, err = GetIDCached(mockServer.URL, name, clientWrapper)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %#v", name, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests for cached names, got %d", requests)
+	}
+
+	// Names which are ambiguous or not cached are looked up via the API.
+	for _, name := range []string{"Twin", "Deleted"} {
+		_, err = GetIDCached(mockServer.URL, name, clientWrapper)
+		if !errors.IsClusterNotFoundError(err) {
+			t.Errorf("Expected ClusterNotFoundError for %q, got %#v", name, err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	// GetID, used by commands modifying clusters, always checks names via the API.
+	_, err = GetID(mockServer.URL, "Production", clientWrapper)
+	if !errors.IsClusterNotFoundError(err) {
+		t.Errorf("Expected ClusterNotFoundError, got %#v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+
+	// Names listed too long ago are looked up via the API, too. The
+	// response without clusters replaces the cache, so the update time is
+	// set afterwards.
+	_, _ = GetIDCached(mockServer.URL, "Unknown", clientWrapper)
+	CacheClusters(mockServer.URL, []*models.V4ClusterListItem{{ID: "fow72", Name: "Production"}})
+	err = update(fs, func(c *Cache) {
+		endpointCache := c.Endpoints[mockServer.URL]
+		endpointCache.Updated = time.Now().Add(-nameCacheDuration - time.Minute).Format(timeLayout)
+		c.Endpoints[mockServer.URL] = endpointCache
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests = 0
+	_, err = GetIDCached(mockServer.URL, "Production", clientWrapper)
+	if !errors.IsClusterNotFoundError(err) {
+		t.Errorf("Expected ClusterNotFoundError for an outdated name, got %#v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func Test_CacheCluster(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterCacheFileDir, err := testutils.TempClusterCache(fs, `endpoints:
+  mock-endpoint:
+    expiry: "2030-03-03T13:17:16+01:00"
+    ids:
+    - 2sg4i
+    - 123asd
+    clusters:
+    - id: 2sg4i
+      name: Old name
+      owner: acme
+    - id: 123asd
+      name: Other
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	CacheCluster("mock-endpoint", Cluster{ID: "2sg4i", Name: "New name"})
+	CacheCluster("mock-endpoint", Cluster{ID: "n3w01", Name: "New cluster", Owner: "acme", ReleaseVersion: "13.0.1"})
+	RemoveCluster("mock-endpoint", "123asd")
+	RemoveCluster("unknown-endpoint", "2sg4i")
+
+	expected := `endpoints:
+  mock-endpoint:
+    expiry: "2030-03-03T13:17:16+01:00"
+    ids:
+    - 2sg4i
+    - n3w01
+    clusters:
+    - id: 2sg4i
+      name: New name
+      owner: acme
+    - id: n3w01
+      name: New cluster
+      owner: acme
+      release_version: 13.0.1
+`
+	cacheContent, _ := afero.ReadFile(fs, path.Join(clusterCacheFileDir, clusterCacheFileName))
+	if diff := cmp.Diff(expected, string(cacheContent)); diff != "" {
+		t.Errorf("Result did not match (-expected +got):\n%s", diff)
+	}
+}
+
+// Test_CacheClusterParallel checks that parallel writers don't overwrite
+// each other's changes, thanks to the file lock.
+func Test_CacheClusterParallel(t *testing.T) {
+	fs := afero.NewOsFs()
+	dir, err := testutils.TempClusterCache(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.RemoveAll(dir)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				CacheCluster("mock-endpoint", Cluster{ID: fmt.Sprintf("c%02d-%d", i, j)})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 5; j++ {
+			id := fmt.Sprintf("c%02d-%d", i, j)
+			if !IsInCache("mock-endpoint", id) {
+				t.Errorf("Expected cluster %s to be cached", id)
+			}
+		}
+	}
+}
@@ -0,0 +1,406 @@
+// Package apply implements the 'apply' command.
+package apply
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+)
+
+var (
+	// Command is the cobra command for 'gsctl apply'
+	Command = &cobra.Command{
+		Use:   "apply",
+		Short: "Create or update a cluster based on a definition file",
+		Long: `Make a cluster match a cluster definition.
+
+The definition must be in the v5 format (the one with the 'api_version' key),
+as used with 'gsctl create cluster --file'. See
+
+  https://docs.giantswarm.io/ui-api/gsctl/cluster-definition/
+
+The cluster is identified by the combination of 'name' and 'owner' in the
+definition. If no such cluster exists, it gets created, including all node pools
+and labels. As with 'gsctl create cluster', a default node pool is added if the
+definition has none. If the cluster exists, these changes are applied:
+
+- The release version gets updated, which means the cluster gets upgraded.
+- Master node high availability gets enabled, if requested.
+- Labels from the definition get set. Labels with a null value get removed.
+  Labels not mentioned in the definition are left untouched.
+- Node pools are matched by name, so every node pool needs a unique name.
+  Node pools missing in the cluster get created, the scaling settings of
+  existing node pools get updated.
+- Node pools not mentioned in the definition are only deleted when the
+  --delete-nodepools flag is given.
+
+Node pool attributes other than the scaling settings, like instance types or
+availability zones, cannot be changed after creation and are ignored.
+
+Examples:
+
+  gsctl apply -f ./my-cluster.yaml
+
+  gsctl apply -f ./my-cluster.yaml --delete-nodepools --force
+
+  cat my-cluster.yaml | gsctl apply -f -
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "apply"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.InputYAMLFile, "file", "f", "", "Path to a cluster definition YAML file. Use '-' to read from STDIN.")
+	Command.Flags().BoolVarP(&flags.DeleteNodePools, "delete-nodepools", "", false, "Delete node pools which are not part of the definition.")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required before deleting node pools (risky!).")
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. It also disables any confirmations.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	DeleteNodePools   bool
+	FileSystem        afero.Fs
+	Force             bool
+	InputYAMLFile     string
+	OutputFormat      string
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// actionResult is the outcome of a single action.
+type actionResult struct {
+	*clusterdefinition.Action
+	Error *errors.Output `json:"error,omitempty"`
+}
+
+// result is what applyDefinition returns.
+type result struct {
+	ClusterID string
+	Created   bool
+	Actions   []*actionResult
+
+	// HasErrors is true if at least one action failed.
+	HasErrors bool
+	// Err is the error of the first action which failed. It determines
+	// the exit code.
+	Err error
+}
+
+// JSONOutput is the structure printed when the command is called with JSON output.
+type JSONOutput struct {
+	// Result of the command. One of 'created', 'updated', 'unchanged', 'created-with-errors',
+	// 'updated-with-errors' or 'error'.
+	Result string `json:"result"`
+	// ClusterID is the ID of the cluster.
+	ClusterID string `json:"cluster_id,omitempty"`
+	// Actions lists the actions taken.
+	Actions []*actionResult `json:"actions,omitempty"`
+	// Error which occurred.
+	Error *errors.Output `json:"error,omitempty"`
+}
+
+func collectArguments() Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		DeleteNodePools:   flags.DeleteNodePools,
+		FileSystem:        config.FileSystem,
+		Force:             flags.Force,
+		InputYAMLFile:     flags.InputYAMLFile,
+		OutputFormat:      flags.OutputFormat,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.InputYAMLFile == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--file")
+	}
+	if !formatting.IsStructured(args.OutputFormat) && args.OutputFormat != formatting.OutputFormatTable {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.OutputFormat)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// applyDefinition is the business function. It compares the definition with
+// the live state and executes the required actions.
+func applyDefinition(args Arguments) (*result, error) {
+	def, err := clusterdefinition.ReadV5(args.FileSystem, args.InputYAMLFile)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = clusterdefinition.ValidateForPlan(def)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	live, err := clusterdefinition.FetchLiveState(clientWrapper, auxParams, def.Name, def.Owner)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if live.Cluster == nil {
+		// Like 'create cluster', apply the installation's master node settings.
+		info, err := clientWrapper.GetInfo(auxParams)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		clusterdefinition.SetProviderDefaultsV5(def, info.Payload.General.Provider, *info.Payload.General.AvailabilityZones.Max)
+	}
+
+	actions := clusterdefinition.Plan(def, live, args.DeleteNodePools)
+
+	r := &result{}
+	if live.Cluster != nil {
+		r.ClusterID = live.Cluster.ID
+	}
+
+	if !args.Force && !formatting.IsStructured(args.OutputFormat) {
+		var deletions []string
+		for _, a := range actions {
+			if a.Type == clusterdefinition.ActionDeleteNodePool {
+				deletions = append(deletions, a.NodePoolID)
+			}
+		}
+
+		if len(deletions) > 0 {
+			confirmed := confirm.Ask(fmt.Sprintf("Do you really want to delete %d node pool(s) from cluster '%s', including all their worker nodes?", len(deletions), r.ClusterID))
+			if !confirmed {
+				return nil, microerror.Mask(errors.CommandAbortedError)
+			}
+		}
+	}
+
+	for i := 0; i < len(actions); i++ {
+		a := actions[i]
+		if !formatting.IsStructured(args.OutputFormat) {
+			fmt.Printf("Applying: %s\n", a)
+		}
+
+		ar := &actionResult{Action: a}
+		r.Actions = append(r.Actions, ar)
+
+		if a.Type == clusterdefinition.ActionCreateCluster {
+			response, err := clientWrapper.CreateClusterV5(a.AddClusterRequest, auxParams)
+			if err != nil {
+				// Without a cluster, there is no point in continuing.
+				return nil, microerror.Mask(err)
+			}
+			r.ClusterID = response.Payload.ID
+			r.Created = true
+
+			// Like 'create cluster', add a default node pool if the
+			// definition has none. Its settings depend on the new cluster.
+			if len(def.NodePools) == 0 {
+				defaultNodePool := &clusterdefinition.Action{
+					Type:               clusterdefinition.ActionCreateNodePool,
+					AddNodePoolRequest: clusterdefinition.DefaultNodePoolRequest(response.Payload),
+				}
+				actions = append(actions[:i+1], append([]*clusterdefinition.Action{defaultNodePool}, actions[i+1:]...)...)
+			}
+			continue
+		}
+
+		err = executeAction(clientWrapper, auxParams, r.ClusterID, a)
+		if err != nil {
+			ar.Error = errors.NewOutput(err)
+			if !r.HasErrors {
+				r.Err = err
+			}
+			r.HasErrors = true
+
+			if !formatting.IsStructured(args.OutputFormat) {
+				fmt.Println(color.RedString("Error: %s", err.Error()))
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// executeAction sends the API request for an action on an existing cluster.
+func executeAction(clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams, clusterID string, a *clusterdefinition.Action) error {
+	var err error
+
+	switch a.Type {
+	case clusterdefinition.ActionModifyCluster:
+		_, err = clientWrapper.ModifyClusterV5(clusterID, a.ModifyClusterRequest, auxParams)
+	case clusterdefinition.ActionUpdateLabels:
+		_, err = clientWrapper.UpdateClusterLabels(clusterID, a.SetLabelsRequest, auxParams)
+	case clusterdefinition.ActionCreateNodePool:
+		_, err = clientWrapper.CreateNodePool(clusterID, a.AddNodePoolRequest, auxParams)
+	case clusterdefinition.ActionModifyNodePool:
+		_, err = clientWrapper.ModifyNodePool(clusterID, a.NodePoolID, a.ModifyNodePoolRequest, auxParams)
+	case clusterdefinition.ActionDeleteNodePool:
+		_, err = clientWrapper.DeleteNodePool(clusterID, a.NodePoolID, auxParams)
+	}
+
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	r, err := applyDefinition(arguments)
+
+	if formatting.IsStructured(arguments.OutputFormat) {
+		printJSONOutput(r, err)
+		return
+	}
+
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	switch {
+	case r.HasErrors:
+		fmt.Println(color.RedString("Some changes could not be applied to cluster '%s'. Please check the error details above.", r.ClusterID))
+		os.Exit(errors.ExitCode(r.Err))
+	case r.Created:
+		fmt.Println(color.GreenString("Cluster '%s' has been created.", r.ClusterID))
+	case len(r.Actions) == 0:
+		fmt.Println(color.GreenString("Cluster '%s' is up to date. Nothing to do.", r.ClusterID))
+	default:
+		fmt.Println(color.GreenString("Cluster '%s' has been updated.", r.ClusterID))
+	}
+}
+
+func printJSONOutput(r *result, applyErr error) {
+	var output JSONOutput
+	if applyErr != nil {
+		output = JSONOutput{Result: "error", Error: errors.NewOutput(applyErr)}
+	} else {
+		output = JSONOutput{ClusterID: r.ClusterID, Actions: r.Actions}
+
+		switch {
+		case r.Created:
+			output.Result = "created"
+		case len(r.Actions) == 0:
+			output.Result = "unchanged"
+		default:
+			output.Result = "updated"
+		}
+
+		if r.HasErrors {
+			output.Result += "-with-errors"
+		}
+	}
+
+	outputBytes, err := formatting.Marshal(arguments.OutputFormat, output)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(string(outputBytes))
+	if applyErr != nil {
+		os.Exit(errors.ExitCode(applyErr))
+	}
+	if r.HasErrors {
+		os.Exit(errors.ExitCode(r.Err))
+	}
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsRequiredFlagMissingError(err):
+		headline = "Missing flag: " + err.Error()
+		subtext = "Please use --file to specify the cluster definition to apply."
+	case clusterdefinition.IsFileNotReadable(err):
+		headline = "Could not read cluster definition"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	case clusterdefinition.IsInvalidDefinition(err):
+		headline = "Could not parse cluster definition"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	case clusterdefinition.IsDefinitionNotV5(err),
+		clusterdefinition.IsClusterNameMissing(err),
+		clusterdefinition.IsOwnerMissing(err),
+		clusterdefinition.IsNodePoolNameMissing(err),
+		clusterdefinition.IsDuplicateNodePoolName(err):
+		headline = "Invalid cluster definition"
+		subtext = microerror.Pretty(err, false)
+	case clusterdefinition.IsClusterNotUnique(err):
+		headline = "Cluster is ambiguous"
+		subtext = fmt.Sprintf("Details: %s. Please rename the clusters so that they can be told apart.", err.Error())
+	case errors.IsCommandAbortedError(err):
+		headline = "Not applying any changes."
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
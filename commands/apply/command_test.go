@@ -0,0 +1,346 @@
+package apply
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const definitionYAML = `api_version: v5
+name: My cluster
+owner: acme
+release_version: 12.0.0
+labels:
+  env: prod
+nodepools:
+- name: workers
+  scaling:
+    min: 3
+    max: 20
+- name: new
+`
+
+func writeDefinition(t *testing.T, fs afero.Fs) {
+	err := afero.WriteFile(fs, "cluster.yaml", []byte(definitionYAML), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestApplyUpdate tests applying a definition to an existing cluster.
+func TestApplyUpdate(t *testing.T) {
+	var requests []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "abc12", "name": "My cluster", "owner": "acme"},
+				{"id": "def34", "name": "My cluster", "owner": "other"}
+			]`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/abc12/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "abc12", "name": "My cluster", "owner": "acme", "release_version": "11.0.0", "labels": {"env": "dev"}}`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/abc12/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "a7k", "name": "workers", "scaling": {"min": 3, "max": 10}},
+				{"id": "b8l", "name": "old", "scaling": {"min": 1, "max": 2}}
+			]`))
+		case r.Method == "PATCH" && r.URL.Path == "/v5/clusters/abc12/":
+			requests = append(requests, "modify-cluster")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "abc12"}`))
+		case r.Method == "PUT" && r.URL.Path == "/v5/clusters/abc12/labels/":
+			requests = append(requests, "update-labels")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"labels": {"env": "prod"}}`))
+		case r.Method == "PATCH" && r.URL.Path == "/v5/clusters/abc12/nodepools/a7k/":
+			requests = append(requests, "modify-nodepool")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "a7k", "name": "workers"}`))
+		case r.Method == "POST" && r.URL.Path == "/v5/clusters/abc12/nodepools/":
+			requests = append(requests, "create-nodepool")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "c9m", "name": "new"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/v5/clusters/abc12/nodepools/b8l/":
+			requests = append(requests, "delete-nodepool")
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"code": "RESOURCE_DELETION_STARTED", "message": "Deletion started."}`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeDefinition(t, fs)
+
+	args := Arguments{
+		APIEndpoint:     mockServer.URL,
+		AuthToken:       "token",
+		DeleteNodePools: true,
+		FileSystem:      fs,
+		Force:           true,
+		InputYAMLFile:   "cluster.yaml",
+		OutputFormat:    "json",
+	}
+
+	r, err := applyDefinition(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Created || r.HasErrors || r.ClusterID != "abc12" {
+		t.Errorf("Unexpected result %#v", r)
+	}
+
+	expected := []string{"create-nodepool", "delete-nodepool", "modify-cluster", "modify-nodepool", "update-labels"}
+	sort.Strings(requests)
+	if diff := cmp.Diff(expected, requests); diff != "" {
+		t.Errorf("Requests not as expected (-want +got):\n%s", diff)
+	}
+}
+
+// TestApplyPartialFailure tests that a failing action is reported, and that
+// its error determines the exit code.
+func TestApplyPartialFailure(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "abc12", "name": "My cluster", "owner": "acme"}]`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/abc12/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "abc12", "name": "My cluster", "owner": "acme", "release_version": "12.0.0", "labels": {"env": "prod"}}`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/abc12/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "a7k", "name": "workers", "scaling": {"min": 3, "max": 20}}]`))
+		case r.Method == "POST" && r.URL.Path == "/v5/clusters/abc12/nodepools/":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code": "FORBIDDEN", "message": "Access denied."}`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeDefinition(t, fs)
+
+	args := Arguments{
+		APIEndpoint:   mockServer.URL,
+		AuthToken:     "token",
+		FileSystem:    fs,
+		Force:         true,
+		InputYAMLFile: "cluster.yaml",
+		OutputFormat:  "json",
+	}
+
+	r, err := applyDefinition(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.HasErrors || len(r.Actions) != 1 || r.Actions[0].Error == nil {
+		t.Fatalf("Expected failed action, got %#v", r)
+	}
+	if r.Actions[0].Error.Class != "auth" {
+		t.Errorf("Expected error class 'auth', got %#v", r.Actions[0].Error)
+	}
+	if code := errors.ExitCode(r.Err); code != exitcode.Auth {
+		t.Errorf("Expected exit code %d, got %d", exitcode.Auth, code)
+	}
+}
+
+// TestApplyCreate tests applying a definition for a cluster which does not exist yet.
+func TestApplyCreate(t *testing.T) {
+	var requests []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case r.Method == "GET" && r.URL.Path == "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"general": {"provider": "aws", "availability_zones": {"default": 1, "max": 3}}}`))
+		case r.Method == "POST" && r.URL.Path == "/v5/clusters/":
+			requests = append(requests, "create-cluster")
+			w.Header().Set("Location", "/v5/clusters/new12/")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "new12", "name": "My cluster", "owner": "acme"}`))
+		case r.Method == "POST" && r.URL.Path == "/v5/clusters/new12/nodepools/":
+			requests = append(requests, "create-nodepool")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "a7k"}`))
+		case r.Method == "PUT" && r.URL.Path == "/v5/clusters/new12/labels/":
+			requests = append(requests, "update-labels")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"labels": {"env": "prod"}}`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeDefinition(t, fs)
+
+	args := Arguments{
+		APIEndpoint:   mockServer.URL,
+		AuthToken:     "token",
+		FileSystem:    fs,
+		InputYAMLFile: "cluster.yaml",
+		OutputFormat:  "json",
+	}
+
+	r, err := applyDefinition(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Created || r.ClusterID != "new12" {
+		t.Errorf("Unexpected result %#v", r)
+	}
+
+	expected := "create-cluster create-nodepool create-nodepool update-labels"
+	if strings.Join(requests, " ") != expected {
+		t.Errorf("Expected requests %q, got %q", expected, strings.Join(requests, " "))
+	}
+}
+
+// TestApplyCreateAzureDefaultNodePool tests that like 'create cluster',
+// apply sets the Azure master node settings and adds a default node pool
+// to a new cluster if the definition has no node pools.
+func TestApplyCreateAzureDefaultNodePool(t *testing.T) {
+	var clusterRequest, nodePoolRequest string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case r.Method == "GET" && r.URL.Path == "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"general": {"provider": "azure", "availability_zones": {"default": 0, "max": 0}}}`))
+		case r.Method == "POST" && r.URL.Path == "/v5/clusters/":
+			body, _ := ioutil.ReadAll(r.Body)
+			clusterRequest = string(body)
+			w.Header().Set("Location", "/v5/clusters/new12/")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "new12", "name": "My cluster", "owner": "acme", "master_nodes": {"availability_zones": []}}`))
+		case r.Method == "POST" && r.URL.Path == "/v5/clusters/new12/nodepools/":
+			body, _ := ioutil.ReadAll(r.Body)
+			nodePoolRequest = string(body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "a7k"}`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = afero.WriteFile(fs, "cluster.yaml", []byte("api_version: v5\nname: My cluster\nowner: acme\nrelease_version: 12.0.0\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:   mockServer.URL,
+		AuthToken:     "token",
+		FileSystem:    fs,
+		InputYAMLFile: "cluster.yaml",
+		OutputFormat:  "json",
+	}
+
+	r, err := applyDefinition(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.HasErrors || len(r.Actions) != 2 || r.Actions[1].Type != clusterdefinition.ActionCreateNodePool {
+		t.Errorf("Unexpected result %#v", r)
+	}
+	if !strings.Contains(clusterRequest, `"availability_zones_unspecified":true`) {
+		t.Errorf("Expected master node without availability zone, got %s", clusterRequest)
+	}
+	if !strings.Contains(nodePoolRequest, `"number":-1`) {
+		t.Errorf("Expected default node pool without availability zone, got %s", nodePoolRequest)
+	}
+}
+
+// TestVerifyPreconditions tests invalid argument combinations.
+func TestVerifyPreconditions(t *testing.T) {
+	testCases := []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", InputYAMLFile: "cluster.yaml", OutputFormat: "table"},
+			errorMatcher: errors.IsNotLoggedInError,
+		},
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", AuthToken: "token", OutputFormat: "table"},
+			errorMatcher: errors.IsRequiredFlagMissingError,
+		},
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", AuthToken: "token", InputYAMLFile: "cluster.yaml", OutputFormat: "xml"},
+			errorMatcher: errors.IsOutputFormatInvalid,
+		},
+	}
+
+	for i, tc := range testCases {
+		err := verifyPreconditions(tc.args)
+		if err == nil {
+			t.Errorf("Case %d - Expected error, got nil", i)
+		} else if !tc.errorMatcher(err) {
+			t.Errorf("Case %d - Error did not match expected type. Got '%s'", i, err)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/pkg/exitcode"
 )
 
 var (
@@ -85,7 +87,7 @@ func validateCompletionPreconditions(cmd *cobra.Command, args []string) {
 	if shell != shellBash && shell != shellFish && shell != shellZsh {
 		fmt.Println(color.RedString("Shell not supported"))
 		fmt.Println("We only provide shell completion support for bash, fish, and zsh at this time.")
-		os.Exit(1)
+		os.Exit(exitcode.Validation)
 	}
 }
 
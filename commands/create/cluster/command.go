@@ -24,6 +24,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/fatih/color"
@@ -35,10 +36,13 @@ import (
 
 	"github.com/giantswarm/gsctl/capabilities"
 	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/clustercache"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/commands/types"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -49,6 +53,7 @@ type Arguments struct {
 	AuthToken             string
 	CreateDefaultNodePool bool
 	ClusterName           string
+	Concurrency           int
 	FileSystem            afero.Fs
 	InputYAMLFile         string
 	Owner                 string
@@ -58,6 +63,13 @@ type Arguments struct {
 	UserProvidedToken     string
 	Verbose               bool
 	OutputFormat          string
+	// Quiet suppresses progress and error messages while creating a
+	// cluster, e. g. when creating several clusters concurrently.
+	Quiet                 bool
+	SetValues             []string
+	ValuesFile            string
+	Wait                  bool
+	WaitTimeout           time.Duration
 }
 
 // collectArguments gets arguments from flags and returns an Arguments object.
@@ -80,10 +92,14 @@ func collectArguments(cmd *cobra.Command) Arguments {
 		flags.OutputFormat = ""
 	}
 
+	// In dry run mode, nothing changes, so there is nothing to wait for.
+	wait := flags.Wait && flags.DryRun == ""
+
 	return Arguments{
 		APIEndpoint:           endpoint,
 		AuthToken:             token,
 		ClusterName:           flags.ClusterName,
+		Concurrency:           flags.Concurrency,
 		CreateDefaultNodePool: flags.CreateDefaultNodePool,
 		FileSystem:            config.FileSystem,
 		InputYAMLFile:         flags.InputYAMLFile,
@@ -92,8 +108,12 @@ func collectArguments(cmd *cobra.Command) Arguments {
 		ReleaseVersion:        normalizedReleaseVersion,
 		Scheme:                scheme,
 		UserProvidedToken:     flags.Token,
-		Verbose:               flags.OutputFormat != formatting.OutputFormatJSON && flags.Verbose,
+		Verbose:               !formatting.IsStructured(flags.OutputFormat) && flags.Verbose,
 		OutputFormat:          flags.OutputFormat,
+		SetValues:             flags.SetValues,
+		ValuesFile:            flags.ValuesFile,
+		Wait:                  wait,
+		WaitTimeout:           flags.WaitTimeout,
 	}
 }
 
@@ -111,6 +131,13 @@ type creationResult struct {
 	// This is only relevant in v5 and should only be used if a node
 	// pool could not be created successfully.
 	HasErrors bool
+	// Errors contains the non-critical errors. The first one determines
+	// the exit code.
+	Errors []error
+
+	// Fleet contains the results per cluster when several clusters have
+	// been created from a multi-document definition.
+	Fleet []*fleetResult
 }
 
 // JSONOutput contains the fields included in JSON output of the create cluster command when called with json output flag
@@ -120,12 +147,15 @@ type JSONOutput struct {
 	// Result of the command. should be 'created'
 	Result string `json:"result"`
 	// Error which occured
-	Error error `json:"error,omitempty"`
+	Error *errors.Output `json:"error,omitempty"`
 }
 
 const (
 	createClusterActivityName = "create-cluster"
 
+	// defaultConcurrency is the default number of clusters created in parallel.
+	defaultConcurrency = 3
+
 	standardInputSpecialPath = "-"
 )
 
@@ -148,6 +178,52 @@ For details about the cluster definition YAML format, see
 Note that you can also use command line flags to override some settings
 from the YAML definition.
 
+Definition files are validated strictly. Unknown keys, values of the wrong
+type and values out of range are reported with their line and column. To
+get the JSON Schema of the definition format, e. g. for autocompletion in
+your editor, use
+
+  gsctl create cluster --print-schema > cluster-definition.schema.json
+
+The schema for v4 definitions is available via --print-schema=v4.
+
+Templating
+----------
+
+Definition files can contain placeholders like ${VAR}, which are replaced by
+the values of environment variables. Values can also be given in a YAML file
+via --values, taking precedence over the environment. A default for unset
+variables can be given as ${VAR:-default}. Use $$ for a literal dollar sign.
+
+After that, single values can be overridden using --set, once per value:
+
+  gsctl create cluster -f ./cluster.yaml --values ./production.yaml \
+    --set nodepools[0].scaling.max=10 \
+    --set labels.team=blue
+
+Creating several clusters
+-------------------------
+
+A definition file can contain several v5 definitions, separated by lines
+containing '---'. One cluster is created per definition, with up to
+--concurrency clusters being created in parallel. Flags like --owner apply
+to all of them. A table with the result per cluster is printed at the end,
+or a JSON or YAML list when using --output=json or --output=yaml. The exit
+code is non-zero if any cluster could not be created.
+
+  gsctl create cluster -f ./fleet.yaml --concurrency 5
+
+Waiting for the cluster
+-----------------------
+
+By default, the command returns as soon as the cluster creation has been
+requested. With --wait, it waits until the master nodes and all node pools
+(or workers, for clusters without node pools) report the desired number of
+ready nodes, printing the readiness counts as they change. If the cluster is
+not ready within --wait-timeout, the command fails with a non-zero exit code.
+
+  gsctl create cluster -f ./cluster.yaml --wait --wait-timeout 45m
+
 Defaults
 --------
 
@@ -226,29 +302,77 @@ func initFlags() {
 	Command.Flags().StringVarP(&flags.Release, "release", "r", "", "Workload cluster release to use, e. g. '1.2.3'. Defaults to the latest. See 'gsctl list releases --help' for details.")
 	Command.Flags().BoolVar(&flags.MasterHA, "master-ha", true, "When true, the cluster will provide high-availability Kubernetes masters.")
 	Command.Flags().BoolVarP(&flags.CreateDefaultNodePool, "create-default-nodepool", "", true, "Whether a default node pool should be created if none is specified in the definition. Requires node pool support.")
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' will change output to be JSON formatted.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' or '%s' will change output to be JSON or YAML formatted. Use '%s=<expression>' or '%s=<template>' to extract fields.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+	Command.Flags().StringVarP(&flags.ValuesFile, "values", "", "", "Path to a YAML file with variables to substitute for ${VAR} placeholders in the cluster definition.")
+	Command.Flags().StringArrayVarP(&flags.SetValues, "set", "", nil, "Override a value in the cluster definition, e. g. 'nodepools[0].scaling.max=10'. Can be used multiple times.")
+	Command.Flags().IntVarP(&flags.Concurrency, "concurrency", "", defaultConcurrency, "Maximum number of clusters to create in parallel, when the definition contains several documents.")
+	Command.Flags().StringVarP(&flags.PrintSchema, "print-schema", "", "", fmt.Sprintf("Print the JSON Schema for cluster definition files and exit. Use '%s' or '%s' to select the definition version.", clusterdefinition.SchemaVersionV5, clusterdefinition.SchemaVersionV4))
+	Command.Flag("print-schema").NoOptDefVal = clusterdefinition.SchemaVersionV5
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the master nodes and node pools of the new cluster are ready.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "wait-timeout", "", clusterwait.DefaultTimeout, "Maximum time to wait when using --wait, e. g. '45m'.")
+}
+
+// printSchema prints the JSON Schema for the definition version given via
+// --print-schema. It returns false if the flag isn't set.
+func printSchema(version string) (bool, error) {
+	if version == "" {
+		return false, nil
+	}
+
+	schema := clusterdefinition.SchemaForVersion(version)
+	if schema == nil {
+		return true, microerror.Maskf(schemaVersionInvalidError, "schema version '%s' is unknown", version)
+	}
+
+	schemaBytes, err := json.MarshalIndent(schema, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	if err != nil {
+		return true, microerror.Mask(err)
+	}
+
+	fmt.Println(string(schemaBytes))
+
+	return true, nil
 }
 
 // printValidation runs our pre-checks.
 // If errors occur, error info is printed to STDOUT/STDERR
 // and the program will exit with non-zero exit codes.
 func printValidation(cmd *cobra.Command, positionalArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "create cluster", "template cluster", "https://docs.giantswarm.io/ui-api/kubectl-gs/template-cluster/"))
+	// Printing the schema requires neither a login nor any other flags.
+	printed, err := printSchema(flags.PrintSchema)
+	if err != nil {
+		fmt.Println(color.RedString("Unknown cluster definition version"))
+		fmt.Printf("Please use --print-schema=%s or --print-schema=%s.\n", clusterdefinition.SchemaVersionV5, clusterdefinition.SchemaVersionV4)
+		os.Exit(errors.ExitCode(err))
+	}
+	if printed {
+		os.Exit(0)
+	}
+
+	// The notice goes to stderr, so that it does not end up in output
+	// processed by other tools, like JSON or extracted fields.
+	fmt.Fprint(os.Stderr, util.GetDeprecatedNotice(config.Config.Provider, "create cluster", "template cluster", "https://docs.giantswarm.io/ui-api/kubectl-gs/template-cluster/"))
 
 	arguments = collectArguments(cmd)
 
 	headline := ""
 	subtext := ""
 
-	err := verifyPreconditions(arguments)
+	err = verifyPreconditions(arguments)
 	if err != nil {
 		client.HandleErrors(err)
 		errors.HandleCommonErrors(err)
 
 		switch {
+		case errors.IsInvalidConcurrencyError(err):
+			headline = "Invalid concurrency"
+			subtext = "Please set --concurrency to a value of 1 or higher, or omit it to use the default."
 		case errors.IsConflictingFlagsError(err):
 			headline = "Conflicting flags used"
 			subtext = "When specifying a definition via a YAML file, certain flags must not be used."
+			if arguments.InputYAMLFile == "" {
+				subtext = "The flags --values and --set can only be used together with a cluster definition given via --file."
+			}
 		default:
 			headline = err.Error()
 		}
@@ -258,7 +382,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -266,7 +390,15 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 func printResult(cmd *cobra.Command, positionalArgs []string) {
 	result, err := addCluster(arguments)
 
-	if arguments.OutputFormat == formatting.OutputFormatJSON {
+	if err == nil && result.Fleet != nil {
+		printFleetResult(result.Fleet, arguments.OutputFormat)
+		return
+	}
+
+	if formatting.IsStructured(arguments.OutputFormat) {
+		if err == nil && arguments.Wait {
+			err = waitUntilReady(arguments, result.ID)
+		}
 		printJSONOutput(result, err)
 		return
 	}
@@ -315,6 +447,10 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 			} else {
 				subtext = fmt.Sprintf("The YAML data read from file '%s' could not be parsed into a cluster definition.", arguments.InputYAMLFile)
 			}
+		case clusterdefinition.IsRenderFailed(err):
+			headline = "Could not render cluster definition"
+			subtext = "Variables or overrides could not be applied to the cluster definition.\n"
+			subtext += fmt.Sprintf("Details: %s", err.Error())
 		case errors.IsYAMLFileNotReadable(err):
 			if arguments.InputYAMLFile == standardInputSpecialPath {
 				headline = "Could not read YAML from STDIN"
@@ -361,7 +497,13 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	// In dry run mode, the requests have only been printed.
+	if flags.DryRun != "" {
+		fmt.Println("Dry run finished, no cluster has been created.")
+		return
 	}
 
 	// success output
@@ -386,6 +528,26 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		}
 	}
 
+	if arguments.Wait {
+		fmt.Printf("\nWaiting up to %s for cluster '%s' to become ready\n", arguments.WaitTimeout, result.ID)
+
+		err = waitUntilReady(arguments, result.ID)
+		if err != nil {
+			client.HandleErrors(err)
+			errors.HandleCommonErrors(err)
+
+			if clusterwait.IsTimeout(err) {
+				fmt.Println(color.RedString("Timed out waiting for the cluster to become ready"))
+				fmt.Printf("Details: %s\n", err.Error())
+			} else {
+				fmt.Println(color.RedString(err.Error()))
+			}
+			os.Exit(errors.ExitCode(err))
+		}
+
+		fmt.Println(color.GreenString("Cluster '%s' is ready.", result.ID))
+	}
+
 	fmt.Println("\nAdd a key pair and settings for kubectl using")
 	fmt.Println("")
 	fmt.Printf("    %s", color.YellowString(fmt.Sprintf("gsctl create kubeconfig --cluster=%s \n", result.ID)))
@@ -406,7 +568,10 @@ func printJSONOutput(result *creationResult, creationErr error) {
 
 	// handle errors
 	if creationErr != nil {
-		jsonResult = JSONOutput{Result: "error", Error: creationErr}
+		jsonResult = JSONOutput{Result: "error", Error: errors.NewOutput(creationErr)}
+		if result != nil {
+			jsonResult.ID = result.ID
+		}
 	} else {
 		jsonResult = JSONOutput{ID: result.ID, Result: "created"}
 		if result.HasErrors {
@@ -414,15 +579,18 @@ func printJSONOutput(result *creationResult, creationErr error) {
 		}
 	}
 
-	outputBytes, err = json.MarshalIndent(jsonResult, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	outputBytes, err = formatting.Marshal(arguments.OutputFormat, jsonResult)
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(string(outputBytes))
-	if creationErr != nil || result.HasErrors {
-		os.Exit(1)
+	if creationErr != nil {
+		os.Exit(errors.ExitCode(creationErr))
+	}
+	if result.HasErrors {
+		os.Exit(errors.ExitCode(result.Errors[0]))
 	}
 }
 
@@ -435,8 +603,14 @@ func verifyPreconditions(args Arguments) error {
 	if args.AuthToken == "" && args.UserProvidedToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.OutputFormat != "" && args.OutputFormat != formatting.OutputFormatJSON {
-		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is invalid for gsctl create cluster. Valid options: '%s'", args.OutputFormat, formatting.OutputFormatJSON))
+	if args.InputYAMLFile == "" && (args.ValuesFile != "" || len(args.SetValues) > 0) {
+		return microerror.Maskf(errors.ConflictingFlagsError, "the flags --values and --set require --file")
+	}
+	if args.Concurrency < 0 {
+		return microerror.Maskf(errors.InvalidConcurrencyError, "--concurrency must not be negative, got %d", args.Concurrency)
+	}
+	if args.OutputFormat != "" && !formatting.IsStructured(args.OutputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is invalid for gsctl create cluster. Valid options: '%s', '%s', '%s=<expression>', '%s=<template>'", args.OutputFormat, formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
 	}
 
 	return nil
@@ -484,8 +658,31 @@ func isVersionProductionReady(version *semver.Version) bool {
 // via the v4 or v5 API endpoint, then calls the according functions
 // and returns results.
 func addCluster(args Arguments) (*creationResult, error) {
-	var err error
+	definitions, err := readDefinitions(args)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if len(definitions) > 1 {
+		fleet, err := addClusters(args, definitions)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
 
+		return &creationResult{Fleet: fleet}, nil
+	}
+
+	var definitionInterface interface{}
+	if len(definitions) == 1 {
+		definitionInterface = definitions[0]
+	}
+
+	return addClusterFromDefinition(args, definitionInterface)
+}
+
+// addClusterFromDefinition creates a cluster based on the given v4 or v5
+// definition (which may be nil) and the arguments.
+func addClusterFromDefinition(args Arguments, definitionInterface interface{}) (*creationResult, error) {
 	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
 	if err != nil {
 		return nil, microerror.Mask(err)
@@ -511,22 +708,6 @@ func addCluster(args Arguments) (*creationResult, error) {
 		}
 	}
 
-	// Process YAML definition (if given), so we can take a 'release_version' key into consideration.
-	var definitionInterface interface{}
-	if args.InputYAMLFile == standardInputSpecialPath {
-		definitionInterface, err = readDefinitionFromSTDIN()
-
-		if err != nil {
-			return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
-		}
-	} else if args.InputYAMLFile != "" {
-		// definition from file (and optionally flags)
-		definitionInterface, err = readDefinitionFromFile(args.FileSystem, args.InputYAMLFile)
-		if err != nil {
-			return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
-		}
-	}
-
 	// The release version we are selecting, based on command line flags, YAML definition,
 	// or as the latest release available.
 	var wantedRelease string
@@ -638,13 +819,14 @@ func addCluster(args Arguments) (*creationResult, error) {
 			maxSupportedAZs: *info.Payload.General.AvailabilityZones.Max,
 		})
 
-		id, hasErrors, err := addClusterV5(result.DefinitionV5, args, clientWrapper, auxParams)
+		id, nonCriticalErrors, err := addClusterV5(result.DefinitionV5, args, clientWrapper, auxParams)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 
 		result.ID = id
-		result.HasErrors = hasErrors
+		result.HasErrors = len(nonCriticalErrors) > 0
+		result.Errors = nonCriticalErrors
 
 	} else {
 		if args.Verbose {
@@ -666,6 +848,20 @@ func addCluster(args Arguments) (*creationResult, error) {
 		result.Location = location
 	}
 
+	cachedCluster := clustercache.Cluster{
+		ID:             result.ID,
+		ReleaseVersion: wantedRelease,
+		CreateDate:     time.Now().UTC().Format(time.RFC3339),
+	}
+	if result.DefinitionV5 != nil {
+		cachedCluster.Name = result.DefinitionV5.Name
+		cachedCluster.Owner = result.DefinitionV5.Owner
+	} else {
+		cachedCluster.Name = result.DefinitionV4.Name
+		cachedCluster.Owner = result.DefinitionV4.Owner
+	}
+	clustercache.CacheCluster(args.APIEndpoint, cachedCluster)
+
 	return result, nil
 }
 
@@ -677,7 +873,7 @@ func validateHAMasters(featureEnabled bool, args *Arguments, v5Definition *types
 	{
 		if v5Definition.MasterNodes == nil && args.MasterHA == nil {
 			// User tries to use the 'master' field in a version that supports HA masters.
-			if featureEnabled && v5Definition.Master != nil {
+			if featureEnabled && v5Definition.Master != nil && !args.Quiet {
 				fmt.Println(color.YellowString("The 'master' attribute is deprecated.\nPlease remove the 'master' attribute from your cluster definition and use the 'master_nodes' attribute instead."))
 			}
 		} else if v5Definition.Master != nil {
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -15,6 +16,8 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/commands/types"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -42,6 +45,8 @@ func Test_CollectArgs(t *testing.T) {
 				APIEndpoint:           "https://foo",
 				AuthToken:             "some-token",
 				CreateDefaultNodePool: true,
+				Concurrency:           defaultConcurrency,
+				WaitTimeout:           clusterwait.DefaultTimeout,
 				Scheme:                "giantswarm",
 				MasterHA:              nil,
 			},
@@ -52,6 +57,8 @@ func Test_CollectArgs(t *testing.T) {
 				APIEndpoint:           "https://foo",
 				AuthToken:             "some-token",
 				CreateDefaultNodePool: true,
+				Concurrency:           defaultConcurrency,
+				WaitTimeout:           clusterwait.DefaultTimeout,
 				Scheme:                "giantswarm",
 				MasterHA:              toBoolPtr(false),
 			},
@@ -67,6 +74,8 @@ func Test_CollectArgs(t *testing.T) {
 				AuthToken:             "some-token",
 				ClusterName:           "ClusterName",
 				CreateDefaultNodePool: true,
+				Concurrency:           defaultConcurrency,
+				WaitTimeout:           clusterwait.DefaultTimeout,
 				Owner:                 "acme",
 				ReleaseVersion:        "1.2.3",
 				Scheme:                "giantswarm",
@@ -81,6 +90,8 @@ func Test_CollectArgs(t *testing.T) {
 				APIEndpoint:           "https://foo",
 				AuthToken:             "some-token",
 				CreateDefaultNodePool: true,
+				Concurrency:           defaultConcurrency,
+				WaitTimeout:           clusterwait.DefaultTimeout,
 				ReleaseVersion:        "1.2.3",
 				Scheme:                "giantswarm",
 				MasterHA:              nil,
@@ -92,6 +103,8 @@ func Test_CollectArgs(t *testing.T) {
 				APIEndpoint:           "https://foo",
 				AuthToken:             "some-token",
 				CreateDefaultNodePool: true,
+				Concurrency:           defaultConcurrency,
+				WaitTimeout:           clusterwait.DefaultTimeout,
 				Scheme:                "giantswarm",
 				MasterHA:              nil,
 				OutputFormat:          "json",
@@ -322,7 +335,7 @@ func Test_CreateClusterSuccessfully(t *testing.T) {
 								Zones: []string{"eu-central-1a", "eu-central-1b", "eu-central-1c"},
 							},
 							Scaling: &types.ScalingDefinition{
-								Min: 3,
+								Min: testutils.Int64Value(3),
 								Max: 10,
 							},
 							NodeSpec: &types.NodeSpec{
@@ -1321,3 +1334,144 @@ func Test_collectArguments(t *testing.T) {
 		t.Errorf("Expected Verbose argument to be false. Got '%t'", argsVerboseFalse.Verbose)
 	}
 }
+
+// Test_printSchema tests printing the JSON Schema via --print-schema.
+func Test_printSchema(t *testing.T) {
+	printed, err := printSchema("")
+	if printed || err != nil {
+		t.Errorf("Expected nothing to be printed without a version, got printed=%t, err=%#v", printed, err)
+	}
+
+	_, err = printSchema("v3")
+	if !IsSchemaVersionInvalid(err) {
+		t.Errorf("Expected schemaVersionInvalidError, got %#v", err)
+	}
+
+	output := testutils.CaptureOutput(func() {
+		printed, err = printSchema("v5")
+	})
+	if !printed || err != nil {
+		t.Fatalf("Expected schema to be printed, got printed=%t, err=%#v", printed, err)
+	}
+	if !strings.Contains(output, `"$schema": "http://json-schema.org/draft-07/schema#"`) || !strings.Contains(output, `"nodepools"`) {
+		t.Errorf("Unexpected schema output: %s", output)
+	}
+}
+
+// Test_CreateClusterDryRun tests that in dry run mode, all requests
+// modifying data are printed, none is sent, and the command runs to the end.
+func Test_CreateClusterDryRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" && r.URL.String() == "/v4/info/" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"general": {"provider": "aws", "availability_zones": {"default": 1, "max": 3}},
+				"features": {"nodepools": {"release_version_minimum": "9.0.0"}}
+			}`))
+		} else if r.Method == "GET" && r.URL.String() == "/v4/releases/" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"timestamp": "2019-09-23T12:00:00Z", "version": "9.0.0", "active": true, "changelog": [], "components": []}]`))
+		} else {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	var out bytes.Buffer
+	client.DryRunFormat = formatting.OutputFormatJSON
+	client.DryRunOutput = &out
+	defer func() {
+		client.DryRunFormat = ""
+		client.DryRunOutput = nil
+	}()
+
+	args := Arguments{
+		APIEndpoint:       mockServer.URL,
+		AuthToken:         "fake token",
+		UserProvidedToken: "fake token",
+		FileSystem:        afero.NewOsFs(),
+		InputYAMLFile:     "testdata/v5_dry_run.yaml",
+		Quiet:             true,
+	}
+
+	result, err := addCluster(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if result.ID != "dryrun" {
+		t.Errorf("Expected ID 'dryrun', got '%s'", result.ID)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Unexpected errors: %v", result.Errors)
+	}
+
+	for _, expected := range []string{`"name": "Dry run cluster"`, `"name": "First node pool"`, `"name": "Second node pool"`, `"key": "value"`} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %s, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+// Test_CreateClusterNodePoolWithoutMin tests that for a node pool definition
+// without a scaling minimum, a minimum of 0 is requested.
+func Test_CreateClusterNodePoolWithoutMin(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" && r.URL.String() == "/v4/info/" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"general": {"provider": "aws", "availability_zones": {"default": 1, "max": 3}},
+				"features": {"nodepools": {"release_version_minimum": "9.0.0"}}
+			}`))
+		} else if r.Method == "GET" && r.URL.String() == "/v4/releases/" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"timestamp": "2019-09-23T12:00:00Z", "version": "9.0.0", "active": true, "changelog": [], "components": []}]`))
+		} else {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	var out bytes.Buffer
+	client.DryRunFormat = formatting.OutputFormatJSON
+	client.DryRunOutput = &out
+	defer func() {
+		client.DryRunFormat = ""
+		client.DryRunOutput = nil
+	}()
+
+	args := Arguments{
+		APIEndpoint:       mockServer.URL,
+		AuthToken:         "fake token",
+		UserProvidedToken: "fake token",
+		FileSystem:        afero.NewOsFs(),
+		InputYAMLFile:     "testdata/v5_without_min.yaml",
+		Quiet:             true,
+	}
+
+	_, err = addCluster(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	for _, expected := range []string{`"min": 0`, `"max": 4`} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %s, got:\n%s", expected, out.String())
+		}
+	}
+}
@@ -50,3 +50,13 @@ var mustProvideSingleMasterTypeError = &microerror.Error{
 func IsMustProvideSingleMasterType(err error) bool {
 	return microerror.Cause(err) == mustProvideSingleMasterTypeError
 }
+
+// schemaVersionInvalidError is used when an unknown definition version is passed to --print-schema.
+var schemaVersionInvalidError = &microerror.Error{
+	Kind: "schemaVersionInvalidError",
+}
+
+// IsSchemaVersionInvalid asserts schemaVersionInvalidError.
+func IsSchemaVersionInvalid(err error) bool {
+	return microerror.Cause(err) == schemaVersionInvalidError
+}
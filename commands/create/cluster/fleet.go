@@ -0,0 +1,314 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/table"
+)
+
+const (
+	fleetResultCreated           = "created"
+	fleetResultCreatedWithErrors = "created-with-errors"
+	fleetResultError             = "error"
+	fleetResultNotReady          = "not-ready"
+)
+
+// fleetResult is the outcome of creating one cluster from a multi-document definition.
+type fleetResult struct {
+	// Index is the position of the document in the definition file, starting at 0.
+	Index int
+	Name  string
+	Owner string
+
+	Result *creationResult
+	Err    error
+	// WaitErr is the error waiting for the cluster to become ready, e. g.
+	// on timeout, when using --wait.
+	WaitErr error
+}
+
+// FleetJSONOutput is the JSON output item per cluster when creating several
+// clusters from a multi-document definition.
+type FleetJSONOutput struct {
+	// Document is the number of the definition document, starting at 1.
+	Document int `json:"document"`
+	// ID of the cluster, if created.
+	ID string `json:"id,omitempty"`
+	// Name of the cluster.
+	Name string `json:"name,omitempty"`
+	// Owner organization of the cluster.
+	Owner string `json:"owner,omitempty"`
+	// Result is 'created', 'created-with-errors', 'not-ready' or 'error'.
+	Result string `json:"result"`
+	// Error is the error which prevented the creation of the cluster.
+	Error *errors.Output `json:"error,omitempty"`
+	// Errors are non-critical errors, e. g. on node pool creation.
+	Errors []*errors.Output `json:"errors,omitempty"`
+}
+
+// addClusters creates one cluster per definition, running at most
+// args.Concurrency creations in parallel. Only v5 definitions are supported.
+// Errors creating single clusters are part of the results.
+func addClusters(args Arguments, definitions []interface{}) ([]*fleetResult, error) {
+	var defs []*types.ClusterDefinitionV5
+	for i, d := range definitions {
+		def, ok := d.(*types.ClusterDefinitionV5)
+		if !ok {
+			return nil, microerror.Maskf(errors.IncompatibleSettingsError, "document %d is not a v5 definition, several clusters can only be created from v5 definitions", i+1)
+		}
+		defs = append(defs, def)
+	}
+
+	// Make sure the provider is known before creating clusters concurrently,
+	// so the configuration doesn't get written in parallel.
+	if config.Config.Provider == "" {
+		clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		auxParams := clientWrapper.DefaultAuxiliaryParams()
+		auxParams.ActivityName = createClusterActivityName
+
+		info, err := clientWrapper.GetInfo(auxParams)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		err = config.Config.SetProvider(info.Payload.General.Provider)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	concurrency := args.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+
+	// Progress output of parallel creations would be interleaved.
+	fleetArgs := args
+	fleetArgs.Quiet = true
+	fleetArgs.Verbose = false
+
+	if !formatting.IsStructured(args.OutputFormat) {
+		fmt.Printf("Creating %d clusters, up to %d at a time\n", len(defs), concurrency)
+	}
+
+	results := make([]*fleetResult, len(defs))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, def := range defs {
+		results[i] = &fleetResult{
+			Index: i,
+			Name:  def.Name,
+			Owner: def.Owner,
+		}
+
+		wg.Add(1)
+		go func(r *fleetResult, def *types.ClusterDefinitionV5) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			r.Result, r.Err = addClusterFromDefinition(fleetArgs, def)
+			if r.Result != nil && r.Result.DefinitionV5 != nil {
+				r.Name = r.Result.DefinitionV5.Name
+				r.Owner = r.Result.DefinitionV5.Owner
+			}
+
+			if r.Err == nil && fleetArgs.Wait {
+				r.WaitErr = waitUntilReady(fleetArgs, r.Result.ID)
+			}
+		}(results[i], def)
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+// resultString returns 'created', 'created-with-errors', 'not-ready' or 'error'.
+func (r *fleetResult) resultString() string {
+	switch {
+	case r.Err != nil:
+		return fleetResultError
+	case r.WaitErr != nil:
+		return fleetResultNotReady
+	case r.Result.HasErrors:
+		return fleetResultCreatedWithErrors
+	}
+
+	return fleetResultCreated
+}
+
+// errorString returns the first line of the error message, or all
+// non-critical error messages.
+func (r *fleetResult) errorString() string {
+	if r.Err != nil {
+		return strings.Split(r.Err.Error(), "\n")[0]
+	}
+	if r.WaitErr != nil {
+		return r.WaitErr.Error()
+	}
+	if r.Result != nil {
+		messages := make([]string, len(r.Result.Errors))
+		for i, err := range r.Result.Errors {
+			messages[i] = err.Error()
+		}
+		return strings.Join(messages, "; ")
+	}
+
+	return ""
+}
+
+// firstError returns the error which prevented the creation of the cluster,
+// the error waiting for it, or the first non-critical error.
+func (r *fleetResult) firstError() error {
+	switch {
+	case r.Err != nil:
+		return r.Err
+	case r.WaitErr != nil:
+		return r.WaitErr
+	case r.Result.HasErrors:
+		return r.Result.Errors[0]
+	}
+
+	return nil
+}
+
+// getFleetOutput renders the results as a table or as JSON.
+func getFleetOutput(results []*fleetResult, outputFormat string) (string, error) {
+	if formatting.IsStructured(outputFormat) {
+		output := []FleetJSONOutput{}
+		for _, r := range results {
+			item := FleetJSONOutput{
+				Document: r.Index + 1,
+				Name:     r.Name,
+				Owner:    r.Owner,
+				Result:   r.resultString(),
+			}
+			if r.Err != nil {
+				item.Error = errors.NewOutput(r.Err)
+			} else {
+				item.ID = r.Result.ID
+				for _, err := range r.Result.Errors {
+					item.Errors = append(item.Errors, errors.NewOutput(err))
+				}
+				if r.WaitErr != nil {
+					item.Error = errors.NewOutput(r.WaitErr)
+				}
+			}
+			output = append(output, item)
+		}
+
+		outputBytes, err := formatting.Marshal(outputFormat, output)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return string(outputBytes), nil
+	}
+
+	t := table.New()
+	t.SetColumns([]table.Column{
+		{Name: "document", DisplayName: "DOC"},
+		{Name: "id", DisplayName: "ID"},
+		{Name: "name", DisplayName: "NAME"},
+		{Name: "owner", DisplayName: "OWNER"},
+		{Name: "result", DisplayName: "RESULT"},
+		{Name: "error", DisplayName: "ERROR"},
+	})
+
+	var rows [][]string
+	for _, r := range results {
+		id := "n/a"
+		if r.Result != nil {
+			id = r.Result.ID
+		}
+
+		result := r.resultString()
+		switch result {
+		case fleetResultCreated:
+			result = color.GreenString(result)
+		case fleetResultCreatedWithErrors:
+			result = color.YellowString(result)
+		default:
+			result = color.RedString(result)
+		}
+
+		rows = append(rows, []string{
+			strconv.Itoa(r.Index + 1),
+			id,
+			valueOrPlaceholder(r.Name),
+			valueOrPlaceholder(r.Owner),
+			result,
+			valueOrPlaceholder(r.errorString()),
+		})
+	}
+	t.SetRows(rows)
+
+	return t.String(), nil
+}
+
+// printFleetResult prints the results of creating several clusters and
+// exits with a non-zero exit code if any of them failed.
+func printFleetResult(results []*fleetResult, outputFormat string) {
+	output, err := getFleetOutput(results, outputFormat)
+	if err != nil {
+		fmt.Println(color.RedString(err.Error()))
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(output)
+
+	created := 0
+	for _, r := range results {
+		if r.Err == nil {
+			created++
+		}
+	}
+
+	var firstErr error
+	for _, r := range results {
+		if err := r.firstError(); err != nil {
+			firstErr = err
+			break
+		}
+	}
+	hasErrors := firstErr != nil
+
+	if !formatting.IsStructured(outputFormat) {
+		summary := fmt.Sprintf("\n%d of %d clusters have been created.", created, len(results))
+		if hasErrors {
+			fmt.Println(color.YellowString(summary))
+		} else {
+			fmt.Println(color.GreenString(summary))
+		}
+	}
+
+	if hasErrors {
+		os.Exit(errors.ExitCode(firstErr))
+	}
+}
+
+func valueOrPlaceholder(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+	return s
+}
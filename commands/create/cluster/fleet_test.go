@@ -0,0 +1,178 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const fleetDefinitionYAML = `api_version: v5
+owner: acme
+name: load-test-1
+---
+# The second cluster
+api_version: v5
+owner: acme
+name: load-test-2
+---
+api_version: v5
+owner: unknown-org
+name: load-test-3
+`
+
+// Test_addClusters tests creating several clusters from a multi-document definition.
+func Test_addClusters(t *testing.T) {
+	var mutex sync.Mutex
+	created := map[string]bool{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"general": {"provider": "aws", "availability_zones": {"default": 1, "max": 3}},
+				"features": {"nodepools": {"release_version_minimum": "9.0.0"}}
+			}`))
+		case r.Method == "GET" && r.URL.String() == "/v4/releases/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"timestamp": "2019-09-23T12:00:00Z", "version": "10.0.0", "active": true, "changelog": [], "components": []}]`))
+		case r.Method == "POST" && r.URL.String() == "/v5/clusters/":
+			body, _ := ioutil.ReadAll(r.Body)
+			request := map[string]interface{}{}
+			json.Unmarshal(body, &request)
+
+			if request["owner"] == "unknown-org" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "organization not found"}`))
+				return
+			}
+
+			name := request["name"].(string)
+			mutex.Lock()
+			created[name] = true
+			mutex.Unlock()
+
+			id := strings.TrimPrefix(name, "load-test-")
+			w.Header().Set("Location", "/v5/clusters/"+id+"/")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(fmt.Sprintf(`{"id": "%s", "owner": "acme", "name": "%s", "release_version": "10.0.0"}`, id, name)))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	definitions, err := readDefinitionsFromYAML([]byte(fleetDefinitionYAML), clusterdefinition.RenderOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error reading definitions: %s", err)
+	}
+	if len(definitions) != 3 {
+		t.Fatalf("Expected 3 definitions, got %d", len(definitions))
+	}
+
+	flags.APIEndpoint = mockServer.URL
+	args := Arguments{
+		APIEndpoint: mockServer.URL,
+		AuthToken:   "token",
+		Concurrency: 2,
+		FileSystem:  fs,
+	}
+
+	results, err := addClusters(args, definitions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(created) != 2 || !created["load-test-1"] || !created["load-test-2"] {
+		t.Errorf("Expected clusters load-test-1 and load-test-2 to be created, got %v", created)
+	}
+
+	expectedResults := []string{fleetResultCreated, fleetResultCreated, fleetResultError}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("Result %d has index %d", i, r.Index)
+		}
+		if r.resultString() != expectedResults[i] {
+			t.Errorf("Expected result %d to be '%s', got '%s' (error: %v)", i, expectedResults[i], r.resultString(), r.Err)
+		}
+	}
+
+	tableOutput, err := getFleetOutput(results, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, s := range []string{"DOC", "load-test-2", "unknown-org", "error"} {
+		if !strings.Contains(tableOutput, s) {
+			t.Errorf("Expected table output to contain '%s', got:\n%s", s, tableOutput)
+		}
+	}
+
+	jsonOutput, err := getFleetOutput(results, formatting.OutputFormatJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var items []FleetJSONOutput
+	err = json.Unmarshal([]byte(jsonOutput), &items)
+	if err != nil {
+		t.Fatalf("Could not parse JSON output: %s", err)
+	}
+	if len(items) != 3 || items[0].ID != "1" || items[2].Result != fleetResultError || items[2].Error == nil || items[2].Error.Class != "not_found" {
+		t.Errorf("Unexpected JSON output: %s", jsonOutput)
+	}
+}
+
+// Test_addClustersV4 tests that only v5 definitions can be used to create several clusters.
+func Test_addClustersV4(t *testing.T) {
+	definitions := []interface{}{
+		&types.ClusterDefinitionV5{Owner: "acme"},
+		&types.ClusterDefinitionV4{Owner: "acme"},
+	}
+
+	_, err := addClusters(Arguments{}, definitions)
+	if err == nil || !strings.Contains(err.Error(), "document 2") {
+		t.Errorf("Expected error on document 2, got %#v", err)
+	}
+}
+
+// Test_readDefinitionsFromYAMLErrors tests that errors name the document they occur in.
+func Test_readDefinitionsFromYAMLErrors(t *testing.T) {
+	data := []byte(`api_version: v5
+owner: acme
+---
+api_version: v5
+owner: acme
+nodepools:
+- name: workers
+  node_specs: {}
+`)
+
+	_, err := readDefinitionsFromYAML(data, clusterdefinition.RenderOptions{})
+	if !IsInvalidV5DefinitionYAML(err) {
+		t.Fatalf("Expected invalidV5DefinitionYAMLError, got %#v", err)
+	}
+	// The line number refers to the position in the whole file.
+	if !strings.Contains(err.Error(), "document 2") || !strings.Contains(err.Error(), "line 8") {
+		t.Errorf("Expected document number and line in error, got '%s'", err.Error())
+	}
+}
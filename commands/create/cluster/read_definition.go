@@ -3,16 +3,40 @@ package cluster
 import (
 	"bufio"
 	"os"
+	"strings"
 
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/afero"
 	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
-// readDefinitionFromYAML reads a cluster definition from YAML data.
-func readDefinitionFromYAML(yamlBytes []byte) (interface{}, error) {
+// getRenderOptions returns the options for rendering a definition template,
+// based on the --values and --set flags and the environment.
+func getRenderOptions(args Arguments) (clusterdefinition.RenderOptions, error) {
+	options := clusterdefinition.RenderOptions{
+		LookupEnv: os.LookupEnv,
+		Set:       args.SetValues,
+	}
+
+	if args.ValuesFile != "" {
+		values, err := clusterdefinition.ReadValues(args.FileSystem, args.ValuesFile)
+		if err != nil {
+			return options, microerror.Mask(err)
+		}
+		options.Values = values
+	}
+
+	return options, nil
+}
+
+// readDefinitionFromYAML reads a cluster definition from YAML data. The
+// definition is validated using doc, the parsed YAML as returned by
+// clusterdefinition.Render, so that errors point into the user's file.
+func readDefinitionFromYAML(yamlBytes []byte, doc *yamlv3.Node) (interface{}, error) {
 	// First unmarshal into a map so we can detect v4 or v5 schema.
 	rawMap := map[string]interface{}{}
 
@@ -24,6 +48,11 @@ func readDefinitionFromYAML(yamlBytes []byte) (interface{}, error) {
 	// Detecting v5 purely based on the existence of the 'api_version' key.
 	if _, apiVersionOK := rawMap["api_version"]; apiVersionOK {
 		// v5
+		err = clusterdefinition.ValidateDocument(doc, clusterdefinition.SchemaV5())
+		if err != nil {
+			return nil, microerror.Maskf(invalidV5DefinitionYAMLError, err.Error())
+		}
+
 		def := &types.ClusterDefinitionV5{}
 		err := yaml.UnmarshalStrict(yamlBytes, def)
 		if err != nil {
@@ -34,6 +63,11 @@ func readDefinitionFromYAML(yamlBytes []byte) (interface{}, error) {
 	}
 
 	// v4 (default/fall back)
+	err = clusterdefinition.ValidateDocument(doc, clusterdefinition.SchemaV4())
+	if err != nil {
+		return nil, microerror.Maskf(invalidDefinitionYAMLError, err.Error())
+	}
+
 	def := &types.ClusterDefinitionV4{}
 	err = yaml.UnmarshalStrict(yamlBytes, def)
 	if err != nil {
@@ -44,19 +78,88 @@ func readDefinitionFromYAML(yamlBytes []byte) (interface{}, error) {
 
 }
 
-// readDefinitionFromFile reads a cluster definition from a YAML file.
-func readDefinitionFromFile(fs afero.Fs, path string) (interface{}, error) {
+// readDefinitions reads the cluster definitions given via --file, either
+// from a file or from standard input. Without --file, no definitions are
+// returned.
+func readDefinitions(args Arguments) ([]interface{}, error) {
+	if args.InputYAMLFile == "" {
+		return nil, nil
+	}
+
+	renderOptions, err := getRenderOptions(args)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var definitions []interface{}
+	if args.InputYAMLFile == standardInputSpecialPath {
+		definitions, err = readDefinitionsFromSTDIN(renderOptions)
+	} else {
+		definitions, err = readDefinitionsFromFile(args.FileSystem, args.InputYAMLFile, renderOptions)
+	}
+	if clusterdefinition.IsRenderFailed(err) {
+		return nil, microerror.Mask(err)
+	} else if err != nil {
+		return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
+	}
+
+	return definitions, nil
+}
+
+// readDefinitionsFromYAML reads the cluster definitions from YAML data,
+// which can contain several documents separated by '---'. Variables and
+// overrides are applied to each document according to renderOptions
+// before parsing.
+func readDefinitionsFromYAML(data []byte, renderOptions clusterdefinition.RenderOptions) ([]interface{}, error) {
+	documents := clusterdefinition.SplitDocuments(data)
+	if len(documents) < 2 {
+		// Keep the content (or lack thereof) of single documents as is.
+		documents = [][]byte{data}
+	}
+
+	var definitions []interface{}
+	for i, document := range documents {
+		rendered, doc, err := clusterdefinition.Render(document, renderOptions)
+		if err != nil {
+			return nil, inDocument(err, i, len(documents))
+		}
+
+		def, err := readDefinitionFromYAML(rendered, doc)
+		if err != nil {
+			return nil, inDocument(err, i, len(documents))
+		}
+
+		definitions = append(definitions, def)
+	}
+
+	return definitions, nil
+}
+
+// inDocument adds the document number to an error when reading several
+// documents, keeping the error's kind.
+func inDocument(err error, index, count int) error {
+	kind, ok := microerror.Cause(err).(*microerror.Error)
+	if count < 2 || !ok {
+		return microerror.Mask(err)
+	}
+
+	annotation := strings.TrimPrefix(err.Error(), kind.Error()+": ")
+	return microerror.Maskf(kind, "document %d: %s", index+1, annotation)
+}
+
+// readDefinitionsFromFile reads cluster definitions from a YAML file.
+func readDefinitionsFromFile(fs afero.Fs, path string, renderOptions clusterdefinition.RenderOptions) ([]interface{}, error) {
 	data, err := afero.ReadFile(fs, path)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	return readDefinitionFromYAML(data)
+	return readDefinitionsFromYAML(data, renderOptions)
 }
 
-// readDefinitionFromSTDIN reads a YAML definition coming via standard input.
+// readDefinitionsFromSTDIN reads YAML definitions coming via standard input.
 // TODO: provide unit test
-func readDefinitionFromSTDIN() (interface{}, error) {
+func readDefinitionsFromSTDIN(renderOptions clusterdefinition.RenderOptions) ([]interface{}, error) {
 	yamlString := ""
 	scanner := bufio.NewScanner(os.Stdin)
 
@@ -68,10 +171,5 @@ func readDefinitionFromSTDIN() (interface{}, error) {
 		return nil, microerror.Mask(err)
 	}
 
-	def, err := readDefinitionFromYAML([]byte(yamlString))
-	if err != nil {
-		return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
-	}
-
-	return def, nil
+	return readDefinitionsFromYAML([]byte(yamlString), renderOptions)
 }
@@ -7,11 +7,14 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
 
 	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/testutils"
 )
 
-// Test_ReadDefinitionFiles tests the readDefinitionFromFile with all
+// Test_ReadDefinitionFiles tests the readDefinitionsFromFile with all
 // YAML files in the testdata directory.
 func Test_readDefinitionFromFile(t *testing.T) {
 	basePath := "testdata"
@@ -69,13 +72,17 @@ func Test_readDefinitionFromFile(t *testing.T) {
 			fileName:     "invalid03.yaml",
 			errorMatcher: IsInvalidDefinitionYAML,
 		},
+		{
+			fileName:     "invalid04.yaml",
+			errorMatcher: IsInvalidV5DefinitionYAML,
+		},
 	}
 
 	for i, tc := range testCases {
 		t.Logf("Case %d, file %s", i, tc.fileName)
 		path := basePath + "/" + tc.fileName
 
-		_, err := readDefinitionFromFile(fs, path)
+		_, err := readDefinitionsFromFile(fs, path, clusterdefinition.RenderOptions{})
 		if tc.errorMatcher != nil {
 			if !tc.errorMatcher(err) {
 				t.Errorf("Unexpected error in case %d, file %s: %s", i, tc.fileName, err)
@@ -121,7 +128,7 @@ scaling:
 				ReleaseVersion:    "1.2.3",
 				AvailabilityZones: 3,
 				Scaling: types.ScalingDefinition{
-					Min: 3,
+					Min: testutils.Int64Value(3),
 					Max: 5,
 				},
 			},
@@ -163,7 +170,7 @@ workers:
 
 	for i, tc := range testCases {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
-			def, err := readDefinitionFromYAML(tc.inputYAML)
+			def, err := readDefinitionFromYAML(tc.inputYAML, parseDocument(t, tc.inputYAML))
 			if tc.errorMatcher != nil {
 				if !tc.errorMatcher(err) {
 					t.Errorf("Case %d - Expected error, got %v", i, err)
@@ -246,7 +253,7 @@ nodepools:
 					{
 						Name:              "Database",
 						AvailabilityZones: &types.AvailabilityZonesDefinition{Zones: []string{"my-zone-1a", "my-zone-1b", "my-zone-1c"}},
-						Scaling:           &types.ScalingDefinition{Min: 3, Max: 10},
+						Scaling:           &types.ScalingDefinition{Min: testutils.Int64Value(3), Max: 10},
 						NodeSpec:          &types.NodeSpec{AWS: &types.AWSSpecificDefinition{InstanceType: "m5.superlarge"}},
 					},
 					{
@@ -291,7 +298,7 @@ nodepools:
 					{
 						Name:              "Database",
 						AvailabilityZones: &types.AvailabilityZonesDefinition{Zones: []string{"my-zone-1a", "my-zone-1b", "my-zone-1c"}},
-						Scaling:           &types.ScalingDefinition{Min: 3, Max: 10},
+						Scaling:           &types.ScalingDefinition{Min: testutils.Int64Value(3), Max: 10},
 						NodeSpec:          &types.NodeSpec{AWS: &types.AWSSpecificDefinition{InstanceType: "m5.superlarge"}},
 					},
 					{
@@ -513,7 +520,7 @@ nodepools:
 
 	for i, tc := range testCases {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
-			def, err := readDefinitionFromYAML(tc.inputYAML)
+			def, err := readDefinitionFromYAML(tc.inputYAML, parseDocument(t, tc.inputYAML))
 			if err != nil {
 				t.Errorf("Case %d - Unexpected error %v", i, err)
 			}
@@ -539,3 +546,69 @@ func Test_CreateFromBadYAML01(t *testing.T) {
 		t.Fatalf("expected owner to be empty, got %q", def.Owner)
 	}
 }
+
+// Test_readDefinitionFromFileWithRenderOptions tests that variables and
+// overrides are applied before the definition is parsed.
+func Test_readDefinitionFromFileWithRenderOptions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "cluster.yaml", []byte(`api_version: v5
+owner: ${OWNER}
+nodepools:
+- name: workers
+  scaling:
+    min: 3
+    max: 5
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = afero.WriteFile(fs, "values.yaml", []byte("OWNER: acme\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := getRenderOptions(Arguments{
+		FileSystem: fs,
+		ValuesFile: "values.yaml",
+		SetValues:  []string{"nodepools[0].scaling.max=10"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	defs, err := readDefinitionsFromFile(fs, "cluster.yaml", options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(defs) != 1 {
+		t.Fatalf("Expected 1 definition, got %d", len(defs))
+	}
+
+	defV5, ok := defs[0].(*types.ClusterDefinitionV5)
+	if !ok {
+		t.Fatalf("Expected v5 definition, got %T", defs[0])
+	}
+	if defV5.Owner != "acme" {
+		t.Errorf("Expected owner 'acme', got '%s'", defV5.Owner)
+	}
+	if defV5.NodePools[0].Scaling.Max != 10 {
+		t.Errorf("Expected scaling max 10, got %d", defV5.NodePools[0].Scaling.Max)
+	}
+
+	// Undefined variables must be reported.
+	_, err = readDefinitionsFromFile(fs, "cluster.yaml", clusterdefinition.RenderOptions{})
+	if !clusterdefinition.IsRenderFailed(err) {
+		t.Errorf("Expected renderFailedError, got %#v", err)
+	}
+}
+
+// parseDocument parses YAML data the way clusterdefinition.Render does
+// without options.
+func parseDocument(t *testing.T, data []byte) *yamlv3.Node {
+	_, doc, err := clusterdefinition.Render(data, clusterdefinition.RenderOptions{})
+	if err != nil {
+		t.Fatalf("Could not parse YAML: %s", err)
+	}
+
+	return doc
+}
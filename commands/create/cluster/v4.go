@@ -47,9 +47,9 @@ func createAddClusterBodyV4(d *types.ClusterDefinitionV4) *models.V4AddClusterRe
 		a.Owner = &d.Owner
 		a.ReleaseVersion = d.ReleaseVersion
 
-		if d.Scaling.Min > 0 {
+		if d.Scaling.Min != nil && *d.Scaling.Min > 0 {
 			a.Scaling = &models.V4AddClusterRequestScaling{
-				Min: &d.Scaling.Min,
+				Min: d.Scaling.Min,
 				Max: d.Scaling.Max,
 			}
 		}
@@ -108,7 +108,7 @@ func addClusterV4(def *types.ClusterDefinitionV4, args Arguments, clientWrapper
 		fmt.Println()
 	}
 
-	if args.OutputFormat != formatting.OutputFormatJSON {
+	if !formatting.IsStructured(args.OutputFormat) {
 		fmt.Printf("Requesting new cluster for organization '%s'\n", color.CyanString(def.Owner))
 	}
 
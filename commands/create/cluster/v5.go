@@ -11,6 +11,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/commands/types"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
 	"github.com/giantswarm/gsctl/pkg/provider"
 )
 
@@ -51,157 +52,63 @@ func updateDefinitionFromFlagsV5(def *types.ClusterDefinitionV5, flags definitio
 		}
 
 	case provider.Azure:
-		def.MasterNodes = &types.MasterNodes{
-			Azure: &types.MasterNodesAzure{
-				AvailabilityZonesUnspecified: flags.maxSupportedAZs < 1,
-			},
-		}
-	}
-}
-
-func createAddClusterBodyV5(def *types.ClusterDefinitionV5) *models.V5AddClusterRequest {
-	b := &models.V5AddClusterRequest{
-		Owner:          &def.Owner,
-		Name:           def.Name,
-		ReleaseVersion: def.ReleaseVersion,
-	}
-
-	if def.Master != nil {
-		b.Master = &models.V5AddClusterRequestMaster{
-			AvailabilityZone: def.Master.AvailabilityZone,
-		}
-	}
-
-	if def.MasterNodes != nil {
-		b.MasterNodes = &models.V5AddClusterRequestMasterNodes{
-			HighAvailability:  def.MasterNodes.HighAvailability,
-			AvailabilityZones: def.MasterNodes.AvailabilityZones,
-		}
-
-		if def.MasterNodes.Azure != nil {
-			b.MasterNodes.Azure = &models.V5AddClusterRequestMasterNodesAzure{
-				AvailabilityZonesUnspecified: def.MasterNodes.Azure.AvailabilityZonesUnspecified,
-			}
-		}
+		clusterdefinition.SetProviderDefaultsV5(def, flags.provider, flags.maxSupportedAZs)
 	}
-
-	return b
 }
 
-func createAddNodePoolBody(def *types.NodePoolDefinition) *models.V5AddNodePoolRequest {
-	b := &models.V5AddNodePoolRequest{
-		Name:              def.Name,
-		AvailabilityZones: &models.V5AddNodePoolRequestAvailabilityZones{},
-		Scaling:           &models.V5AddNodePoolRequestScaling{},
-		NodeSpec:          &models.V5AddNodePoolRequestNodeSpec{},
-	}
-
-	if def.AvailabilityZones != nil {
-		if def.AvailabilityZones.Number != 0 {
-			b.AvailabilityZones.Number = def.AvailabilityZones.Number
-		}
-		if len(def.AvailabilityZones.Zones) != 0 {
-			b.AvailabilityZones.Zones = def.AvailabilityZones.Zones
-		}
-	}
-
-	if def.Scaling != nil {
-		if def.Scaling.Min >= 0 {
-			b.Scaling.Min = &def.Scaling.Min
-		}
-		if def.Scaling.Max != 0 {
-			b.Scaling.Max = def.Scaling.Max
-		}
-	}
-
-	if def.NodeSpec != nil {
-		if def.NodeSpec.AWS != nil {
-			b.NodeSpec.Aws = &models.V5AddNodePoolRequestNodeSpecAws{}
-
-			if def.NodeSpec.AWS.InstanceDistribution != nil {
-				b.NodeSpec.Aws.InstanceDistribution = &models.V5AddNodePoolRequestNodeSpecAwsInstanceDistribution{
-					OnDemandBaseCapacity:                &def.NodeSpec.AWS.InstanceDistribution.OnDemandBaseCapacity,
-					OnDemandPercentageAboveBaseCapacity: &def.NodeSpec.AWS.InstanceDistribution.OnDemandPercentageAboveBaseCapacity,
-				}
-			}
-
-			if def.NodeSpec.AWS.InstanceType != "" {
-				b.NodeSpec.Aws.InstanceType = def.NodeSpec.AWS.InstanceType
-			}
-
-			b.NodeSpec.Aws.UseAlikeInstanceTypes = &def.NodeSpec.AWS.UseAlikeInstanceTypes
-		}
-
-		if def.NodeSpec.Azure != nil {
-			b.NodeSpec.Azure = &models.V5AddNodePoolRequestNodeSpecAzure{}
-			if def.NodeSpec.Azure.VMSize != "" {
-				b.NodeSpec.Azure.VMSize = def.NodeSpec.Azure.VMSize
-			}
-			if def.NodeSpec.Azure.AzureSpotInstances != nil {
-				b.NodeSpec.Azure.SpotInstances = &models.V5AddNodePoolRequestNodeSpecAzureSpotInstances{
-					Enabled:  &def.NodeSpec.Azure.AzureSpotInstances.Enabled,
-					MaxPrice: &def.NodeSpec.Azure.AzureSpotInstances.MaxPrice,
-				}
-			}
-		}
-	}
-
-	return b
-}
-
-func addClusterV5(def *types.ClusterDefinitionV5, args Arguments, clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams) (string, bool, error) {
+// addClusterV5 creates a cluster with node pools and labels. Besides the cluster ID,
+// it returns non-critical errors, e. g. when a node pool could not be created.
+func addClusterV5(def *types.ClusterDefinitionV5, args Arguments, clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams) (string, []error, error) {
 	// Validate definition
 	if def.Owner == "" {
-		return "", true, microerror.Mask(errors.ClusterOwnerMissingError)
+		return "", nil, microerror.Mask(errors.ClusterOwnerMissingError)
 	}
 
-	clusterRequestBody := createAddClusterBodyV5(def)
+	clusterRequestBody := clusterdefinition.AddClusterRequestV5(def)
 
-	if args.OutputFormat != formatting.OutputFormatJSON {
+	if !formatting.IsStructured(args.OutputFormat) && !args.Quiet {
 		fmt.Printf("Requesting new cluster for organization '%s'\n", color.CyanString(def.Owner))
 	}
 
 	response, err := clientWrapper.CreateClusterV5(clusterRequestBody, auxParams)
 	if err != nil {
-		return "", true, microerror.Mask(err)
+		return "", nil, microerror.Mask(err)
 	}
 
-	hasErrors := false
+	var nonCriticalErrors []error
+	reportError := func(err error) {
+		nonCriticalErrors = append(nonCriticalErrors, err)
+		if !args.Quiet {
+			fmt.Println(color.RedString(err.Error()))
+		}
+	}
 
 	// Create node pools.
 	if def.NodePools != nil && len(def.NodePools) > 0 {
 		for i, np := range def.NodePools {
-			nodePoolRequestBody := createAddNodePoolBody(np)
+			nodePoolRequestBody := clusterdefinition.AddNodePoolRequest(np)
 
-			if args.OutputFormat != formatting.OutputFormatJSON {
+			if !formatting.IsStructured(args.OutputFormat) && !args.Quiet {
 				fmt.Printf("Adding node pool %d\n", i+1)
 			}
 
 			npResponse, err := clientWrapper.CreateNodePool(response.Payload.ID, nodePoolRequestBody, auxParams)
 			if err != nil {
-				fmt.Println(color.RedString("Error creating node pool %d: %s", i+1, err.Error()))
-				hasErrors = true
+				reportError(fmt.Errorf("Error creating node pool %d: %w", i+1, err))
 			} else if args.Verbose {
 				fmt.Println(color.WhiteString("Added node pool %d with ID %s named '%s'", i+1, npResponse.Payload.ID, npResponse.Payload.Name))
 			}
 		}
 	} else if args.CreateDefaultNodePool {
-		if args.OutputFormat != formatting.OutputFormatJSON {
+		if !formatting.IsStructured(args.OutputFormat) && !args.Quiet {
 			fmt.Println("Adding a default node pool")
 		}
 
-		nodePoolRequestBody := &models.V5AddNodePoolRequest{}
-
-		if response.Payload.MasterNodes != nil && len(response.Payload.MasterNodes.AvailabilityZones) < 1 {
-			nodePoolRequestBody.AvailabilityZones = &models.V5AddNodePoolRequestAvailabilityZones{
-				Number: -1,
-			}
-		}
+		nodePoolRequestBody := clusterdefinition.DefaultNodePoolRequest(response.Payload)
 
 		npResponse, err := clientWrapper.CreateNodePool(response.Payload.ID, nodePoolRequestBody, auxParams)
 		if err != nil {
-			fmt.Println(color.RedString("Error creating default node pool: %s", err.Error()))
-			hasErrors = true
+			reportError(fmt.Errorf("Error creating default node pool: %w", err))
 		} else if args.Verbose {
 			fmt.Println(color.WhiteString("Added default node pool with ID %s", npResponse.Payload.ID))
 		}
@@ -212,13 +119,12 @@ func addClusterV5(def *types.ClusterDefinitionV5, args Arguments, clientWrapper
 		labelsRequest := models.V5SetClusterLabelsRequest{Labels: def.Labels}
 		_, err := clientWrapper.UpdateClusterLabels(response.Payload.ID, &labelsRequest, auxParams)
 		if err != nil {
-			fmt.Println(color.RedString("Error attaching labels %s", err.Error()))
-			hasErrors = true
+			reportError(fmt.Errorf("Error attaching labels %w", err))
 		} else if args.Verbose {
 			fmt.Println(color.WhiteString("Attached labels to cluster with ID %s named '%s'", response.Payload.ID, response.Payload.Name))
 		}
 	}
 
-	return response.Payload.ID, hasErrors, nil
+	return response.Payload.ID, nonCriticalErrors, nil
 
 }
@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+)
+
+// waitInterval is the time between two status requests when waiting.
+var waitInterval = clusterwait.DefaultInterval
+
+// waitUntilReady waits for the master nodes and node pools of the new
+// cluster to become ready. Progress is printed unless the output format is
+// JSON or args.Quiet is set.
+func waitUntilReady(args Arguments, clusterID string) error {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = createClusterActivityName
+
+	waitConfig := clusterwait.Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     clusterID,
+		Timeout:       args.WaitTimeout,
+		Interval:      waitInterval,
+	}
+
+	if !formatting.IsStructured(args.OutputFormat) && !args.Quiet {
+		waitConfig.Progress = func(status string) {
+			fmt.Println(status)
+		}
+	}
+
+	_, err = clusterwait.UntilReady(waitConfig, "")
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
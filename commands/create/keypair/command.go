@@ -55,6 +55,7 @@ type Arguments struct {
 	clusterNameOrID          string
 	commonNamePrefix         string
 	description              string
+	dryRun                   bool
 	fileSystem               afero.Fs
 	force                    bool
 	scheme                   string
@@ -91,6 +92,7 @@ func collectArguments() (Arguments, error) {
 		clusterNameOrID:          flags.ClusterID,
 		commonNamePrefix:         flags.CNPrefix,
 		description:              description,
+		dryRun:                   flags.DryRun != "",
 		fileSystem:               config.FileSystem,
 		scheme:                   scheme,
 		ttlHours:                 int32(ttl.Hours()),
@@ -142,7 +144,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 		} else {
 			fmt.Println(color.RedString(argsErr.Error()))
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(argsErr))
 	}
 
 	if !arguments.force && arguments.ttlHours >= maxSafeTTLHours {
@@ -182,7 +184,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func verifyPreconditions(args Arguments) error {
@@ -231,7 +233,11 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if arguments.dryRun {
+		return
 	}
 
 	// Success output
@@ -293,6 +299,12 @@ func createKeypair(args Arguments) (createKeypairResult, error) {
 	result.id = response.Payload.ID
 	result.ttlHours = uint(response.Payload.TTLHours)
 
+	// In dry run mode, no key pair has been created, so there is nothing
+	// to store.
+	if args.dryRun {
+		return result, nil
+	}
+
 	// store credentials to file
 	result.caCertPath = util.StoreCaCertificate(args.fileSystem, config.CertsDirPath,
 		clusterID, response.Payload.CertificateAuthorityData)
@@ -4,7 +4,6 @@ package kubeconfig
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -105,6 +104,7 @@ type Arguments struct {
 	cnPrefix          string
 	contextName       string
 	description       string
+	dryRun            bool
 	fileSystem        afero.Fs
 	force             bool
 	internalAPI       bool
@@ -169,6 +169,7 @@ func collectArguments(cmd *cobra.Command) (Arguments, error) {
 		cnPrefix:          flags.CNPrefix,
 		contextName:       contextName,
 		description:       description,
+		dryRun:            flags.DryRun != "",
 		fileSystem:        config.FileSystem,
 		force:             flags.Force,
 		internalAPI:       flags.InternalAPI,
@@ -178,7 +179,7 @@ func collectArguments(cmd *cobra.Command) (Arguments, error) {
 		ttlHours:          int32(ttl.Hours()),
 		useKubie:          flags.UseKubie,
 		userProvidedToken: flags.Token,
-		verbose:           flags.OutputFormat != formatting.OutputFormatJSON && flags.Verbose,
+		verbose:           !formatting.IsStructured(flags.OutputFormat) && flags.Verbose,
 	}, nil
 }
 
@@ -210,7 +211,7 @@ type JSONOutput struct {
 	// KubeConfig is a string containing the kubeconfig
 	KubeConfig string `json:"kubeconfig,omitempty"`
 	// Error which occured
-	Error error `json:"error,omitempty"`
+	Error *errors.Output `json:"error,omitempty"`
 }
 
 func init() {
@@ -225,7 +226,7 @@ func init() {
 	Command.Flags().BoolVarP(&flags.InternalAPI, "internal-api", "", false, "If set, kubeconfig will be issued with the internal Kubernetes API address instead of the public one.")
 	Command.Flags().BoolVarP(&flags.UseKubie, "kubie", "", false, "Use kubie to set context (requires kubie binary in your path)")
 	Command.Flags().StringVarP(&flags.TTL, "ttl", "", "1d", "Lifetime of the created key pair, e.g. 3h. Allowed units: h, d, w, m, y.")
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' will change output to be JSON formatted.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' or '%s' will change output to be JSON or YAML formatted. Use '%s=<expression>' or '%s=<template>' to extract fields.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
 
 	Command.MarkFlagRequired("cluster")
 
@@ -235,7 +236,9 @@ func init() {
 
 // createKubeconfigPreRunOutput shows our pre-check results
 func createKubeconfigPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "create kubeconfig", "login", "https://docs.giantswarm.io/ui-api/kubectl-gs/login/"))
+	// The notice goes to stderr, so that it does not end up in output
+	// processed by other tools, like JSON or extracted fields.
+	fmt.Fprint(os.Stderr, util.GetDeprecatedNotice(config.Config.Provider, "create kubeconfig", "login", "https://docs.giantswarm.io/ui-api/kubectl-gs/login/"))
 
 	var argsErr error
 
@@ -250,7 +253,7 @@ func createKubeconfigPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		} else {
 			fmt.Println(color.RedString(argsErr.Error()))
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(argsErr))
 	}
 
 	if !arguments.force && arguments.ttlHours >= maxSafeTTLHours {
@@ -299,7 +302,7 @@ func createKubeconfigPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 
 }
 
@@ -315,8 +318,8 @@ func verifyCreateKubeconfigPreconditions(args Arguments, cmdLineArgs []string) e
 	if args.clusterNameOrID == "" {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	}
-	if args.outputFormat != "" && args.outputFormat != formatting.OutputFormatJSON {
-		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is is invalid for gsctl create kubeconfig. Valid options: '%s'", args.outputFormat, formatting.OutputFormatJSON))
+	if args.outputFormat != "" && !formatting.IsStructured(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is invalid for gsctl create kubeconfig. Valid options: '%s', '%s', '%s=<expression>', '%s=<template>'", args.outputFormat, formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
 	}
 
 	// validate CN prefix character set
@@ -350,8 +353,11 @@ func createKubeconfigRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	ctx := context.Background()
 
 	result, err := createKubeconfig(ctx, arguments)
+	if err == nil && arguments.dryRun {
+		return
+	}
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
+	if formatting.IsStructured(arguments.outputFormat) {
 		printJSONOutput(result, err)
 		return
 	}
@@ -396,7 +402,7 @@ func createKubeconfigRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	// Success output
@@ -463,20 +469,20 @@ func printJSONOutput(result createKubeconfigResult, creationErr error) {
 
 	// handle errors
 	if creationErr != nil {
-		jsonResult = JSONOutput{Result: "error", Error: creationErr}
+		jsonResult = JSONOutput{Result: "error", Error: errors.NewOutput(creationErr)}
 	} else {
 		jsonResult = JSONOutput{Result: "ok", KubeConfig: string(result.selfContainedYAMLBytes)}
 	}
 
-	outputBytes, err = json.MarshalIndent(jsonResult, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	outputBytes, err = formatting.Marshal(arguments.outputFormat, jsonResult)
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(string(outputBytes))
 	if creationErr != nil {
-		os.Exit(1)
+		os.Exit(errors.ExitCode(creationErr))
 	}
 }
 
@@ -572,7 +578,13 @@ func createKubeconfig(ctx context.Context, args Arguments) (createKubeconfigResu
 	result.id = response.Payload.ID
 	result.ttlHours = uint(response.Payload.TTLHours)
 
-	if args.outputFormat == formatting.OutputFormatJSON {
+	// In dry run mode, no key pair has been created, so there is nothing
+	// to store or to render.
+	if args.dryRun {
+		return result, nil
+	}
+
+	if formatting.IsStructured(args.outputFormat) {
 		yamlBytes, err := createKubeconfigYAML(ctx, clusterID, result.apiEndpoint, response)
 		if err != nil {
 			return result, microerror.Mask(err)
@@ -378,7 +378,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 // createNodePool is the business function sending our creation request to the API
@@ -509,7 +509,7 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	if r == nil {
@@ -0,0 +1,256 @@
+// Package app implements the "delete app" command.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/clustercache"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/precondition"
+)
+
+var (
+	// Command is the cobra command for 'gsctl delete app'
+	Command = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>/<app-name>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Delete an app",
+		Long: `Delete an app from a cluster.
+
+This uninstalls the app and removes all resources it created in the cluster.
+
+Examples:
+
+  To delete app 'efk' from cluster 'f01r4', use this command:
+
+    gsctl delete app f01r4/efk
+
+  To prevent the confirmation question, apply --force:
+
+    gsctl delete app f01r4/efk --force
+
+  You can also use the cluster's name:
+
+    gsctl delete app "Cluster name"/efk
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "delete-app"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. It also disables any confirmations.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AppName           string
+	AuthToken         string
+	ClusterNameOrID   string
+	Force             bool
+	OutputFormat      string
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// JSONOutput is the structure printed when the command is called with JSON output.
+type JSONOutput struct {
+	// Result of the command. Should be 'deletion scheduled'.
+	Result string `json:"result"`
+	// ClusterID is the ID of the cluster the app was installed in.
+	ClusterID string `json:"cluster_id,omitempty"`
+	// Name of the app.
+	Name string `json:"name,omitempty"`
+	// Error which occurred.
+	Error *errors.Output `json:"error,omitempty"`
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(positionalArgs []string) (Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	parts := strings.Split(positionalArgs[0], "/")
+	if len(parts) != 2 {
+		return Arguments{}, microerror.Maskf(errors.InvalidAppArgumentError, "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details.")
+	}
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AppName:           strings.TrimSpace(parts[1]),
+		AuthToken:         token,
+		ClusterNameOrID:   strings.TrimSpace(parts[0]),
+		Force:             flags.Force,
+		OutputFormat:      flags.OutputFormat,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}, nil
+}
+
+func verifyPreconditions(args Arguments) error {
+	err := precondition.VerifyApp(args.APIEndpoint, args.AuthToken, args.UserProvidedToken, args.ClusterNameOrID, args.AppName)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !formatting.IsStructured(args.OutputFormat) && args.OutputFormat != formatting.OutputFormatTable {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.OutputFormat)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+	arguments, err = collectArguments(positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+	}
+
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// deleteApp is the business function sending our deletion request to the API.
+// It returns the cluster ID and true for success, false if the user aborted.
+func deleteApp(args Arguments) (string, bool, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	if !args.Force && !formatting.IsStructured(args.OutputFormat) {
+		confirmed := confirm.Ask(fmt.Sprintf("Do you really want to delete app '%s' from cluster '%s'?", args.AppName, args.ClusterNameOrID))
+		if !confirmed {
+			return clusterID, false, nil
+		}
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	_, err = clientWrapper.DeleteApp(clusterID, args.AppName, auxParams)
+	if err != nil {
+		switch {
+		case clienterror.IsAccessForbiddenError(err):
+			return "", false, microerror.Mask(errors.AccessForbiddenError)
+		case clienterror.IsNotFoundError(err):
+			return "", false, microerror.Mask(errors.AppNotFoundError)
+		}
+
+		return "", false, microerror.Mask(err)
+	}
+
+	return clusterID, true, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	clusterID, deleted, err := deleteApp(arguments)
+
+	if formatting.IsStructured(arguments.OutputFormat) {
+		printJSONOutput(clusterID, err)
+		return
+	}
+
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if deleted {
+		fmt.Println(color.GreenString("App '%s' will be deleted from cluster '%s'.", arguments.AppName, clusterID))
+	} else if arguments.Verbose {
+		fmt.Println(color.WhiteString("Aborted."))
+	}
+}
+
+func printJSONOutput(clusterID string, deletionErr error) {
+	var result JSONOutput
+	if deletionErr != nil {
+		result = JSONOutput{Result: "error", Error: errors.NewOutput(deletionErr)}
+	} else {
+		result = JSONOutput{Result: "deletion scheduled", ClusterID: clusterID, Name: arguments.AppName}
+	}
+
+	outputBytes, err := formatting.Marshal(arguments.OutputFormat, result)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(string(outputBytes))
+	if deletionErr != nil {
+		os.Exit(errors.ExitCode(deletionErr))
+	}
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsInvalidAppArgument(err):
+		headline = "Invalid argument syntax"
+		subtext = "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details."
+	case errors.IsAppNameMissingError(err):
+		headline = "No app name specified."
+		subtext = "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details."
+	case errors.IsAppNotFound(err):
+		headline = "App not found"
+		subtext = fmt.Sprintf("Could not find an app named '%s' in this cluster. Check 'gsctl list apps' to make sure.", arguments.AppName)
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = fmt.Sprintf("Could not find a cluster with name or ID '%s'. Check 'gsctl list clusters' to make sure.", arguments.ClusterNameOrID)
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
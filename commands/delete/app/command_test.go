@@ -0,0 +1,84 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// TestDeleteApp tests app deletion, both successful and for a non-existing app.
+func TestDeleteApp(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acme"}]`))
+
+		case r.Method == "DELETE" && r.URL.Path == "/v4/clusters/cluster-id/apps/efk/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"code": "RESOURCE_DELETED", "message": "The app with name 'efk' has been deleted."}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:     mockServer.URL,
+		AppName:         "efk",
+		AuthToken:       "token",
+		ClusterNameOrID: "Name of the cluster",
+		Force:           true,
+		OutputFormat:    "table",
+	}
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterID, deleted, err := deleteApp(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted || clusterID != "cluster-id" {
+		t.Errorf("Expected app to be deleted from 'cluster-id', got deleted=%t, cluster ID %q", deleted, clusterID)
+	}
+
+	args.AppName = "non-existing"
+	_, _, err = deleteApp(args)
+	if !errors.IsAppNotFound(err) {
+		t.Errorf("Expected AppNotFoundError, got %#v", err)
+	}
+}
+
+// TestCollectArguments tests parsing of the positional argument.
+func TestCollectArguments(t *testing.T) {
+	_, err := collectArguments([]string{"cluster-id"})
+	if !errors.IsInvalidAppArgument(err) {
+		t.Errorf("Expected InvalidAppArgumentError, got %#v", err)
+	}
+
+	args, err := collectArguments([]string{"cluster-id/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = verifyPreconditions(args)
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
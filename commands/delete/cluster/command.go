@@ -2,9 +2,9 @@
 package cluster
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
@@ -18,6 +18,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/confirm"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
 )
 
 // Arguments represents all argument that can be passed to our
@@ -40,16 +41,19 @@ type Arguments struct {
 	verbose bool
 	// outputFormat
 	outputFormat string
+	// wait until the cluster is gone
+	wait        bool
+	waitTimeout time.Duration
 }
 
 // JSONOutput contains the fields included in JSON output of the delete cluster command when called with json output flag
 type JSONOutput struct {
-	// Result of the command. should be 'deletion scheduled'
+	// Result of the command. should be 'deletion scheduled', or 'deleted' when using --wait
 	Result string `json:"result"`
 	// ID of the cluster
 	ID string `json:"id"`
 	// Error which occured
-	Error error `json:"error,omitempty"`
+	Error *errors.Output `json:"error,omitempty"`
 }
 
 func collectArguments(positionalArgs []string) Arguments {
@@ -69,6 +73,9 @@ func collectArguments(positionalArgs []string) Arguments {
 		flags.OutputFormat = ""
 	}
 
+	// In dry run mode, nothing changes, so there is nothing to wait for.
+	wait := flags.Wait && flags.DryRun == ""
+
 	return Arguments{
 		apiEndpoint:       endpoint,
 		clusterNameOrID:   clusterNameOrID,
@@ -79,11 +86,16 @@ func collectArguments(positionalArgs []string) Arguments {
 		userProvidedToken: flags.Token,
 		verbose:           flags.Verbose,
 		outputFormat:      flags.OutputFormat,
+		wait:              wait,
+		waitTimeout:       flags.WaitTimeout,
 	}
 }
 
 const deleteClusterActivityName = "delete-cluster"
 
+// waitInterval is the time between two status requests when waiting.
+var waitInterval = clusterwait.DefaultInterval
+
 var (
 	// Command performs the "delete cluster" function
 	Command = &cobra.Command{
@@ -96,7 +108,12 @@ worker nodes will be lost. There is no way to undo this.
 
 Example:
 
-	gsctl delete cluster c7t2o`,
+	gsctl delete cluster c7t2o
+
+To wait until the cluster is gone, use --wait. If the cluster still exists
+after --wait-timeout, the command fails with a non-zero exit code.
+
+	gsctl delete cluster c7t2o --force --wait --wait-timeout 20m`,
 		PreRun: printValidation,
 		Run:    printResult,
 	}
@@ -107,7 +124,9 @@ Example:
 func init() {
 	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "Name or ID of the cluster to delete")
 	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' will change output to be JSON formatted. It also disables any confirmations.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' or '%s' will change output to be JSON or YAML formatted. Use '%s=<expression>' or '%s=<template>' to extract fields. It also disables any confirmations.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the cluster has been deleted.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "wait-timeout", "", clusterwait.DefaultTimeout, "Maximum time to wait when using --wait, e. g. '20m'.")
 
 	Command.Flags().MarkDeprecated("cluster", "You no longer need to pass the cluster ID with -c/--cluster. Use --help for details.")
 }
@@ -147,7 +166,7 @@ func printValidation(cmd *cobra.Command, args []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -165,8 +184,8 @@ func validatePreconditions(args Arguments) error {
 	if config.Config.Token == "" && args.token == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != "" && args.outputFormat != formatting.OutputFormatJSON {
-		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is invalid for gsctl delete cluster. Valid options: '%s'", args.outputFormat, formatting.OutputFormatJSON))
+	if args.outputFormat != "" && !formatting.IsStructured(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is invalid for gsctl delete cluster. Valid options: '%s', '%s', '%s=<expression>', '%s=<template>'", args.outputFormat, formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
 	}
 	return nil
 }
@@ -180,7 +199,7 @@ func printResult(cmd *cobra.Command, args []string) {
 
 	deleted, err := deleteCluster(arguments)
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
+	if formatting.IsStructured(arguments.outputFormat) {
 		printJSONOutput(deleted, clusterID, err)
 		return
 	}
@@ -196,6 +215,9 @@ func printResult(cmd *cobra.Command, args []string) {
 		case errors.IsClusterNotFoundError(err):
 			headline = "Cluster not found"
 			subtext = "The cluster you tried to delete doesn't seem to exist. Check 'gsctl list clusters' to make sure."
+		case clusterwait.IsTimeout(err):
+			headline = "Timed out waiting for the cluster to be deleted"
+			subtext = fmt.Sprintf("Details: %s", err.Error())
 		default:
 			headline = err.Error()
 		}
@@ -204,11 +226,13 @@ func printResult(cmd *cobra.Command, args []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	// non-error output
-	if deleted {
+	if deleted && arguments.wait {
+		fmt.Println(color.GreenString("The cluster '%s' has been deleted.", clusterID))
+	} else if deleted {
 		fmt.Println(color.GreenString("The cluster '%s' will be deleted as soon as all workloads are terminated.", clusterID))
 	} else {
 		if arguments.verbose {
@@ -224,24 +248,27 @@ func printJSONOutput(deleted bool, clusterID string, creationErr error) {
 
 	// handle errors
 	if creationErr != nil {
-		jsonResult = JSONOutput{Result: "error", Error: creationErr}
+		jsonResult = JSONOutput{Result: "error", Error: errors.NewOutput(creationErr)}
+	} else if arguments.wait {
+		jsonResult = JSONOutput{Result: "deleted", ID: clusterID}
 	} else {
 		jsonResult = JSONOutput{Result: "deletion scheduled", ID: clusterID}
 	}
 
-	outputBytes, err = json.MarshalIndent(jsonResult, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	outputBytes, err = formatting.Marshal(arguments.outputFormat, jsonResult)
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(string(outputBytes))
 	if creationErr != nil {
-		os.Exit(1)
+		os.Exit(errors.ExitCode(creationErr))
 	}
 }
 
-// deleteCluster performs the cluster deletion API call
+// deleteCluster performs the cluster deletion API call. If args.wait is set,
+// it then waits until the cluster is gone.
 //
 // The returned tuple contains:
 // - bool: true if cluster will really be deleted, false otherwise
@@ -264,7 +291,7 @@ func deleteCluster(args Arguments) (bool, error) {
 
 	requireConfirmation := true
 
-	if args.force || args.outputFormat == formatting.OutputFormatJSON {
+	if args.force || formatting.IsStructured(args.outputFormat) {
 		requireConfirmation = false
 	}
 
@@ -295,5 +322,29 @@ func deleteCluster(args Arguments) (bool, error) {
 		return false, microerror.Maskf(errors.CouldNotDeleteClusterError, err.Error())
 	}
 
+	clustercache.RemoveCluster(args.apiEndpoint, clusterID)
+
+	if args.wait {
+		waitConfig := clusterwait.Config{
+			ClientWrapper: clientWrapper,
+			AuxParams:     auxParams,
+			ClusterID:     clusterID,
+			Timeout:       args.waitTimeout,
+			Interval:      waitInterval,
+		}
+
+		if !formatting.IsStructured(args.outputFormat) {
+			fmt.Printf("Waiting up to %s for cluster '%s' to be deleted\n", args.waitTimeout, clusterID)
+			waitConfig.Progress = func(status string) {
+				fmt.Println(status)
+			}
+		}
+
+		err = clusterwait.UntilDeleted(waitConfig)
+		if err != nil {
+			return true, microerror.Mask(err)
+		}
+	}
+
 	return true, nil
 }
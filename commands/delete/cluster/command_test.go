@@ -4,8 +4,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
 	"github.com/giantswarm/gsctl/testutils"
 	"github.com/spf13/afero"
 )
@@ -73,6 +76,65 @@ func TestDeleteClusterSuccess(t *testing.T) {
 	}
 }
 
+// TestDeleteClusterWait tests waiting for the cluster to be gone.
+func TestDeleteClusterWait(t *testing.T) {
+	deleted := false
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "as123asd", "name": "somecluster", "owner": "acme"}]`))
+		case r.Method == "DELETE" && r.URL.String() == "/v4/clusters/as123asd/":
+			deleted = true
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"code": "RESOURCE_DELETION_STARTED", "message": "We'll soon nuke this cluster"}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/as123asd/" && !deleted:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "as123asd"}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/as123asd/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Cluster not found"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitInterval = time.Millisecond
+	defer func() { waitInterval = clusterwait.DefaultInterval }()
+
+	args := Arguments{
+		apiEndpoint:     mockServer.URL,
+		clusterNameOrID: "as123asd",
+		token:           "fake-token",
+		force:           true,
+		outputFormat:    formatting.OutputFormatJSON,
+		wait:            true,
+		waitTimeout:     5 * time.Second,
+	}
+
+	ok, err := deleteCluster(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !ok || !deleted {
+		t.Error("Expected cluster to be deleted")
+	}
+}
+
 type failTestCase struct {
 	arguments    Arguments
 	errorMatcher func(error) bool
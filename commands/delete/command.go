@@ -3,6 +3,7 @@ package delete
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/commands/delete/app"
 	"github.com/giantswarm/gsctl/commands/delete/cluster"
 	"github.com/giantswarm/gsctl/commands/delete/endpoint"
 	"github.com/giantswarm/gsctl/commands/delete/nodepool"
@@ -13,7 +14,7 @@ var (
 	Command = &cobra.Command{
 		Use:   "delete",
 		Short: "Delete things",
-		Long:  `Lets you delete a cluster, a node pool, or an API endpoint`,
+		Long:  `Lets you delete a cluster, a node pool, an app, or an API endpoint`,
 	}
 )
 
@@ -21,4 +22,5 @@ func init() {
 	Command.AddCommand(cluster.Command)
 	Command.AddCommand(nodepool.Command)
 	Command.AddCommand(endpoint.Command)
+	Command.AddCommand(app.Command)
 }
@@ -92,7 +92,7 @@ func printValidation(cmd *cobra.Command, args []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -161,7 +161,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 // deleteNodePool is the business function sending our deletion request to the API
@@ -243,7 +243,7 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	if deleted {
@@ -0,0 +1,131 @@
+// Package devapiserver implements the dev-api-server command.
+package devapiserver
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/provider"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const (
+	defaultAddress = "localhost:8080"
+)
+
+var (
+	// Command is the "dev-api-server" CLI command
+	Command = &cobra.Command{
+		Use:   "dev-api-server",
+		Short: "Serve a fake API for local experimentation",
+		Long: `Serves a fake Giant Swarm API, keeping all state in memory.
+
+This is meant for trying out gsctl and for development, without access
+to a real installation. Any credentials are accepted.
+
+Example:
+
+  gsctl dev-api-server --provider aws
+
+Then, in another terminal:
+
+  gsctl login user@example.com --password secret --endpoint http://localhost:8080
+  gsctl create cluster --owner acme --name "My cluster"
+`,
+		Hidden: true,
+		PreRun: printValidation,
+		Run:    runCommand,
+	}
+)
+
+// Arguments specifies all the arguments to be used for our business function.
+type Arguments struct {
+	address  string
+	provider string
+}
+
+// collectArguments fills arguments from user input.
+func collectArguments() Arguments {
+	return Arguments{
+		address:  flags.ListenAddress,
+		provider: flags.Provider,
+	}
+}
+
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.ListenAddress, "address", "", defaultAddress, "Address to serve the fake API on.")
+	Command.Flags().StringVarP(&flags.Provider, "provider", "", provider.AWS, fmt.Sprintf("Provider to simulate. Can be '%s', '%s' or '%s'.", provider.AWS, provider.Azure, provider.KVM))
+}
+
+func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
+	args := collectArguments()
+	err := verifyPreconditions(args)
+
+	if err == nil {
+		return
+	}
+
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsProviderNotSupportedError(err):
+		headline = "Provider not supported"
+		subtext = fmt.Sprintf("Please set --provider to '%s', '%s' or '%s'.", provider.AWS, provider.Azure, provider.KVM)
+	case errors.IsRequiredFlagMissingError(err):
+		headline = "Address missing"
+		subtext = fmt.Sprintf("Please set --address, e. g. to '%s'.", defaultAddress)
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+
+	os.Exit(errors.ExitCode(err))
+}
+
+func verifyPreconditions(args Arguments) error {
+	switch args.provider {
+	case provider.AWS, provider.Azure, provider.KVM:
+	default:
+		return microerror.Mask(errors.ProviderNotSupportedError)
+	}
+
+	if args.address == "" {
+		return microerror.Mask(errors.RequiredFlagMissingError)
+	}
+
+	return nil
+}
+
+// runCommand serves the fake API until the process is terminated.
+func runCommand(cmd *cobra.Command, cmdLineArgs []string) {
+	args := collectArguments()
+
+	fmt.Printf("Serving a fake %s API on %s\n", args.provider, color.CyanString("http://"+args.address))
+	fmt.Println("The organization 'acme' exists. Press Ctrl+C to stop.")
+
+	err := http.ListenAndServe(args.address, testutils.NewFakeAPI(args.provider))
+	if err != nil {
+		fmt.Println(color.RedString("Could not serve the fake API"))
+		fmt.Println(err.Error())
+		os.Exit(errors.ExitCode(err))
+	}
+}
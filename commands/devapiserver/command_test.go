@@ -0,0 +1,46 @@
+package devapiserver
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+)
+
+// Test_verifyPreconditions tests validating the flags.
+func Test_verifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{address: defaultAddress, provider: "aws"},
+			nil,
+		},
+		{
+			Arguments{address: ":9000", provider: "kvm"},
+			nil,
+		},
+		{
+			Arguments{address: defaultAddress, provider: "gcp"},
+			errors.IsProviderNotSupportedError,
+		},
+		{
+			Arguments{address: "", provider: "azure"},
+			errors.IsRequiredFlagMissingError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil {
+				if err != nil {
+					t.Errorf("Case %d - Unexpected error: %#v", i, err)
+				}
+			} else if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Expected matching error, got %#v", i, err)
+			}
+		})
+	}
+}
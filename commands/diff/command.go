@@ -0,0 +1,326 @@
+// Package diff implements the 'diff' command.
+package diff
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
+	"github.com/giantswarm/gsctl/pkg/table"
+)
+
+var (
+	// Command is the cobra command for 'gsctl diff'
+	Command = &cobra.Command{
+		Use:   "diff",
+		Short: "Compare a cluster definition with the live cluster",
+		Long: `Show the differences between a cluster definition file and the
+cluster it describes.
+
+The definition must be in the v5 format, as used with 'gsctl create cluster'
+and 'gsctl apply'. The cluster is identified by the combination of 'name' and
+'owner' in the definition, node pools are matched by name.
+
+The output lists the changes 'gsctl apply' would make: release version
+mismatches, label changes, node pools to add or modify (scaling), and node pools
+which exist in the cluster but not in the definition. Node pool attributes which
+cannot be changed after creation, like instance types or availability zones, are
+not compared.
+
+Exit codes:
+
+  0  The cluster matches the definition.
+  8  The cluster differs from the definition (drift).
+
+Any other exit code means that an error occurred, e. g. 2 for an invalid
+definition or 4 if the cluster does not exist. See the README for all codes.
+
+Examples:
+
+  gsctl diff -f ./my-cluster.yaml
+
+  gsctl diff -f ./my-cluster.yaml -o json
+
+  cat my-cluster.yaml | gsctl diff -f -
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "diff"
+
+	tableColAction   = "action"
+	tableColResource = "resource"
+	tableColField    = "field"
+	tableColCurrent  = "current"
+	tableColDesired  = "desired"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.InputYAMLFile, "file", "f", "", "Path to a cluster definition YAML file. Use '-' to read from STDIN.")
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	FileSystem        afero.Fs
+	InputYAMLFile     string
+	OutputFormat      string
+	UserProvidedToken string
+}
+
+// JSONOutput is the structure printed when the command is called with JSON output.
+type JSONOutput struct {
+	// ClusterID is the ID of the cluster, if it exists.
+	ClusterID string `json:"cluster_id,omitempty"`
+	// Drift is true if the cluster differs from the definition.
+	Drift bool `json:"drift"`
+	// Actions lists the changes required to make the cluster match the definition.
+	Actions []*clusterdefinition.Action `json:"actions"`
+}
+
+// result is what diffDefinition returns.
+type result struct {
+	ClusterID string
+	Actions   []*clusterdefinition.Action
+}
+
+func collectArguments() Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		FileSystem:        config.FileSystem,
+		InputYAMLFile:     flags.InputYAMLFile,
+		OutputFormat:      flags.OutputFormat,
+		UserProvidedToken: flags.Token,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.InputYAMLFile == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--file")
+	}
+	if !formatting.IsStructured(args.OutputFormat) && args.OutputFormat != formatting.OutputFormatTable {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.OutputFormat)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// diffDefinition compares the definition with the live cluster and returns
+// the actions required to make them match.
+func diffDefinition(args Arguments) (*result, error) {
+	def, err := clusterdefinition.ReadV5(args.FileSystem, args.InputYAMLFile)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = clusterdefinition.ValidateForPlan(def)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	live, err := clusterdefinition.FetchLiveState(clientWrapper, auxParams, def.Name, def.Owner)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r := &result{
+		// Node pools missing in the definition are drift, too.
+		Actions: clusterdefinition.Plan(def, live, true),
+	}
+	if live.Cluster != nil {
+		r.ClusterID = live.Cluster.ID
+	}
+
+	return r, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	r, err := diffDefinition(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	output, err := getOutput(r, arguments.OutputFormat)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(output)
+
+	if len(r.Actions) > 0 {
+		os.Exit(exitcode.Drift)
+	}
+}
+
+func getOutput(r *result, outputFormat string) (string, error) {
+	if formatting.IsStructured(outputFormat) {
+		output := JSONOutput{
+			ClusterID: r.ClusterID,
+			Drift:     len(r.Actions) > 0,
+			Actions:   r.Actions,
+		}
+		if output.Actions == nil {
+			output.Actions = []*clusterdefinition.Action{}
+		}
+
+		outputBytes, err := formatting.Marshal(outputFormat, output)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return string(outputBytes), nil
+	}
+
+	if len(r.Actions) == 0 {
+		return color.GreenString("No differences. Cluster '%s' matches the definition.", r.ClusterID), nil
+	}
+
+	t := table.New()
+	t.SetColumns([]table.Column{
+		{Name: tableColAction, DisplayName: "ACTION"},
+		{Name: tableColResource, DisplayName: "RESOURCE"},
+		{Name: tableColField, DisplayName: "FIELD"},
+		{Name: tableColCurrent, DisplayName: "CURRENT"},
+		{Name: tableColDesired, DisplayName: "DESIRED"},
+	})
+
+	var rows [][]string
+	for _, a := range r.Actions {
+		action, resource := describeAction(a)
+
+		if len(a.Changes) == 0 {
+			rows = append(rows, []string{action, resource, "-", "-", "-"})
+			continue
+		}
+
+		for _, c := range a.Changes {
+			rows = append(rows, []string{action, resource, c.Field, valueOrPlaceholder(c.Current), valueOrPlaceholder(c.Desired)})
+		}
+	}
+	t.SetRows(rows)
+
+	return t.String(), nil
+}
+
+// describeAction returns the colored action verb and the affected resource.
+func describeAction(a *clusterdefinition.Action) (string, string) {
+	switch a.Type {
+	case clusterdefinition.ActionCreateCluster:
+		return color.GreenString("add"), "cluster"
+	case clusterdefinition.ActionModifyCluster:
+		return color.YellowString("modify"), "cluster"
+	case clusterdefinition.ActionUpdateLabels:
+		return color.YellowString("modify"), "labels"
+	case clusterdefinition.ActionCreateNodePool:
+		return color.GreenString("add"), fmt.Sprintf("node pool '%s'", a.NodePoolName)
+	case clusterdefinition.ActionModifyNodePool:
+		return color.YellowString("modify"), fmt.Sprintf("node pool '%s' (%s)", a.NodePoolName, a.NodePoolID)
+	case clusterdefinition.ActionDeleteNodePool:
+		return color.RedString("remove"), fmt.Sprintf("node pool '%s' (%s)", a.NodePoolName, a.NodePoolID)
+	}
+
+	return a.Type, ""
+}
+
+func valueOrPlaceholder(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsRequiredFlagMissingError(err):
+		headline = "Missing flag: " + err.Error()
+		subtext = "Please use --file to specify the cluster definition to compare."
+	case clusterdefinition.IsFileNotReadable(err):
+		headline = "Could not read cluster definition"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	case clusterdefinition.IsInvalidDefinition(err):
+		headline = "Could not parse cluster definition"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	case clusterdefinition.IsDefinitionNotV5(err),
+		clusterdefinition.IsClusterNameMissing(err),
+		clusterdefinition.IsOwnerMissing(err),
+		clusterdefinition.IsNodePoolNameMissing(err),
+		clusterdefinition.IsDuplicateNodePoolName(err):
+		headline = "Invalid cluster definition"
+		subtext = microerror.Pretty(err, false)
+	case clusterdefinition.IsClusterNotUnique(err):
+		headline = "Cluster is ambiguous"
+		subtext = fmt.Sprintf("Details: %s. Please rename the clusters so that they can be told apart.", err.Error())
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
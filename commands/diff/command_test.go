@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const definitionYAML = `api_version: v5
+name: My cluster
+owner: acme
+release_version: 12.0.0
+labels:
+  env: prod
+nodepools:
+- name: workers
+  scaling:
+    min: 3
+    max: 20
+- name: new
+`
+
+func newMockServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "abc12", "name": "My cluster", "owner": "acme"}]`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/abc12/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "abc12", "name": "My cluster", "owner": "acme", "release_version": "11.0.0", "labels": {"env": "dev"}}`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/abc12/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "a7k", "name": "workers", "scaling": {"min": 3, "max": 10}},
+				{"id": "b8l", "name": "old", "scaling": {"min": 1, "max": 2}}
+			]`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+}
+
+// TestDiff tests the table and JSON output for a cluster with drift.
+func TestDiff(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = afero.WriteFile(fs, "cluster.yaml", []byte(definitionYAML), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:   mockServer.URL,
+		AuthToken:     "token",
+		FileSystem:    fs,
+		InputYAMLFile: "cluster.yaml",
+		OutputFormat:  "table",
+	}
+
+	r, err := diffDefinition(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := getOutput(r, "table")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"release_version", "11.0.0", "12.0.0", "labels.env", "scaling.max", "node pool 'new'", "remove", "node pool 'old' (b8l)"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output:\n%s", expected, output)
+		}
+	}
+
+	output, err = getOutput(r, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jsonOutput JSONOutput
+	err = json.Unmarshal([]byte(output), &jsonOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonOutput.Drift || jsonOutput.ClusterID != "abc12" || len(jsonOutput.Actions) != 5 {
+		t.Errorf("Unexpected JSON output:\n%s", output)
+	}
+}
+
+// TestNoDiff tests the output for a cluster matching the definition.
+func TestNoDiff(t *testing.T) {
+	output, err := getOutput(&result{ClusterID: "abc12"}, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "cluster_id": "abc12",
+  "drift": false,
+  "actions": []
+}`
+	if output != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, output)
+	}
+}
+
+// TestVerifyPreconditions tests invalid argument combinations.
+func TestVerifyPreconditions(t *testing.T) {
+	testCases := []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", InputYAMLFile: "cluster.yaml", OutputFormat: "table"},
+			errorMatcher: errors.IsNotLoggedInError,
+		},
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", AuthToken: "token", OutputFormat: "table"},
+			errorMatcher: errors.IsRequiredFlagMissingError,
+		},
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", AuthToken: "token", InputYAMLFile: "cluster.yaml", OutputFormat: "xml"},
+			errorMatcher: errors.IsOutputFormatInvalid,
+		},
+	}
+
+	for i, tc := range testCases {
+		err := verifyPreconditions(tc.args)
+		if err == nil {
+			t.Errorf("Case %d - Expected error, got nil", i)
+		} else if !tc.errorMatcher(err) {
+			t.Errorf("Case %d - Error did not match expected type. Got '%s'", i, err)
+		}
+	}
+}
@@ -567,6 +567,27 @@ func IsInvalidDurationError(err error) bool {
 	return microerror.Cause(err) == InvalidDurationError
 }
 
+// InvalidRetriesError means that the number of retries given is not valid.
+var InvalidRetriesError = &microerror.Error{
+	Kind: "InvalidRetriesError",
+}
+
+// IsInvalidRetriesError asserts InvalidRetriesError.
+func IsInvalidRetriesError(err error) bool {
+	return microerror.Cause(err) == InvalidRetriesError
+}
+
+// InvalidConcurrencyError means that the number of clusters to create in
+// parallel is not valid.
+var InvalidConcurrencyError = &microerror.Error{
+	Kind: "InvalidConcurrencyError",
+}
+
+// IsInvalidConcurrencyError asserts InvalidConcurrencyError.
+func IsInvalidConcurrencyError(err error) bool {
+	return microerror.Cause(err) == InvalidConcurrencyError
+}
+
 // DurationExceededError is thrown when a duration value is larger than can be represented internally
 var DurationExceededError = &microerror.Error{
 	Kind: "DurationExceededError",
@@ -727,3 +748,37 @@ var NotPercentage = &microerror.Error{
 	Kind: "NotPercentage",
 	Desc: "Value should be in the range between 0 and 100.",
 }
+
+// App errors
+
+// AppNameMissingError means that the user did not specify the name of an app.
+var AppNameMissingError = &microerror.Error{
+	Kind: "AppNameMissingError",
+}
+
+// IsAppNameMissingError asserts AppNameMissingError.
+func IsAppNameMissingError(err error) bool {
+	return microerror.Cause(err) == AppNameMissingError
+}
+
+// InvalidAppArgumentError should be raised when the user gives a "clusterID/appName"
+// argument that is syntactically incorrect.
+var InvalidAppArgumentError = &microerror.Error{
+	Kind: "InvalidAppArgumentError",
+}
+
+// IsInvalidAppArgument asserts InvalidAppArgumentError.
+func IsInvalidAppArgument(err error) bool {
+	return microerror.Cause(err) == InvalidAppArgumentError
+}
+
+// AppNotFoundError means that an app the user wants to interact with
+// is not installed in the cluster.
+var AppNotFoundError = &microerror.Error{
+	Kind: "AppNotFoundError",
+}
+
+// IsAppNotFound asserts AppNotFoundError.
+func IsAppNotFound(err error) bool {
+	return microerror.Cause(err) == AppNotFoundError
+}
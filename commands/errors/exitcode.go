@@ -0,0 +1,132 @@
+package errors
+
+import (
+	stderrors "errors"
+
+	"github.com/giantswarm/gscliauth/oidc"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
+	"github.com/giantswarm/gsctl/pkg/table"
+)
+
+// ExitCode returns the exit code for an error, according to its class.
+// See package exitcode for the codes.
+func ExitCode(err error) int {
+	if err == nil {
+		return exitcode.OK
+	}
+
+	// Errors returned by the API client carry the HTTP status code, or none
+	// if there was no response.
+	var apiErr *clienterror.APIError
+	if stderrors.As(err, &apiErr) {
+		return exitcode.FromHTTPStatus(apiErr.HTTPStatusCode)
+	}
+
+	// Schema violations found in a cluster definition.
+	var validationErrs clusterdefinition.ValidationErrors
+	if stderrors.As(err, &validationErrs) {
+		return exitcode.Validation
+	}
+
+	switch {
+	case IsNotLoggedInError(err),
+		IsNotAuthorizedError(err),
+		IsAccessForbiddenError(err),
+		IsInvalidCredentialsError(err),
+		IsUserAccountInactiveError(err),
+		IsEmptyPasswordError(err),
+		IsSSOError(err),
+		oidc.IsAuthorizationError(err),
+		oidc.IsRefreshError(err),
+		client.IsNotAuthorizedError(err):
+		return exitcode.Auth
+
+	case IsClusterNotFoundError(err),
+		IsNodePoolNotFound(err),
+		IsReleaseNotFoundError(err),
+		IsOrganizationNotFoundError(err),
+		IsCredentialNotFoundError(err),
+		IsEndpointNotFoundError(err),
+		IsAppNotFound(err):
+		return exitcode.NotFound
+
+	case IsCredentialsAlreadySetError(err),
+		clienterror.IsConflictError(err),
+		clusterdefinition.IsClusterNotUnique(err):
+		return exitcode.Conflict
+
+	case clusterwait.IsTimeout(err):
+		return exitcode.Timeout
+
+	case IsNoResponseError(err):
+		return exitcode.Network
+
+	case IsInternalServerError(err),
+		IsAPIError(err),
+		IsUnspecifiedAPIError(err),
+		IsCouldNotCreateClusterError(err),
+		IsCouldNotDeleteClusterError(err),
+		IsCouldNotScaleClusterError(err),
+		IsCouldNotUpgradeClusterError(err),
+		clienterror.IsMalformedResponse(err):
+		return exitcode.API
+
+	case IsInvalidReleaseError(err),
+		IsConflictingFlagsError(err),
+		IsClusterNameOrIDMissingError(err),
+		IsNodePoolIDMissingError(err),
+		IsNodePoolIDMalformedError(err),
+		IsReleaseVersionMissingError(err),
+		IsNotEnoughWorkerNodesError(err),
+		IsClusterOwnerMissingError(err),
+		IsOrganizationNotSpecifiedError(err),
+		IsYAMLFileNotReadable(err),
+		IsYAMLNotParseable(err),
+		IsBadRequestError(err),
+		IsCannotScaleBelowMinimumWorkersError(err),
+		IsIncompatibleSettings(err),
+		IsEndpointMissingError(err),
+		IsTokenArgumentNotApplicableError(err),
+		IsPasswordArgumentNotApplicableError(err),
+		IsNoEmailArgumentGivenError(err),
+		IsInvalidCNPrefixError(err),
+		IsInvalidDurationError(err),
+		IsInvalidRetriesError(err),
+		IsInvalidConcurrencyError(err),
+		IsProviderNotSupportedError(err),
+		IsRequiredFlagMissingError(err),
+		IsConflictingWorkerFlagsUsed(err),
+		IsWorkersMinMaxInvalid(err),
+		IsOutputFormatInvalid(err),
+		IsClusterDoesNotSupportNodePools(err),
+		IsInvalidNodePoolIDArgument(err),
+		IsAppNameMissingError(err),
+		IsInvalidAppArgument(err),
+		table.IsFieldNotFoundError(err),
+		table.IsMultipleFieldsMatchingError(err),
+		table.IsInvalidCustomColumnsError(err),
+		table.IsInvalidSortKeyError(err),
+		table.IsInvalidFilterError(err),
+		formatting.IsInvalidTemplate(err),
+		clusterdefinition.IsInvalidDefinition(err),
+		clusterdefinition.IsDefinitionNotV5(err),
+		clusterdefinition.IsFileNotReadable(err),
+		clusterdefinition.IsRenderFailed(err),
+		clusterdefinition.IsClusterNameMissing(err),
+		clusterdefinition.IsOwnerMissing(err),
+		clusterdefinition.IsNodePoolNameMissing(err),
+		clusterdefinition.IsDuplicateNodePoolName(err),
+		clusterwait.IsInvalidCondition(err),
+		client.IsEndpointNotSpecifiedError(err),
+		client.IsEndpointInvalidError(err):
+		return exitcode.Validation
+	}
+
+	return exitcode.Error
+}
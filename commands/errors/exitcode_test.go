@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
+)
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{nil, exitcode.OK},
+		{microerror.Mask(ClusterNotFoundError), exitcode.NotFound},
+		{microerror.Maskf(OutputFormatInvalidError, "Output format '%s' is unknown", "xml"), exitcode.Validation},
+		{microerror.Mask(NotLoggedInError), exitcode.Auth},
+		{microerror.Mask(CredentialsAlreadySetError), exitcode.Conflict},
+		{microerror.Mask(InternalServerError), exitcode.API},
+		{microerror.Mask(NoResponseError), exitcode.Network},
+		{microerror.Mask(CommandAbortedError), exitcode.Error},
+		{microerror.Mask(&clienterror.APIError{HTTPStatusCode: http.StatusNotFound}), exitcode.NotFound},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusServiceUnavailable}, exitcode.API},
+		{&clienterror.APIError{IsTimeout: true}, exitcode.Network},
+		{fmt.Errorf("Error creating node pool 1: %w", &clienterror.APIError{HTTPStatusCode: http.StatusForbidden}), exitcode.Auth},
+		{microerror.Mask(clusterdefinition.ValidationErrors{{Line: 1, Column: 1, Message: "unknown key"}}), exitcode.Validation},
+		{parseError(t), exitcode.Validation},
+		{conditionError(t), exitcode.Validation},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if code := ExitCode(tc.err); code != tc.expected {
+				t.Errorf("Expected exit code %d, got %d", tc.expected, code)
+			}
+		})
+	}
+}
+
+// parseError returns the error for a cluster definition which is not valid
+// YAML.
+func parseError(t *testing.T) error {
+	_, err := clusterdefinition.ParseV5([]byte("api_version: [v5"))
+	if err == nil {
+		t.Fatal("Expected error parsing invalid YAML")
+	}
+	return err
+}
+
+// conditionError returns the error for an invalid wait condition.
+func conditionError(t *testing.T) error {
+	_, err := clusterwait.ParseCondition("unknown")
+	if err == nil {
+		t.Fatal("Expected error parsing invalid condition")
+	}
+	return err
+}
+
+func TestPrintStructured(t *testing.T) {
+	testCases := []struct {
+		outputFormat string
+		err          error
+		expected     string
+	}{
+		{
+			formatting.OutputFormatJSON,
+			microerror.Mask(ClusterNotFoundError),
+			`{"kind":"ClusterNotFoundError","class":"not_found","exit_code":4,"message":"cluster not found error"}` + "\n",
+		},
+		{
+			formatting.OutputFormatJSON,
+			microerror.Mask(&clienterror.APIError{
+				HTTPStatusCode: http.StatusInternalServerError,
+				ErrorMessage:   "Internal error",
+				ErrorDetails:   "Please try again later.",
+				RequestID:      "abc123",
+			}),
+			`{"kind":"APIError","class":"api","exit_code":6,"message":"Internal error","details":"Please try again later.","request_id":"abc123"}` + "\n",
+		},
+		{
+			formatting.OutputFormatYAML,
+			microerror.Mask(ClusterNotFoundError),
+			"class: not_found\nexit_code: 4\nkind: ClusterNotFoundError\nmessage: cluster not found error\n",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var out bytes.Buffer
+			PrintStructured(&out, tc.outputFormat, tc.err)
+			if out.String() != tc.expected {
+				t.Errorf("Expected\n%s\ngot\n%s", tc.expected, out.String())
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package errors
 
 import (
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/fatih/color"
@@ -9,14 +12,40 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
 )
 
+// Output is the machine-readable representation of an error. It is printed
+// to stderr with '--output json' or '--output yaml', and is part of the
+// result printed by commands having their own structured output.
+type Output struct {
+	// Kind is the specific error, e. g. 'ClusterNotFoundError'.
+	Kind string `json:"kind"`
+	// Class is the error class also expressed by the exit code,
+	// e. g. 'not_found'.
+	Class     string `json:"class"`
+	ExitCode  int    `json:"exit_code"`
+	Message   string `json:"message"`
+	Details   string `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
 // HandleCommonErrors is a common function to handle certain errors happening in
 // more than one command. If the error given is handled by the function, it
 // prints according text for the end user and exits the process.
 // If the error is not recognized, we simply return.
 //
+// With '--output json' or '--output yaml', any error is printed as an object
+// to stderr instead, and the process exits.
+//
 func HandleCommonErrors(err error) {
+	if flags.OutputFormat == formatting.OutputFormatJSON || flags.OutputFormat == formatting.OutputFormatYAML {
+		PrintStructured(os.Stderr, flags.OutputFormat, err)
+		os.Exit(ExitCode(err))
+	}
 
 	var headline = ""
 	var subtext = ""
@@ -91,5 +120,52 @@ func HandleCommonErrors(err error) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(ExitCode(err))
+}
+
+// NewOutput returns the machine-readable representation of an error, with
+// kind, class, exit code, message, details and request ID.
+func NewOutput(err error) *Output {
+	code := ExitCode(err)
+	output := &Output{
+		Kind:     "Error",
+		Class:    exitcode.Name(code),
+		ExitCode: code,
+		Message:  err.Error(),
+	}
+
+	var apiErr *clienterror.APIError
+	var validationErrs clusterdefinition.ValidationErrors
+	switch cause := microerror.Cause(err).(type) {
+	case *microerror.Error:
+		output.Kind = cause.Kind
+		output.Details = cause.Desc
+	default:
+		switch {
+		case stderrors.As(err, &apiErr):
+			output.Kind = "APIError"
+			output.Message = apiErr.ErrorMessage
+			output.Details = apiErr.ErrorDetails
+			output.RequestID = apiErr.RequestID
+		case stderrors.As(err, &validationErrs):
+			output.Kind = "ValidationErrors"
+		}
+	}
+
+	return output
+}
+
+// PrintStructured prints an error as an object in the given output format,
+// JSON or YAML. JSON is printed in a single line.
+func PrintStructured(w io.Writer, outputFormat string, err error) {
+	output := NewOutput(err)
+
+	var outputBytes []byte
+	if outputFormat == formatting.OutputFormatYAML {
+		outputBytes, _ = formatting.Marshal(outputFormat, output)
+	} else {
+		// Marshalling strings and integers cannot fail.
+		outputBytes, _ = json.Marshal(output)
+	}
+	fmt.Fprintln(w, string(outputBytes))
 }
@@ -0,0 +1,206 @@
+// Package cluster implements the 'export cluster' command.
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+)
+
+var (
+	// Command is the cobra command for 'gsctl export cluster'
+	Command = &cobra.Command{
+		Use: "cluster <cluster-name/cluster-id>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Export a cluster definition",
+		Long: `Print the definition of an existing cluster as YAML.
+
+The output is a cluster definition in the v5 format, containing the release
+version, master node settings, labels and all node pools including their
+scaling, availability zones and node specification. It can be used with
+'gsctl create cluster --file' to create a cluster with the same layout, and
+with 'gsctl apply --file' to keep the cluster in sync with the definition.
+
+Labels managed by Giant Swarm (in the giantswarm.io domain) are not exported.
+Only clusters with node pool support can be exported.
+
+Examples:
+
+  gsctl export cluster f01r4 > my-cluster.yaml
+
+  gsctl export cluster "Production cluster" > production.yaml
+
+To create a copy of a cluster, export it, change name and owner as needed,
+then create the new cluster from the file:
+
+  gsctl export cluster production > staging.yaml
+  gsctl create cluster --file staging.yaml --name staging
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "export-cluster"
+)
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	ClusterNameOrID   string
+	UserProvidedToken string
+}
+
+func collectArguments(positionalArgs []string) Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		ClusterNameOrID:   positionalArgs[0],
+		UserProvidedToken: flags.Token,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.ClusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments(positionalArgs)
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// exportCluster fetches the cluster details and node pools and
+// returns them as a cluster definition.
+func exportCluster(args Arguments) (*types.ClusterDefinitionV5, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetIDCached(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	clusterResponse, err := clientWrapper.GetClusterV5(clusterID, auxParams)
+	if err != nil {
+		switch {
+		case clienterror.IsAccessForbiddenError(err):
+			return nil, microerror.Mask(errors.AccessForbiddenError)
+		case clienterror.IsUnauthorizedError(err):
+			return nil, microerror.Mask(errors.NotAuthorizedError)
+		case clienterror.IsNotFoundError(err), clienterror.IsBadRequestError(err):
+			// The cluster exists (we found its ID), so it's likely a v4 cluster.
+			return nil, microerror.Mask(errors.ClusterDoesNotSupportNodePoolsError)
+		}
+
+		return nil, microerror.Mask(err)
+	}
+
+	nodePoolsResponse, err := clientWrapper.GetNodePools(clusterID, auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// The provider is needed for provider specific master settings.
+	provider := config.Config.Provider
+	if provider == "" {
+		info, err := clientWrapper.GetInfo(auxParams)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		provider = info.Payload.General.Provider
+	}
+
+	live := &clusterdefinition.LiveState{
+		Cluster:   clusterResponse.Payload,
+		NodePools: nodePoolsResponse.Payload,
+		Provider:  provider,
+	}
+
+	return clusterdefinition.FromLiveState(live), nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	def, err := exportCluster(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	yamlBytes, err := yaml.Marshal(def)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Print(string(yamlBytes))
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = fmt.Sprintf("Could not find a cluster with name or ID '%s'. Check 'gsctl list clusters' to make sure.", arguments.ClusterNameOrID)
+	case errors.IsClusterDoesNotSupportNodePools(err):
+		headline = "Cluster cannot be exported"
+		subtext = "Only clusters with node pool support can be exported."
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Fprintln(os.Stderr, color.RedString(headline))
+	if subtext != "" {
+		fmt.Fprintln(os.Stderr, subtext)
+	}
+}
@@ -0,0 +1,142 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// TestExportCluster tests exporting a cluster with node pools.
+func TestExportCluster(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"general": {"provider": "aws"}}`))
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "Production", "owner": "acme"}]`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/f01r4/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "f01r4",
+				"name": "Production",
+				"owner": "acme",
+				"release_version": "12.1.0",
+				"master_nodes": {"high_availability": true, "availability_zones": ["eu-central-1a", "eu-central-1b", "eu-central-1c"]},
+				"labels": {"giantswarm.io/cluster": "f01r4", "release.giantswarm.io/version": "12.1.0", "env": "prod"}
+			}`))
+		case r.Method == "GET" && r.URL.Path == "/v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{
+				"id": "a7k",
+				"name": "workers",
+				"availability_zones": ["eu-central-1b", "eu-central-1a"],
+				"scaling": {"min": 3, "max": 10},
+				"node_spec": {
+					"aws": {
+						"instance_type": "m5.xlarge",
+						"use_alike_instance_types": true,
+						"instance_distribution": {"on_demand_base_capacity": 1, "on_demand_percentage_above_base_capacity": 50}
+					}
+				}
+			}]`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:     mockServer.URL,
+		AuthToken:       "token",
+		ClusterNameOrID: "Production",
+	}
+
+	def, err := exportCluster(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The output must be parseable as a v5 definition again.
+	yamlBytes, err := yaml.Marshal(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := clusterdefinition.ParseV5(yamlBytes)
+	if err != nil {
+		t.Fatalf("Exported YAML could not be parsed: %s\n%s", err, string(yamlBytes))
+	}
+
+	if parsed.Name != "Production" || parsed.Owner != "acme" || parsed.ReleaseVersion != "12.1.0" {
+		t.Errorf("Unexpected cluster attributes in\n%s", string(yamlBytes))
+	}
+	if parsed.MasterNodes == nil || parsed.MasterNodes.HighAvailability == nil || !*parsed.MasterNodes.HighAvailability {
+		t.Errorf("Expected master node high availability in\n%s", string(yamlBytes))
+	}
+	if parsed.MasterNodes != nil && parsed.MasterNodes.Azure != nil {
+		t.Errorf("Expected no Azure master settings in\n%s", string(yamlBytes))
+	}
+	if len(parsed.Labels) != 1 || *parsed.Labels["env"] != "prod" {
+		t.Errorf("Expected only label 'env', got %v", parsed.Labels)
+	}
+	if len(parsed.NodePools) != 1 {
+		t.Fatalf("Expected 1 node pool, got %d", len(parsed.NodePools))
+	}
+
+	np := parsed.NodePools[0]
+	if np.Name != "workers" || np.Scaling.Min == nil || *np.Scaling.Min != 3 || np.Scaling.Max != 10 {
+		t.Errorf("Unexpected node pool %#v", np)
+	}
+	if len(np.AvailabilityZones.Zones) != 2 || np.AvailabilityZones.Zones[0] != "eu-central-1a" {
+		t.Errorf("Unexpected availability zones %v", np.AvailabilityZones.Zones)
+	}
+	if np.NodeSpec.AWS.InstanceType != "m5.xlarge" || !np.NodeSpec.AWS.UseAlikeInstanceTypes || np.NodeSpec.AWS.InstanceDistribution.OnDemandPercentageAboveBaseCapacity != 50 {
+		t.Errorf("Unexpected AWS node spec %#v", np.NodeSpec.AWS)
+	}
+}
+
+// TestExportV4Cluster tests that clusters without node pool support are rejected.
+func TestExportV4Cluster(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "Old", "owner": "acme"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = exportCluster(Arguments{APIEndpoint: mockServer.URL, AuthToken: "token", ClusterNameOrID: "f01r4"})
+	if !errors.IsClusterDoesNotSupportNodePools(err) {
+		t.Errorf("Expected ClusterDoesNotSupportNodePoolsError, got %#v", err)
+	}
+}
@@ -0,0 +1,21 @@
+// Package export holds the 'export *' sub-commands.
+package export
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/export/cluster"
+)
+
+var (
+	// Command is the command to export resources as definitions.
+	Command = &cobra.Command{
+		Use:   "export",
+		Short: "Export cluster definitions",
+		Long:  `Lets you export existing resources as definitions which can be used to re-create them`,
+	}
+)
+
+func init() {
+	Command.AddCommand(cluster.Command)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/giantswarm/columnize"
 	"github.com/giantswarm/gscliauth/config"
 	clientinfo "github.com/giantswarm/gsclientgen/v2/client/info"
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/cobra"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
 )
 
 const (
@@ -25,9 +27,15 @@ const (
 var (
 	// Command is the "info" go command
 	Command = &cobra.Command{
-		Use:    "info",
-		Short:  "Print some information",
-		Long:   `Prints information that might help you get out of trouble`,
+		Use:   "info",
+		Short: "Print some information",
+		Long: `Prints information that might help you get out of trouble
+
+With --output json or --output yaml, the information is printed with the
+fields version, build_date, commit_hash, config_path, kubeconfig_paths,
+api_endpoint, api_endpoint_alias, email, logged_in, auth_token (only with
+--verbose), installation (details on the installation as returned by the API)
+and environment_variables.`,
 		PreRun: printValidation,
 		Run:    printInfo,
 	}
@@ -35,9 +43,19 @@ var (
 	arguments Arguments
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
 // Arguments represents the arguments we can make use of in this command
 type Arguments struct {
 	apiEndpoint       string
+	outputFormat      string
 	scheme            string
 	token             string
 	userProvidedToken string
@@ -53,6 +71,7 @@ func collectArguments() Arguments {
 
 	return Arguments{
 		apiEndpoint:       endpoint,
+		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
 		token:             token,
 		userProvidedToken: flags.Token,
@@ -75,9 +94,30 @@ type infoResult struct {
 	environmentVariables map[string]string
 }
 
-// validatePreconditions simply returns nil, as the command should work under
-// all conditions.
+// infoOutput is the structure printed with '--output json' and
+// '--output yaml'.
+type infoOutput struct {
+	Version              string                 `json:"version"`
+	BuildDate            string                 `json:"build_date"`
+	CommitHash           string                 `json:"commit_hash"`
+	ConfigPath           string                 `json:"config_path"`
+	KubeConfigPaths      []string               `json:"kubeconfig_paths"`
+	APIEndpoint          string                 `json:"api_endpoint,omitempty"`
+	APIEndpointAlias     string                 `json:"api_endpoint_alias,omitempty"`
+	Email                string                 `json:"email,omitempty"`
+	LoggedIn             bool                   `json:"logged_in"`
+	AuthToken            string                 `json:"auth_token,omitempty"`
+	Installation         *models.V4InfoResponse `json:"installation,omitempty"`
+	EnvironmentVariables map[string]string      `json:"environment_variables,omitempty"`
+}
+
+// validatePreconditions only checks the output format, as the command should
+// work under all other conditions.
 func validatePreconditions(args Arguments) error {
+	if args.outputFormat != "" && args.outputFormat != formatting.OutputFormatTable && !formatting.IsStructured(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	}
+
 	return nil
 }
 
@@ -89,6 +129,9 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 	if err != nil {
 		client.HandleErrors(err)
 		errors.HandleCommonErrors(err)
+
+		fmt.Println(color.RedString(err.Error()))
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -96,6 +139,23 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 func printInfo(cmd *cobra.Command, args []string) {
 	result, err := info(arguments)
 
+	if formatting.IsStructured(arguments.outputFormat) {
+		var output string
+		if err == nil {
+			output, err = getStructuredOutput(arguments.outputFormat, result, arguments.verbose)
+		}
+		if err != nil {
+			client.HandleErrors(err)
+			errors.HandleCommonErrors(err)
+
+			fmt.Println(color.RedString(err.Error()))
+			os.Exit(errors.ExitCode(err))
+		}
+
+		fmt.Println(output)
+		return
+	}
+
 	output := []string{}
 
 	if result.version != buildinfo.VersionPlaceholder && result.version != "" {
@@ -201,10 +261,41 @@ func printInfo(cmd *cobra.Command, args []string) {
 		// handle non-standard errors.
 		fmt.Println(color.RedString("Some error occurred:"))
 		fmt.Println(err.Error())
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
+// getStructuredOutput renders the info result in the given structured output
+// format. The auth token is only included if verbose is true.
+func getStructuredOutput(outputFormat string, result infoResult, verbose bool) (string, error) {
+	output := infoOutput{
+		Version:              result.version,
+		BuildDate:            result.buildDate,
+		CommitHash:           result.commitHash,
+		ConfigPath:           result.configFilePath,
+		KubeConfigPaths:      result.kubeConfigPaths,
+		APIEndpoint:          result.apiEndpoint,
+		APIEndpointAlias:     result.apiEndpointAlias,
+		Email:                result.email,
+		LoggedIn:             result.token != "",
+		EnvironmentVariables: result.environmentVariables,
+	}
+
+	if verbose {
+		output.AuthToken = result.token
+	}
+	if result.infoResponse != nil {
+		output.Installation = result.infoResponse.Payload
+	}
+
+	outputBytes, err := formatting.Marshal(outputFormat, output)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return string(outputBytes), nil
+}
+
 // info gets all the information we'd like to show with the "info" command
 // and returns it as a struct
 func info(args Arguments) (infoResult, error) {
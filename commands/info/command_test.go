@@ -1,12 +1,14 @@
 package info
 
 import (
+	"encoding/json"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/spf13/afero"
 
+	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -78,3 +80,41 @@ func Test_InfoWithTempDirAndToken(t *testing.T) {
 		t.Error("Expected empty email, got ", infoResult.email)
 	}
 }
+
+// Test_getStructuredOutput tests that the auth token is only part of the
+// structured output in verbose mode.
+func Test_getStructuredOutput(t *testing.T) {
+	result := infoResult{
+		version:         "1.2.3",
+		configFilePath:  "/home/user/.config/gsctl/config.yaml",
+		kubeConfigPaths: []string{"/home/user/.kube/config"},
+		apiEndpoint:     "https://api.example.com",
+		email:           "email@example.com",
+		token:           "some-token",
+	}
+
+	output, err := getStructuredOutput(formatting.OutputFormatJSON, result, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var data map[string]interface{}
+	err = json.Unmarshal([]byte(output), &data)
+	if err != nil {
+		t.Fatalf("Could not parse output as JSON: %s", err)
+	}
+	if data["version"] != "1.2.3" || data["api_endpoint"] != "https://api.example.com" || data["logged_in"] != true {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	if _, ok := data["auth_token"]; ok {
+		t.Errorf("Expected no auth token in non-verbose output:\n%s", output)
+	}
+
+	output, err = getStructuredOutput(formatting.OutputFormatYAML, result, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.Contains(output, "auth_token: some-token\n") {
+		t.Errorf("Expected auth token in verbose output:\n%s", output)
+	}
+}
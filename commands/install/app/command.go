@@ -0,0 +1,261 @@
+// Package app implements the "install app" command.
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/clustercache"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/precondition"
+)
+
+var (
+	// Command is the cobra command for 'gsctl install app'
+	Command = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Install an app",
+		Long: `Install an app from a catalog into a cluster.
+
+The chart version given via --version will be installed into the namespace
+given via --namespace. Unless a name is given via --name, the app will be named
+after the chart.
+
+Examples:
+
+  gsctl install app f01r4 --catalog giantswarm --chart nginx-ingress-controller-app \
+    --version 1.6.10 --namespace kube-system
+
+  gsctl install app "Cluster name" --catalog giantswarm --chart efk-stack-app \
+    --version 0.2.0 --namespace logging --name efk
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "install-app"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.Name, "name", "n", "", "Name of the app. Defaults to the chart name.")
+	Command.Flags().StringVarP(&flags.AppCatalog, "catalog", "", "", "Name of the catalog to install the app from.")
+	Command.Flags().StringVarP(&flags.AppChart, "chart", "", "", "Name of the chart to install.")
+	Command.Flags().StringVarP(&flags.AppNamespace, "namespace", "", "", "Namespace in the cluster to install the app to.")
+	Command.Flags().StringVarP(&flags.AppVersion, "version", "", "", "Chart version to install.")
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	Catalog           string
+	Chart             string
+	ClusterNameOrID   string
+	Name              string
+	Namespace         string
+	OutputFormat      string
+	UserProvidedToken string
+	Version           string
+}
+
+// JSONOutput is the structure printed when the command is called with JSON output.
+type JSONOutput struct {
+	// Result of the command. Should be 'created'.
+	Result string `json:"result"`
+	// ClusterID is the ID of the cluster the app has been installed to.
+	ClusterID string `json:"cluster_id,omitempty"`
+	// Name of the app.
+	Name string `json:"name,omitempty"`
+	// Error which occurred.
+	Error *errors.Output `json:"error,omitempty"`
+}
+
+func collectArguments(positionalArgs []string) Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	name := flags.Name
+	if name == "" {
+		name = flags.AppChart
+	}
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		Catalog:           flags.AppCatalog,
+		Chart:             flags.AppChart,
+		ClusterNameOrID:   positionalArgs[0],
+		Name:              name,
+		Namespace:         flags.AppNamespace,
+		OutputFormat:      flags.OutputFormat,
+		UserProvidedToken: flags.Token,
+		Version:           flags.AppVersion,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	err := precondition.VerifyCluster(args.APIEndpoint, args.AuthToken, args.UserProvidedToken, args.ClusterNameOrID)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if args.Catalog == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--catalog")
+	}
+	if args.Chart == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--chart")
+	}
+	if args.Namespace == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--namespace")
+	}
+	if args.Version == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--version")
+	}
+	if !formatting.IsStructured(args.OutputFormat) && args.OutputFormat != formatting.OutputFormatTable {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.OutputFormat)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments(positionalArgs)
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// installApp sends the app creation request to the API and returns
+// the ID of the cluster the app has been installed to.
+func installApp(args Arguments) (string, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	requestBody := &models.V4CreateAppRequest{
+		Spec: &models.V4CreateAppRequestSpec{
+			Catalog:   &args.Catalog,
+			Name:      &args.Chart,
+			Namespace: &args.Namespace,
+			Version:   &args.Version,
+		},
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	_, err = clientWrapper.CreateApp(clusterID, args.Name, requestBody, auxParams)
+	if err != nil {
+		switch {
+		case clienterror.IsAccessForbiddenError(err):
+			return "", microerror.Mask(errors.AccessForbiddenError)
+		case clienterror.IsNotFoundError(err):
+			return "", microerror.Mask(errors.ClusterNotFoundError)
+		}
+
+		return "", microerror.Mask(err)
+	}
+
+	return clusterID, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	clusterID, err := installApp(arguments)
+
+	if formatting.IsStructured(arguments.OutputFormat) {
+		printJSONOutput(clusterID, err)
+		return
+	}
+
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(color.GreenString("App '%s' (chart '%s' version %s) is being installed into cluster '%s'.", arguments.Name, arguments.Chart, arguments.Version, clusterID))
+	fmt.Println("Use this command to inspect the app's status:")
+	fmt.Println("")
+	fmt.Println(color.YellowString("    gsctl show app %s/%s", clusterID, arguments.Name))
+	fmt.Println("")
+}
+
+func printJSONOutput(clusterID string, installErr error) {
+	var result JSONOutput
+	if installErr != nil {
+		result = JSONOutput{Result: "error", Error: errors.NewOutput(installErr)}
+	} else {
+		result = JSONOutput{Result: "created", ClusterID: clusterID, Name: arguments.Name}
+	}
+
+	outputBytes, err := formatting.Marshal(arguments.OutputFormat, result)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(string(outputBytes))
+	if installErr != nil {
+		os.Exit(errors.ExitCode(installErr))
+	}
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsRequiredFlagMissingError(err):
+		headline = "Missing flag: " + err.Error()
+		subtext = "Please use --help to see details regarding the command's usage."
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = fmt.Sprintf("Could not find a cluster with name or ID '%s'. Check 'gsctl list clusters' to make sure.", arguments.ClusterNameOrID)
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// TestInstallApp tests the successful installation of an app.
+func TestInstallApp(t *testing.T) {
+	var requestBody models.V4CreateAppRequest
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acme"}]`))
+
+		case r.Method == "PUT" && r.URL.Path == "/v4/clusters/cluster-id/apps/efk/":
+			bodyBytes, _ := ioutil.ReadAll(r.Body)
+			err := json.Unmarshal(bodyBytes, &requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"code": "RESOURCE_CREATED", "message": "Your app is being created."}`))
+
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initFlags()
+	Command.ParseFlags([]string{"--catalog=giantswarm-playground", "--chart=efk-stack-app", "--version=0.2.0", "--namespace=logging", "--name=efk"})
+
+	args := collectArguments([]string{"Name of the cluster"})
+	args.APIEndpoint = mockServer.URL
+	args.AuthToken = "token"
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterID, err := installApp(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clusterID != "cluster-id" {
+		t.Errorf("Expected cluster ID 'cluster-id', got %q", clusterID)
+	}
+
+	if requestBody.Spec == nil {
+		t.Fatal("Expected request body with spec, got none")
+	}
+	if *requestBody.Spec.Catalog != "giantswarm-playground" || *requestBody.Spec.Name != "efk-stack-app" || *requestBody.Spec.Namespace != "logging" || *requestBody.Spec.Version != "0.2.0" {
+		t.Errorf("Unexpected request body spec %#v", requestBody.Spec)
+	}
+}
+
+// TestDefaultName tests that the chart name is used if no app name is given.
+func TestDefaultName(t *testing.T) {
+	initFlags()
+	Command.ParseFlags([]string{"--chart=efk-stack-app"})
+
+	args := collectArguments([]string{"cluster-id"})
+	if args.Name != "efk-stack-app" {
+		t.Errorf("Expected name 'efk-stack-app', got %q", args.Name)
+	}
+
+	flags.Name = ""
+}
+
+// TestVerifyPreconditions tests invalid argument combinations.
+func TestVerifyPreconditions(t *testing.T) {
+	testCases := []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", ClusterNameOrID: "cluster-id"},
+			errorMatcher: errors.IsNotLoggedInError,
+		},
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", AuthToken: "token", ClusterNameOrID: "cluster-id", Chart: "chart", Namespace: "ns", Version: "1.0.0", OutputFormat: "table"},
+			errorMatcher: errors.IsRequiredFlagMissingError,
+		},
+		{
+			args:         Arguments{APIEndpoint: "https://mock-url", AuthToken: "token", ClusterNameOrID: "cluster-id", Catalog: "catalog", Chart: "chart", Namespace: "ns", Version: "1.0.0", OutputFormat: "not-json"},
+			errorMatcher: errors.IsOutputFormatInvalid,
+		},
+	}
+
+	for i, tc := range testCases {
+		err := verifyPreconditions(tc.args)
+		if err == nil {
+			t.Errorf("Case %d - Expected error, got nil", i)
+		} else if !tc.errorMatcher(err) {
+			t.Errorf("Case %d - Error did not match expected type. Got '%s'", i, err)
+		}
+	}
+}
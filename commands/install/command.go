@@ -0,0 +1,21 @@
+// Package install holds the 'install *' sub-commands.
+package install
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/install/app"
+)
+
+var (
+	// Command is the command to install things into clusters.
+	Command = &cobra.Command{
+		Use:   "install",
+		Short: "Install apps",
+		Long:  `Lets you install apps into a cluster`,
+	}
+)
+
+func init() {
+	Command.AddCommand(app.Command)
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// Test_ClusterLifecycle runs commands of the command tree against the fake
+// API, creating a cluster, showing, scaling and finally deleting it.
+func Test_ClusterLifecycle(t *testing.T) {
+	server := httptest.NewServer(testutils.NewFakeAPI("aws"))
+	defer server.Close()
+
+	fs := afero.NewOsFs()
+	configDir, err := testutils.TempConfig(fs, `endpoints:
+  `+server.URL+`:
+    email: email@example.com
+    token: some-token
+    provider: aws
+selected_endpoint: `+server.URL+`
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	defer fs.RemoveAll(configDir)
+	defer RootCommand.SetArgs(nil)
+
+	run := func(args ...string) string {
+		args = append(args, "--config-dir", configDir, "--silence-http-endpoint-warning")
+		return testutils.CaptureOutput(func() {
+			RootCommand.SetArgs(args)
+			err := RootCommand.Execute()
+			if err != nil {
+				t.Errorf("%v - unexpected error: %#v", args, err)
+			}
+		})
+	}
+
+	out := run("create", "cluster", "--owner", "acme", "--name", "Lifecycle test", "--release", "9.3.5")
+	matches := regexp.MustCompile(`\(ID '([a-z0-9]+)'\)`).FindStringSubmatch(out)
+	if matches == nil {
+		t.Fatalf("Expected cluster ID in output, got\n%s", out)
+	}
+	clusterID := matches[1]
+
+	out = run("show", "cluster", clusterID)
+	for _, expected := range []string{"Lifecycle test", "9.3.5", "Worker nodes running:      3"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected show cluster output to contain %q, got\n%s", expected, out)
+		}
+	}
+
+	out = run("scale", "cluster", clusterID, "--num-workers", "5", "--force")
+	if !strings.Contains(out, "min=5 and max=5") {
+		t.Errorf("Expected scaled cluster, got\n%s", out)
+	}
+
+	out = run("show", "cluster", clusterID)
+	if !strings.Contains(out, "Worker nodes running:      5") {
+		t.Errorf("Expected 5 workers after scaling, got\n%s", out)
+	}
+
+	out = run("delete", "cluster", clusterID, "--force")
+	if !strings.Contains(out, "will be deleted") {
+		t.Errorf("Expected deleted cluster, got\n%s", out)
+	}
+
+	// Flag values are shared between commands and keep their values
+	// between runs, so the output format has to be given explicitly.
+	out = run("list", "clusters", "--output", "table")
+	if strings.Contains(out, clusterID) {
+		t.Errorf("Expected cluster to be gone, got\n%s", out)
+	}
+}
@@ -0,0 +1,407 @@
+// Package apps implements the 'list apps' sub-command.
+package apps
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/pkg/precondition"
+	"github.com/giantswarm/gsctl/pkg/sortable"
+	"github.com/giantswarm/gsctl/pkg/table"
+	"github.com/giantswarm/gsctl/util"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+)
+
+var (
+	// Command performs the "list apps" function
+	Command = &cobra.Command{
+		Use:     "apps <cluster-name/cluster-id>",
+		Aliases: []string{"app"},
+
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "List apps",
+		Long: `Prints a list of the apps installed in a cluster.
+
+The result will be a table of all apps of a specific cluster with the following details in
+columns:
+
+	NAME:           Name given to the app on installation (unique within the cluster)
+	NAMESPACE:      Namespace the app is installed to
+	CATALOG:        Catalog the chart of this app comes from
+	CHART:          Name of the chart used to install the app
+	VERSION:        Version of the chart
+	APP VERSION:    Version of the application packaged in the chart
+	STATUS:         Deployment status of the app
+	LAST DEPLOYED:  Date and time of the last deployment
+
+With --output wide, the config map and secret holding the user configuration
+of the app are shown in addition.
+
+To see all available details for an app, use 'gsctl show app <cluster-id>/<app-name>'.
+
+Examples:
+
+  gsctl list apps f01r4
+
+  gsctl list apps "Cluster name" --output json
+
+  gsctl list apps f01r4 --output custom-columns=NAME:.metadata.name,CHART:.spec.name
+
+  gsctl list apps f01r4 --output csv
+
+  gsctl list apps f01r4 --filter status!=deployed --sort catalog,name
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "list-apps"
+
+	tableColName         = "name"
+	tableColNamespace    = "namespace"
+	tableColCatalog      = "catalog"
+	tableColChart        = "chart"
+	tableColVersion      = "version"
+	tableColAppVersion   = "app-version"
+	tableColStatus       = "status"
+	tableColLastDeployed = "last-deployed"
+
+	// Columns only displayed in wide output.
+	tableColUserConfigMap = "user-configmap"
+	tableColUserSecret    = "user-secret"
+)
+
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Use '%s' to add user configuration details to the table, or '%s=<HEADER>:<expression>,...' to choose the columns. Use '%s' or '%s' for comma or tab separated values with all columns. Defaults to human-friendly table output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate, table.OutputFormatWide, table.OutputFormatCustomColumns, table.OutputFormatCSV, table.OutputFormatTSV))
+	Command.Flags().StringVarP(&flags.Sort, "sort", "s", "", "Sort by one or more comma separated fields, like 'namespace,-last-deployed'. Prefix a field with '-' to sort in descending order. Defaults to sorting by name.")
+	Command.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Only list apps meeting a condition, like 'status=deployed' or 'version>=1.0.0'. Supported operators are =, !=, >, >=, < and <=. Can be specified multiple times.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	ClusterNameOrID   string
+	Filters           []string
+	OutputFormat      string
+	SortBy            string
+	UserProvidedToken string
+}
+
+// collectArguments creates arguments based on command line flags and config.
+func collectArguments(positionalArgs []string) Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		ClusterNameOrID:   positionalArgs[0],
+		Filters:           flags.Filter,
+		OutputFormat:      flags.OutputFormat,
+		SortBy:            flags.Sort,
+		UserProvidedToken: flags.Token,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	err := precondition.VerifyCluster(args.APIEndpoint, args.AuthToken, args.UserProvidedToken, args.ClusterNameOrID)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !formatting.IsStructured(args.OutputFormat) && !table.IsTableOutput(args.OutputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.OutputFormat)
+	}
+	if _, _, err := table.ParseSortAndFilter(args.SortBy, args.Filters); err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments(positionalArgs)
+	err := verifyPreconditions(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+}
+
+// fetchApps fetches all apps installed in a cluster, sorted by name.
+func fetchApps(args Arguments) ([]*models.V4GetClusterAppsResponseItems, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetIDCached(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	response, err := clientWrapper.GetApps(clusterID, auxParams)
+	if err != nil {
+		switch {
+		case clienterror.IsAccessForbiddenError(err):
+			return nil, microerror.Mask(errors.AccessForbiddenError)
+		case clienterror.IsNotFoundError(err):
+			return nil, microerror.Mask(errors.ClusterNotFoundError)
+		}
+
+		return nil, microerror.Mask(err)
+	}
+
+	apps := response.Payload
+	sort.Slice(apps, func(i, j int) bool {
+		return appName(apps[i]) < appName(apps[j])
+	})
+
+	return apps, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	apps, err := fetchApps(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if len(apps) == 0 && !formatting.IsStructured(arguments.OutputFormat) && !table.IsDelimited(arguments.OutputFormat) {
+		fmt.Println(color.YellowString("No apps installed in this cluster"))
+		return
+	}
+
+	output, err := getOutput(apps, arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(output)
+}
+
+func getOutput(apps []*models.V4GetClusterAppsResponseItems, args Arguments) (string, error) {
+	outputFormat := args.OutputFormat
+
+	t := createTable()
+	t.SetWide(table.IsWide(outputFormat))
+
+	rows := make([][]string, 0, len(apps))
+	for _, app := range apps {
+		var (
+			namespace, catalog, chart, version string
+			appVersion, status, lastDeployed   = "n/a", "n/a", "n/a"
+			userConfigMap, userSecret          = "n/a", "n/a"
+		)
+
+		if app.Spec != nil {
+			namespace = app.Spec.Namespace
+			catalog = app.Spec.Catalog
+			chart = app.Spec.Name
+			version = app.Spec.Version
+
+			if app.Spec.UserConfig != nil {
+				if app.Spec.UserConfig.Configmap != nil && app.Spec.UserConfig.Configmap.Name != "" {
+					userConfigMap = app.Spec.UserConfig.Configmap.Namespace + "/" + app.Spec.UserConfig.Configmap.Name
+				}
+				if app.Spec.UserConfig.Secret != nil && app.Spec.UserConfig.Secret.Name != "" {
+					userSecret = app.Spec.UserConfig.Secret.Namespace + "/" + app.Spec.UserConfig.Secret.Name
+				}
+			}
+		}
+		if app.Status != nil {
+			if app.Status.AppVersion != "" {
+				appVersion = app.Status.AppVersion
+			}
+			if app.Status.Release != nil {
+				if app.Status.Release.Status != "" {
+					status = app.Status.Release.Status
+				}
+				if app.Status.Release.LastDeployed != "" {
+					lastDeployed = util.ShortDate(util.ParseDate(app.Status.Release.LastDeployed))
+				}
+			}
+		}
+
+		rows = append(rows, []string{
+			appName(app),
+			namespace,
+			catalog,
+			chart,
+			version,
+			appVersion,
+			formatStatus(status),
+			lastDeployed,
+			userConfigMap,
+			userSecret,
+		})
+	}
+	t.SetRows(rows)
+
+	keys, filters, err := table.ParseSortAndFilter(args.SortBy, args.Filters)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	indexes, err := t.SortAndFilter(keys, filters)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if formatting.IsStructured(outputFormat) || table.IsCustomColumns(outputFormat) {
+		// Render an empty list instead of null.
+		items := make([]*models.V4GetClusterAppsResponseItems, 0, len(indexes))
+		for _, i := range indexes {
+			items = append(items, apps[i])
+		}
+
+		if table.IsCustomColumns(outputFormat) {
+			output, err := table.CustomColumns(outputFormat, items)
+			if err != nil {
+				return "", microerror.Mask(err)
+			}
+
+			return output, nil
+		}
+
+		outputBytes, err := formatting.Marshal(outputFormat, items)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return string(outputBytes), nil
+	}
+
+	output, err := t.Format(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return output, nil
+}
+
+func createTable() *table.Table {
+	t := table.New()
+
+	t.SetColumns([]table.Column{
+		{
+			Name:        tableColName,
+			DisplayName: "NAME",
+			Sortable:    sortable.Sortable{SortType: sortable.String},
+		},
+		{
+			Name:        tableColNamespace,
+			DisplayName: "NAMESPACE",
+			Sortable:    sortable.Sortable{SortType: sortable.String},
+		},
+		{
+			Name:        tableColCatalog,
+			DisplayName: "CATALOG",
+			Sortable:    sortable.Sortable{SortType: sortable.String},
+		},
+		{
+			Name:        tableColChart,
+			DisplayName: "CHART",
+			Sortable:    sortable.Sortable{SortType: sortable.String},
+		},
+		{
+			Name:        tableColVersion,
+			DisplayName: "VERSION",
+			Sortable:    sortable.Sortable{SortType: sortable.Semver},
+		},
+		{
+			Name:        tableColAppVersion,
+			DisplayName: "APP VERSION",
+			Sortable:    sortable.Sortable{SortType: sortable.Semver},
+		},
+		{
+			Name:        tableColStatus,
+			DisplayName: "STATUS",
+			Sortable:    sortable.Sortable{SortType: sortable.String},
+		},
+		{
+			Name:        tableColLastDeployed,
+			DisplayName: "LAST DEPLOYED",
+			Sortable:    sortable.Sortable{SortType: sortable.Date},
+		},
+		{
+			Name:        tableColUserConfigMap,
+			DisplayName: "USER CONFIGMAP",
+			Sortable:    sortable.Sortable{SortType: sortable.String},
+			Wide:        true,
+		},
+		{
+			Name:        tableColUserSecret,
+			DisplayName: "USER SECRET",
+			Sortable:    sortable.Sortable{SortType: sortable.String},
+			Wide:        true,
+		},
+	})
+
+	return &t
+}
+
+func appName(app *models.V4GetClusterAppsResponseItems) string {
+	if app.Metadata == nil {
+		return ""
+	}
+
+	return app.Metadata.Name
+}
+
+// formatStatus highlights failed deployments.
+func formatStatus(status string) string {
+	if status == "FAILED" {
+		return color.RedString(status)
+	}
+
+	return status
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = fmt.Sprintf("Could not find a cluster with name or ID '%s'. Check 'gsctl list clusters' to make sure.", arguments.ClusterNameOrID)
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
@@ -0,0 +1,204 @@
+package apps
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+func Test_ListApps(t *testing.T) {
+	testCases := []struct {
+		appsResponse string
+		outputFormat string
+		output       string
+	}{
+		{
+			appsResponse: `[
+				{"metadata": {"name": "nginx-ingress-controller"}, "spec": {"catalog": "giantswarm", "name": "nginx-ingress-controller-app", "namespace": "kube-system", "version": "1.6.10"}, "status": {"app_version": "0.30.0", "release": {"last_deployed": "2020-04-08T12:34:00Z", "status": "DEPLOYED"}, "version": "1.6.10"}},
+				{"metadata": {"name": "efk"}, "spec": {"catalog": "giantswarm-playground", "name": "efk-stack-app", "namespace": "logging", "version": "0.2.0"}, "status": {"app_version": "1.0.0", "release": {"last_deployed": "2020-05-01T09:00:00Z", "status": "FAILED"}, "version": "0.2.0"}},
+				{"metadata": {"name": "new-app"}, "spec": {"catalog": "giantswarm", "name": "some-app", "namespace": "default", "version": "0.1.0"}}
+			]`,
+			outputFormat: "table",
+			output: `NAME                       NAMESPACE     CATALOG                 CHART                          VERSION   APP VERSION   STATUS     LAST DEPLOYED
+efk                        logging       giantswarm-playground   efk-stack-app                  0.2.0     1.0.0         FAILED     2020 May 01, 09:00 UTC
+new-app                    default       giantswarm              some-app                       0.1.0     n/a           n/a        n/a
+nginx-ingress-controller   kube-system   giantswarm              nginx-ingress-controller-app   1.6.10    0.30.0        DEPLOYED   2020 Apr 08, 12:34 UTC`,
+		},
+		{
+			appsResponse: `[
+				{"metadata": {"name": "efk"}, "spec": {"catalog": "giantswarm", "name": "efk-stack-app", "namespace": "logging", "version": "0.2.0", "user_config": {"configmap": {"name": "efk-values", "namespace": "f01r4"}}}},
+				{"metadata": {"name": "new-app"}, "spec": {"catalog": "giantswarm", "name": "some-app", "namespace": "default", "version": "0.1.0"}}
+			]`,
+			outputFormat: "wide",
+			output: `NAME      NAMESPACE   CATALOG      CHART           VERSION   APP VERSION   STATUS   LAST DEPLOYED   USER CONFIGMAP     USER SECRET
+efk       logging     giantswarm   efk-stack-app   0.2.0     n/a           n/a      n/a             f01r4/efk-values   n/a
+new-app   default     giantswarm   some-app        0.1.0     n/a           n/a      n/a             n/a                n/a`,
+		},
+		{
+			appsResponse: `[
+				{"metadata": {"name": "efk"}, "spec": {"catalog": "giantswarm", "name": "efk-stack-app", "namespace": "logging", "version": "0.2.0"}}
+			]`,
+			outputFormat: "custom-columns=NAME:.metadata.name,CHART:.spec.name",
+			output: `NAME   CHART
+efk    efk-stack-app`,
+		},
+		{
+			appsResponse: `[
+				{"metadata": {"name": "efk"}, "spec": {"catalog": "giantswarm-playground", "name": "efk-stack-app", "namespace": "logging", "version": "0.2.0"}, "status": {"app_version": "1.0.0", "release": {"last_deployed": "2020-05-01T09:00:00Z", "status": "DEPLOYED"}, "version": "0.2.0"}}
+			]`,
+			outputFormat: "json",
+			output: `[
+  {
+    "metadata": {
+      "name": "efk"
+    },
+    "spec": {
+      "catalog": "giantswarm-playground",
+      "name": "efk-stack-app",
+      "namespace": "logging",
+      "version": "0.2.0"
+    },
+    "status": {
+      "app_version": "1.0.0",
+      "release": {
+        "last_deployed": "2020-05-01T09:00:00Z",
+        "status": "DEPLOYED"
+      },
+      "version": "0.2.0"
+    }
+  }
+]`,
+		},
+		{
+			appsResponse: `[]`,
+			outputFormat: "json",
+			output:       `[]`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == "GET" {
+					switch uri := r.URL.Path; uri {
+					case "/v4/clusters/cluster-id/apps/":
+						w.WriteHeader(http.StatusOK)
+						w.Write([]byte(tc.appsResponse))
+
+					case "/v4/clusters/":
+						w.WriteHeader(http.StatusOK)
+						w.Write([]byte(`[
+					{
+						"id": "cluster-id",
+						"name": "Name of the cluster",
+						"owner": "acme"
+					}
+				]`))
+
+					default:
+						t.Errorf("Case %d: Unsupported route %s called in mock server", i, r.URL.Path)
+						w.WriteHeader(http.StatusNotFound)
+						w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+					}
+				}
+			}))
+			defer mockServer.Close()
+
+			// temp config
+			fs := afero.NewMemMapFs()
+			configDir := testutils.TempDir(fs)
+			config.Initialize(fs, configDir)
+
+			args := Arguments{
+				ClusterNameOrID: "Name of the cluster",
+				APIEndpoint:     mockServer.URL,
+				AuthToken:       "my-token",
+				OutputFormat:    tc.outputFormat,
+			}
+
+			err := verifyPreconditions(args)
+			if err != nil {
+				t.Errorf("Case %d: %s", i, err)
+			}
+
+			results, err := fetchApps(args)
+			if err != nil {
+				t.Errorf("Case %d: %s", i, err)
+			}
+
+			output, err := getOutput(results, args)
+			if err != nil {
+				t.Errorf("Case %d: %s", i, err)
+			}
+
+			if diff := cmp.Diff(tc.output, output); diff != "" {
+				t.Errorf("Case %d - Command output is incorrect. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+func Test_ListAppsPreconditions(t *testing.T) {
+	testCases := []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			args: Arguments{
+				AuthToken:       "token",
+				ClusterNameOrID: "cluster-id",
+				OutputFormat:    "table",
+			},
+			errorMatcher: errors.IsEndpointMissingError,
+		},
+		{
+			args: Arguments{
+				APIEndpoint:     "https://mock-url",
+				ClusterNameOrID: "cluster-id",
+				OutputFormat:    "table",
+			},
+			errorMatcher: errors.IsNotLoggedInError,
+		},
+		{
+			args: Arguments{
+				APIEndpoint:  "https://mock-url",
+				AuthToken:    "token",
+				OutputFormat: "table",
+			},
+			errorMatcher: errors.IsClusterNameOrIDMissingError,
+		},
+		{
+			args: Arguments{
+				APIEndpoint:     "https://mock-url",
+				AuthToken:       "token",
+				ClusterNameOrID: "cluster-id",
+				OutputFormat:    "not-json",
+			},
+			errorMatcher: errors.IsOutputFormatInvalid,
+		},
+	}
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range testCases {
+		err := verifyPreconditions(tc.args)
+		if err == nil {
+			t.Errorf("Case %d - Expected error, got nil", i)
+		} else if !tc.errorMatcher(err) {
+			t.Errorf("Case %d - Error did not match expected type. Got '%s'", i, err)
+		}
+	}
+}
@@ -2,9 +2,10 @@
 package clusters
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,8 +18,10 @@ import (
 
 	"github.com/giantswarm/gsctl/clustercache"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
 	"github.com/giantswarm/gsctl/pkg/sortable"
 	"github.com/giantswarm/gsctl/pkg/table"
+	"github.com/giantswarm/gsctl/pkg/watch"
 
 	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/client/clienterror"
@@ -41,11 +44,35 @@ Examples:
 
   gsctl list clusters --output json
 
+  gsctl list clusters --output yaml
+
+  gsctl list clusters --output 'jsonpath={.items[?(@.name=="Production")].id}'
+
+  gsctl list clusters --output 'go-template={{range .items}}{{.id}} {{.name}}{{"\n"}}{{end}}'
+
+  gsctl list clusters --output wide
+
+  gsctl list clusters --output custom-columns=NAME:.name,RELEASE:.release_version
+
+  gsctl list clusters --output csv > clusters.csv
+
   gsctl list clusters --show-deleting
 
   gsctl list clusters --selector environment=testing
 
   gsctl list clusters --sort org
+
+  gsctl list clusters --sort release,-created
+
+  gsctl list clusters --filter organization=acme --filter 'release>=12.0.0'
+
+  gsctl list clusters --output wide --watch
+
+With --watch, the list is updated every few seconds. Rows of clusters which
+have been added since the last update are marked with '+', changed rows with
+'~' and removed rows with '-'. With --output json or --output yaml, one event
+with the fields 'type' (ADDED, MODIFIED or DELETED) and 'object' is printed
+per change instead.
 `,
 		PreRun: printValidation,
 		Run:    printResult,
@@ -55,8 +82,6 @@ Examples:
 
 	cmdSelector string
 
-	cmdSort string
-
 	arguments Arguments
 )
 
@@ -69,6 +94,11 @@ const (
 	tableColOrg           = "organization"
 	tableColRelease       = "release"
 	tableColDeletingSince = "deleting-since"
+
+	// Columns only displayed in wide output.
+	tableColMasters = "masters"
+	tableColWorkers = "workers"
+	tableColLabels  = "labels"
 )
 
 var tableCols = [...]string{
@@ -86,21 +116,27 @@ func init() {
 
 func initFlags() {
 	Command.ResetFlags()
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Use '%s' to add node counts and labels to the table, or '%s=<HEADER>:<expression>,...' to choose the columns. Use '%s' or '%s' for comma or tab separated values with all columns. Defaults to human-friendly table output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate, table.OutputFormatWide, table.OutputFormatCustomColumns, table.OutputFormatCSV, table.OutputFormatTSV))
 	Command.Flags().BoolVarP(&cmdShowDeleted, "show-deleting", "", false, "Show clusters which are currently being deleted (only with cluster release > 10.0.0).")
 	Command.Flags().StringVarP(&cmdSelector, "selector", "l", "", "Label selector query to filter clusters on.")
-	Command.Flags().StringVarP(&cmdSort, "sort", "s", "id", fmt.Sprintf("Sort by one of the fields %s", getFormattedFilterFields(tableCols[:])))
+	Command.Flags().StringVarP(&flags.Sort, "sort", "s", "id", fmt.Sprintf("Sort by one or more comma separated fields of %s. Prefix a field with '-' to sort in descending order, like in 'release,-created'.", getFormattedFilterFields(tableCols[:])))
+	Command.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Only list clusters meeting a condition, like 'organization=acme' or 'release>=12.0.0'. Supported operators are =, !=, >, >=, < and <=. Can be specified multiple times.")
+	Command.Flags().BoolVarP(&flags.Watch, "watch", "w", false, "Keep polling and update the list when clusters change.")
+	Command.Flags().DurationVarP(&flags.WatchInterval, "watch-interval", "", watch.DefaultInterval, "Time between two updates with --watch.")
 }
 
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
+	filters           []string
 	outputFormat      string
 	scheme            string
 	selector          string
 	showDeleting      bool
 	sortBy            string
 	userProvidedToken string
+	watch             bool
+	watchInterval     time.Duration
 }
 
 func collectArguments() Arguments {
@@ -111,17 +147,22 @@ func collectArguments() Arguments {
 	return Arguments{
 		apiEndpoint:       endpoint,
 		authToken:         token,
+		filters:           flags.Filter,
 		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
 		selector:          cmdSelector,
 		showDeleting:      cmdShowDeleted,
-		sortBy:            cmdSort,
+		sortBy:            flags.Sort,
 		userProvidedToken: flags.Token,
+		watch:             flags.Watch,
+		watchInterval:     flags.WatchInterval,
 	}
 }
 
 func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "list clusters", "get clusters", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-clusters/"))
+	// The notice goes to stderr, so that it does not end up in output
+	// processed by other tools, like CSV or JSON.
+	fmt.Fprint(os.Stderr, util.GetDeprecatedNotice(config.Config.Provider, "list clusters", "get clusters", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-clusters/"))
 
 	arguments = collectArguments()
 	err := verifyListClusterPreconditions(arguments)
@@ -136,7 +177,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 	// Display error
 	fmt.Println(color.RedString(err.Error()))
 
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func verifyListClusterPreconditions(args Arguments) error {
@@ -146,63 +187,86 @@ func verifyListClusterPreconditions(args Arguments) error {
 	if config.Config.Token == "" && args.authToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
+	if !formatting.IsStructured(args.outputFormat) && !table.IsTableOutput(args.outputFormat) {
 		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
 	}
+	if args.watch && !watch.SupportsOutputFormat(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is not supported with --watch", args.outputFormat)
+	}
+	if _, _, err := table.ParseSortAndFilter(args.sortBy, args.filters); err != nil {
+		return microerror.Mask(err)
+	}
 
 	return nil
 }
 
 // printResult prints a table with all clusters the user has access to
 func printResult(cmd *cobra.Command, cmdLineArgs []string) {
+	if arguments.watch {
+		err := watch.Run(watch.Config{
+			Fetch:        func() (*watch.Snapshot, error) { return getClustersSnapshot(arguments) },
+			Interval:     arguments.watchInterval,
+			OutputFormat: arguments.outputFormat,
+			Title:        cmd.CommandPath(),
+			Redraw:       watch.IsTerminal(os.Stdout),
+		})
+		if err != nil {
+			handleError(err)
+			os.Exit(errors.ExitCode(err))
+		}
+		return
+	}
+
 	output, err := getClustersOutput(arguments)
 	if err != nil {
-		client.HandleErrors(err)
-		errors.HandleCommonErrors(err)
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
 
-		var (
-			headline string
-			subtext  string
-		)
+	if output != "" {
+		fmt.Println(output)
+	}
+}
 
-		clientErr, isClientErr := err.(*clienterror.APIError)
+// handleError prints an error which occurred while listing clusters.
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
 
-		switch {
-		case isClientErr:
-			headline = clientErr.ErrorMessage
-			if clientErr.ErrorDetails != "" {
-				subtext = clientErr.ErrorDetails
-			}
+	var (
+		headline string
+		subtext  string
+	)
 
-		case table.IsFieldNotFoundError(err):
-			headline = fmt.Sprintf("Cannot sort by attribute '%s'.", arguments.sortBy)
-			subtext = fmt.Sprintf(
-				"The attribute '%s' does not exist.\nYou can sort by any of these attributes: %v",
-				arguments.sortBy,
-				strings.Join(tableCols[:], ", "),
-			)
-
-		case table.IsMultipleFieldsMatchingError(err):
-			headline = fmt.Sprintf("Multiple attributes found for token '%s'.", arguments.sortBy)
-			subtext = fmt.Sprintf(
-				"Please provide the complete attribute.\nYou can sort by any of these attributes: %v",
-				strings.Join(tableCols[:], ", "),
-			)
-
-		default:
-			headline = fmt.Sprintf("Error: %s", err.Error())
-		}
+	clientErr, isClientErr := err.(*clienterror.APIError)
 
-		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
+	switch {
+	case isClientErr:
+		headline = clientErr.ErrorMessage
+		if clientErr.ErrorDetails != "" {
+			subtext = clientErr.ErrorDetails
 		}
-		os.Exit(1)
+
+	case table.IsFieldNotFoundError(err):
+		headline = "Cannot sort or filter by an attribute which does not exist."
+		subtext = err.Error()
+
+	case table.IsMultipleFieldsMatchingError(err):
+		headline = "Multiple attributes found for the given name. Please provide the complete attribute."
+		subtext = err.Error()
+
+	case table.IsInvalidFilterError(err):
+		headline = "Invalid filter."
+		subtext = err.Error()
+
+	default:
+		headline = fmt.Sprintf("Error: %s", err.Error())
 	}
 
-	if output != "" {
-		fmt.Println(output)
+	// print output
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
 	}
 }
 
@@ -220,12 +284,105 @@ func getFormattedFilterFields(colNames []string) string {
 	return result
 }
 
+// clusterListing is the result of fetching the clusters the user has
+// access to.
+type clusterListing struct {
+	// table holds the sorted and filtered clusters.
+	table *table.Table
+	// clusters are the clusters of the table rows, in the same order.
+	clusters []*models.V4ClusterListItem
+	// numDeleted is the number of clusters currently being deleted.
+	numDeleted int
+}
+
 // getClustersOutput returns a table of clusters the user has access to
 func getClustersOutput(args Arguments) (string, error) {
+	listing, err := listClusters(args)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	isStructured := formatting.IsStructured(args.outputFormat) || table.IsCustomColumns(args.outputFormat)
+
+	if isStructured {
+		output, err := renderItems(listing.clusters, args.outputFormat)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return output, nil
+	}
+
+	// This function's output string.
+	output := ""
+
+	// CSV and TSV consist of the table only, which is the header
+	// in case there are no clusters.
+	if table.IsDelimited(args.outputFormat) {
+		output, err = listing.table.Format(args.outputFormat)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return output, nil
+	}
+
+	// Only show table when there is content.
+	if len(listing.clusters) > 0 {
+		output += listing.table.String()
+	} else {
+		output += color.YellowString("No clusters")
+	}
+
+	if notice := getDeletingNotice(args, listing.numDeleted); notice != "" {
+		output += "\n\n" + notice
+	}
+
+	return output, nil
+}
+
+// getClustersSnapshot returns the clusters the user has access to for
+// watching, with cluster IDs as keys.
+func getClustersSnapshot(args Arguments) (*watch.Snapshot, error) {
+	listing, err := listClusters(args)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	keys := make([]string, 0, len(listing.clusters))
+	objects := make([]interface{}, 0, len(listing.clusters))
+	for _, cluster := range listing.clusters {
+		keys = append(keys, cluster.ID)
+		objects = append(objects, cluster)
+	}
+
+	snapshot := watch.NewTableSnapshot(listing.table, keys, objects)
+	snapshot.Footer = getDeletingNotice(args, listing.numDeleted)
+
+	return snapshot, nil
+}
+
+// getDeletingNotice returns a hint on clusters being deleted which are not
+// shown, or an empty string if there are none.
+func getDeletingNotice(args Arguments, numDeletedClusters int) string {
+	if args.showDeleting || numDeletedClusters == 0 {
+		return ""
+	}
+
+	if numDeletedClusters == 1 {
+		return fmt.Sprintf("There is 1 additional cluster currently being deleted. Add the %s flag to see it.", color.CyanString("--show-deleting"))
+	}
+
+	return fmt.Sprintf("There are %d additional clusters currently being deleted. Add the %s flag to see them.", numDeletedClusters, color.CyanString("--show-deleting"))
+}
+
+// listClusters fetches the clusters the user has access to and returns them
+// as a table, sorted and filtered according to the arguments.
+func listClusters(args Arguments) (*clusterListing, error) {
 	var err error
 	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
 	auxParams := clientWrapper.DefaultAuxiliaryParams()
@@ -244,43 +401,23 @@ func getClustersOutput(args Arguments) (string, error) {
 
 	if err != nil {
 		if clienterror.IsUnauthorizedError(err) {
-			return "", microerror.Mask(errors.NotAuthorizedError)
+			return nil, microerror.Mask(errors.NotAuthorizedError)
 		}
 		if clienterror.IsAccessForbiddenError(err) {
-			return "", microerror.Mask(errors.AccessForbiddenError)
+			return nil, microerror.Mask(errors.AccessForbiddenError)
 		}
 
-		return "", microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
 	// Create the cluster list table.
 	cTable := createTable(args)
-
-	if args.outputFormat == formatting.OutputFormatJSON {
-		// Filter deleted clusters if seeing them is not desired.
-		var clusterList []*models.V4ClusterListItem
-		{
-			for _, cluster := range response.Payload {
-				if cluster.DeleteDate != nil && !args.showDeleting {
-					continue
-				}
-
-				clusterList = append(clusterList, cluster)
-			}
-		}
-
-		var output string
-		output, err = getJSONOutput(clusterList, cTable, arguments)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
-
-		return output, nil
-	}
+	cTable.SetWide(table.IsWide(args.outputFormat))
 
 	numDeletedClusters := 0
-	numOtherClusters := 0
-	clusterIDs := make([]string, 0, len(response.Payload))
+
+	// The clusters the table rows are created from, in the same order.
+	var clusterList []*models.V4ClusterListItem
 
 	rows := make([][]string, 0, len(response.Payload))
 	for _, cluster := range response.Payload {
@@ -299,10 +436,6 @@ func getClustersOutput(args Arguments) (string, error) {
 			deleted = util.ShortDate(util.ParseDate(cluster.DeleteDate.String()))
 			deleteTime := time.Time(*cluster.DeleteDate)
 			secondsSinceDelete = time.Now().Sub(deleteTime).Seconds()
-		} else {
-			clusterIDs = append(clusterIDs, cluster.ID)
-
-			numOtherClusters++
 		}
 
 		releaseVersion := cluster.ReleaseVersion
@@ -310,12 +443,22 @@ func getClustersOutput(args Arguments) (string, error) {
 			releaseVersion = "n/a"
 		}
 
+		// Values of wide columns are only fetched when displayed.
+		masters, workers, labels := "", "", ""
+		if table.IsWide(args.outputFormat) {
+			masters, workers = getNodeCounts(clientWrapper, auxParams, cluster)
+			labels = formatLabels(cluster.Labels)
+		}
+
 		fields := []string{
 			cluster.ID,
 			cluster.Owner,
 			cluster.Name,
 			releaseVersion,
 			created,
+			masters,
+			workers,
+			labels,
 		}
 		if args.showDeleting {
 			fields = append(fields, color.RedString(deleted))
@@ -329,36 +472,28 @@ func getClustersOutput(args Arguments) (string, error) {
 		}
 
 		rows = append(rows, fields)
+		clusterList = append(clusterList, cluster)
 	}
 	cTable.SetRows(rows)
 
-	err = sortTable(cTable, args)
+	indexes, err := sortAndFilterTable(cTable, args)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
-	clustercache.CacheIDs(args.apiEndpoint, clusterIDs)
-
-	// This function's output string.
-	output := ""
+	clustercache.CacheClusters(args.apiEndpoint, response.Payload)
 
-	// Only show table when there is content.
-	if len(rows) > 0 {
-		output += cTable.String()
-	} else {
-		output += color.YellowString("No clusters")
+	// Render an empty list instead of null in structured output.
+	listing := &clusterListing{
+		table:      cTable,
+		clusters:   make([]*models.V4ClusterListItem, 0, len(indexes)),
+		numDeleted: numDeletedClusters,
 	}
-
-	if !args.showDeleting && numDeletedClusters > 0 {
-		output += "\n\n"
-		if numDeletedClusters == 1 {
-			output += fmt.Sprintf("There is 1 additional cluster currently being deleted. Add the %s flag to see it.", color.CyanString("--show-deleting"))
-		} else {
-			output += fmt.Sprintf("There are %d additional clusters currently being deleted. Add the %s flag to see them.", numDeletedClusters, color.CyanString("--show-deleting"))
-		}
+	for _, i := range indexes {
+		listing.clusters = append(listing.clusters, clusterList[i])
 	}
 
-	return output, nil
+	return listing, nil
 }
 
 func createTable(args Arguments) *table.Table {
@@ -400,6 +535,32 @@ func createTable(args Arguments) *table.Table {
 				SortType: sortable.Date,
 			},
 		},
+		{
+			Name:        tableColMasters,
+			DisplayName: "MASTERS",
+			Sortable: sortable.Sortable{
+				SortType: sortable.String,
+			},
+			Wide:       true,
+			AlignRight: true,
+		},
+		{
+			Name:        tableColWorkers,
+			DisplayName: "WORKERS",
+			Sortable: sortable.Sortable{
+				SortType: sortable.String,
+			},
+			Wide:       true,
+			AlignRight: true,
+		},
+		{
+			Name:        tableColLabels,
+			DisplayName: "LABELS",
+			Sortable: sortable.Sortable{
+				SortType: sortable.String,
+			},
+			Wide: true,
+		},
 		{
 			Name:        tableColDeletingSince,
 			DisplayName: "DELETING SINCE",
@@ -415,98 +576,77 @@ func createTable(args Arguments) *table.Table {
 	return &t
 }
 
-func sortTable(cTable *table.Table, args Arguments) error {
-	var err error
-
+// sortAndFilterTable applies the filters and sort keys given via flags to
+// the table. It returns the indexes of the remaining clusters, in order.
+func sortAndFilterTable(cTable *table.Table, args Arguments) ([]int, error) {
 	// Use the 'id' column by default.
-	sortByColName := tableColID
+	sortBy := tableColID
 	if args.sortBy != "" {
-		sortByColName, err = cTable.GetColumnNameFromInitials(args.sortBy)
-		if err != nil {
-			return microerror.Mask(err)
-		}
+		sortBy = args.sortBy
 	}
 
-	err = cTable.SortByColumnName(sortByColName, sortable.ASC)
+	keys, filters, err := table.ParseSortAndFilter(sortBy, args.filters)
 	if err != nil {
-		return microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
-	return nil
-}
-
-func getJSONOutput(clusterList []*models.V4ClusterListItem, cTable *table.Table, args Arguments) (string, error) {
-	var (
-		err    error
-		output []byte
-	)
-
-	// take the shortest route. no need to call json.Marshal
-	if clusterList == nil || len(clusterList) == 0 {
-		return "[]", nil
+	indexes, err := cTable.SortAndFilter(keys, filters)
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
-	// If there is nothing to sort, let's get this over with.
-	if len(clusterList) < 2 {
-		output, err = json.MarshalIndent(clusterList, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	return indexes, nil
+}
+
+// renderItems renders the cluster list items as custom columns, or in one
+// of the structured output formats.
+func renderItems(items interface{}, outputFormat string) (string, error) {
+	if table.IsCustomColumns(outputFormat) {
+		output, err := table.CustomColumns(outputFormat, items)
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
 
-		return string(output), nil
+		return output, nil
 	}
 
-	sortByColumnName := tableColID
-	var sortByColumn table.Column
-	if args.sortBy != "" {
-		sortByColumnName = args.sortBy
+	outputBytes, err := formatting.Marshal(outputFormat, items)
+	if err != nil {
+		return "", microerror.Mask(err)
 	}
 
-	if sortByColumnName != "" {
-		var colName string
-
-		colName, err = cTable.GetColumnNameFromInitials(sortByColumnName)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
+	return string(outputBytes), nil
+}
 
-		_, sortByColumn, err = cTable.GetColumnByName(colName)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
+// getNodeCounts returns the number of master and worker nodes of a cluster,
+// according to its status, or "n/a" if there is no status.
+func getNodeCounts(clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams, cluster *models.V4ClusterListItem) (string, string) {
+	if cluster.DeleteDate != nil {
+		return "n/a", "n/a"
 	}
 
-	// The table column names, mapped to the json field names in the cluster data structure.
-	fieldMapping := map[string]string{
-		tableColCreateDate:    "create_date",
-		tableColID:            "id",
-		tableColName:          "name",
-		tableColOrg:           "owner",
-		tableColRelease:       "release_version",
-		tableColDeletingSince: "delete_date",
+	status, err := clientWrapper.GetClusterStatus(cluster.ID, auxParams)
+	if err != nil {
+		return "n/a", "n/a"
 	}
 
-	// Convert cluster list to map, with the json field names as keys,
-	// to be able to use same sorting logic as in the table.
-	var clustersAsMapList []map[string]interface{}
-	{
-		var j []byte
-		j, err = json.Marshal(clusterList)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
-		err = json.Unmarshal(j, &clustersAsMapList)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
-	}
+	masters, workers := clusterwait.CountNodes(status)
 
-	table.SortMapSliceUsingColumnData(clustersAsMapList, sortByColumn, fieldMapping)
+	return strconv.Itoa(masters), strconv.Itoa(workers)
+}
 
-	output, err = json.MarshalIndent(clustersAsMapList, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
-	if err != nil {
-		return "", microerror.Mask(err)
+// formatLabels returns cluster labels as a sorted, comma-separated list of
+// key=value pairs.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "n/a"
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
 	}
+	sort.Strings(pairs)
 
-	return string(output), nil
+	return strings.Join(pairs, ",")
 }
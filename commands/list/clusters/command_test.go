@@ -4,10 +4,14 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/table"
 	"github.com/giantswarm/gsctl/testutils"
 	"github.com/spf13/afero"
 )
@@ -169,6 +173,263 @@ func Test_ListClustersEmptyJSON(t *testing.T) {
 	}
 }
 
+// Test_ListClustersJSONPath tests extracting cluster IDs via a JSONPath expression.
+func Test_ListClustersJSONPath(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{"create_date": "2017-05-16T09:30:31.192170835Z", "id": "fow72", "name": "Production", "owner": "acme"},
+			{"create_date": "2017-04-16T09:30:31.192170835Z", "id": "2sg4i", "name": "Staging", "owner": "acme"}
+		]`))
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	var testCases = []struct {
+		outputFormat string
+		expected     string
+	}{
+		{"jsonpath={.items[*].id}", "2sg4i fow72"},
+		{`jsonpath={.items[?(@.name=="Production")].id}`, "fow72"},
+		{`go-template={{range .items}}{{.owner}}/{{.name}}{{"\n"}}{{end}}`, "acme/Staging\nacme/Production"},
+	}
+
+	for i, tc := range testCases {
+		args := Arguments{
+			apiEndpoint:  mockServer.URL,
+			authToken:    "testtoken",
+			outputFormat: tc.outputFormat,
+		}
+
+		err = verifyListClusterPreconditions(args)
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %s", i, err)
+		}
+
+		output, err := getClustersOutput(args)
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %s", i, err)
+		}
+
+		if output != tc.expected {
+			t.Errorf("Case %d - Expected '%s', got '%s'", i, tc.expected, output)
+		}
+	}
+}
+
+// Test_ListClustersWide tests the wide table, custom columns, CSV and TSV output.
+func Test_ListClustersWide(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.String() {
+		case "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"create_date": "2017-05-16T09:30:31.192170835Z", "id": "fow72", "name": "Production", "owner": "acme", "release_version": "12.0.0", "labels": {"team": "rocket", "env": "prod"}},
+				{"create_date": "2017-04-16T09:30:31.192170835Z", "id": "2sg4i", "name": "Staging", "owner": "acme"}
+			]`))
+		case "/v4/clusters/fow72/status/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"cluster": {"nodes": [
+				{"name": "master-1", "labels": {"role": "master"}},
+				{"name": "worker-1", "labels": {"role": "worker"}},
+				{"name": "worker-2", "labels": {"role": "worker"}}
+			]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Status for this cluster is not yet available."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	var testCases = []struct {
+		outputFormat string
+		expected     string
+	}{
+		{
+			"wide",
+			`ID      ORGANIZATION   NAME         RELEASE   CREATED                  MASTERS   WORKERS   LABELS
+2sg4i   acme           Staging      n/a       2017 Apr 16, 09:30 UTC       n/a       n/a   n/a
+fow72   acme           Production   12.0.0    2017 May 16, 09:30 UTC         1         2   env=prod,team=rocket`,
+		},
+		{
+			"custom-columns=NAME:.name,TEAM:.labels.team",
+			`NAME         TEAM
+Staging      <none>
+Production   rocket`,
+		},
+		{
+			"csv",
+			`ID,ORGANIZATION,NAME,RELEASE,CREATED,MASTERS,WORKERS,LABELS
+2sg4i,acme,Staging,n/a,"2017 Apr 16, 09:30 UTC",n/a,n/a,n/a
+fow72,acme,Production,12.0.0,"2017 May 16, 09:30 UTC",1,2,"env=prod,team=rocket"`,
+		},
+		{
+			"tsv",
+			"ID\tORGANIZATION\tNAME\tRELEASE\tCREATED\tMASTERS\tWORKERS\tLABELS\n" +
+				"2sg4i\tacme\tStaging\tn/a\t2017 Apr 16, 09:30 UTC\tn/a\tn/a\tn/a\n" +
+				"fow72\tacme\tProduction\t12.0.0\t2017 May 16, 09:30 UTC\t1\t2\tenv=prod,team=rocket",
+		},
+	}
+
+	for i, tc := range testCases {
+		args := Arguments{
+			apiEndpoint:  mockServer.URL,
+			authToken:    "testtoken",
+			outputFormat: tc.outputFormat,
+		}
+
+		err = verifyListClusterPreconditions(args)
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %s", i, err)
+		}
+
+		output, err := getClustersOutput(args)
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %s", i, err)
+		}
+
+		if output != tc.expected {
+			t.Errorf("Case %d - Expected\n%s\ngot\n%s", i, tc.expected, output)
+		}
+	}
+}
+
+// Test_ListClustersWideReplay tests wide output against API responses
+// recorded via --record.
+func Test_ListClustersWideReplay(t *testing.T) {
+	client.ReplayDir = "testdata/replay-wide"
+	defer func() { client.ReplayDir = "" }()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	args := Arguments{
+		apiEndpoint:  "https://api.g8s.example.com",
+		authToken:    "testtoken",
+		outputFormat: "wide",
+	}
+
+	output, err := getClustersOutput(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `ID      ORGANIZATION   NAME         RELEASE   CREATED                  MASTERS   WORKERS   LABELS
+c7hm5   acme           Production   12.1.4    2020 Sep 30, 11:24 UTC         1         3   giantswarm.io/cluster=c7hm5,giantswarm.io/organization=acme,team=rocket
+zq6nl   acme_dev       Staging      11.5.2    2020 Oct 02, 14:45 UTC       n/a       n/a   n/a
+
+There is 1 additional cluster currently being deleted. Add the --show-deleting flag to see it.`
+	if output != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, output)
+	}
+}
+
+// Test_ListClustersSortAndFilter tests sorting by several keys and filtering,
+// both for table and structured output.
+func Test_ListClustersSortAndFilter(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{"create_date": "2017-05-16T09:30:31.192170835Z", "id": "fow72", "name": "Production", "owner": "acme", "release_version": "12.0.0"},
+			{"create_date": "2017-04-16T09:30:31.192170835Z", "id": "2sg4i", "name": "Staging", "owner": "acme", "release_version": "9.1.0"},
+			{"create_date": "2017-06-16T09:30:31.192170835Z", "id": "k9ds2", "name": "Testing", "owner": "umbrella", "release_version": "12.0.0"},
+			{"create_date": "2017-07-16T09:30:31.192170835Z", "id": "a1b2c", "name": "Preview", "owner": "acme", "release_version": "12.1.0"}
+		]`))
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	var testCases = []struct {
+		outputFormat string
+		sortBy       string
+		filters      []string
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			outputFormat: "jsonpath={.items[*].id}",
+			sortBy:       "release,-created",
+			expected:     "2sg4i k9ds2 fow72 a1b2c",
+		},
+		{
+			outputFormat: "jsonpath={.items[*].id}",
+			sortBy:       "-release,name",
+			filters:      []string{"organization=acme", "release>=12.0.0"},
+			expected:     "a1b2c fow72",
+		},
+		{
+			outputFormat: "table",
+			filters:      []string{"created<2017-06-01"},
+			expected: `ID      ORGANIZATION   NAME         RELEASE   CREATED
+2sg4i   acme           Staging      9.1.0     2017 Apr 16, 09:30 UTC
+fow72   acme           Production   12.0.0    2017 May 16, 09:30 UTC`,
+		},
+		{
+			outputFormat: "table",
+			filters:      []string{"size>1"},
+			errorMatcher: table.IsFieldNotFoundError,
+		},
+		{
+			outputFormat: "table",
+			filters:      []string{"release>latest"},
+			errorMatcher: table.IsInvalidFilterError,
+		},
+	}
+
+	for i, tc := range testCases {
+		args := Arguments{
+			apiEndpoint:  mockServer.URL,
+			authToken:    "testtoken",
+			filters:      tc.filters,
+			outputFormat: tc.outputFormat,
+			sortBy:       tc.sortBy,
+		}
+
+		err = verifyListClusterPreconditions(args)
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %s", i, err)
+		}
+
+		output, err := getClustersOutput(args)
+		if tc.errorMatcher != nil {
+			if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Unexpected error: %#v", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %s", i, err)
+		}
+
+		if output != tc.expected {
+			t.Errorf("Case %d - Expected\n%s\ngot\n%s", i, tc.expected, output)
+		}
+	}
+}
+
 // Test_ListClustersUnauthorized tests listing clusters with a 401 response.
 func Test_ListClustersUnauthorized(t *testing.T) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -266,3 +527,110 @@ func Test_ListClustersBySelector(t *testing.T) {
 
 	t.Log(jsonRepresentation)
 }
+
+// Test_ListClustersSnapshot tests the snapshot used with --watch, and
+// that --watch is rejected with output formats not supporting it.
+func Test_ListClustersSnapshot(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{
+				"create_date": "2017-05-16T09:30:31.192170835Z",
+				"id": "fow72",
+				"name": "Production",
+				"owner": "acme"
+			},
+			{
+				"create_date": "2017-04-16T09:30:31.192170835Z",
+				"id": "2sg4i",
+				"name": "Staging",
+				"owner": "acme"
+			},
+			{
+				"create_date": "2017-10-10T07:24:55.192170835Z",
+				"delete_date": "2019-10-10T07:24:55.192170835Z",
+				"id": "del01",
+				"name": "A deleted cluster",
+				"owner": "acme"
+			}
+		]`))
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	args := Arguments{
+		apiEndpoint:  mockServer.URL,
+		authToken:    "testtoken",
+		outputFormat: "table",
+		watch:        true,
+	}
+
+	err = verifyListClusterPreconditions(args)
+	if err != nil {
+		t.Error(err)
+	}
+
+	snapshot, err := getClustersSnapshot(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snapshot.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(snapshot.Items))
+	}
+	if snapshot.Items[0].Key != "2sg4i" || snapshot.Items[1].Key != "fow72" {
+		t.Errorf("Expected items sorted by ID, got %q and %q", snapshot.Items[0].Key, snapshot.Items[1].Key)
+	}
+	if !strings.Contains(snapshot.Items[1].Line, "Production") {
+		t.Errorf("Expected line to contain the cluster name, got %q", snapshot.Items[1].Line)
+	}
+	if !strings.HasPrefix(snapshot.Header, "ID") {
+		t.Errorf("Expected table header, got %q", snapshot.Header)
+	}
+	if !strings.HasPrefix(snapshot.Footer, "There is 1 additional cluster currently being deleted.") {
+		t.Errorf("Expected note on deleted cluster, got %q", snapshot.Footer)
+	}
+
+	args.outputFormat = "csv"
+	err = verifyListClusterPreconditions(args)
+	if !errors.IsOutputFormatInvalid(err) {
+		t.Errorf("Expected OutputFormatInvalidError, got %#v", err)
+	}
+}
+
+// Test_PrintValidationOutput tests that nothing is printed to stdout
+// before the actual output, so that CSV output can be redirected to a file
+// and extracted fields can be used in scripts.
+func Test_PrintValidationOutput(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags.APIEndpoint = "https://foo"
+	flags.Token = "testtoken"
+	defer func() {
+		flags.APIEndpoint = ""
+		flags.Token = ""
+		flags.OutputFormat = ""
+	}()
+
+	outputFormats := []string{table.OutputFormatCSV, "jsonpath={.items[*].id}", `go-template={{len .items}}`}
+	for _, outputFormat := range outputFormats {
+		flags.OutputFormat = outputFormat
+
+		output := testutils.CaptureOutput(func() {
+			printValidation(Command, []string{})
+		})
+		if output != "" {
+			t.Errorf("Expected no output for '%s', got %q", outputFormat, output)
+		}
+	}
+}
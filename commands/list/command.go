@@ -4,6 +4,7 @@ package list
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/commands/list/apps"
 	"github.com/giantswarm/gsctl/commands/list/clusters"
 	"github.com/giantswarm/gsctl/commands/list/endpoints"
 	"github.com/giantswarm/gsctl/commands/list/keypairs"
@@ -16,12 +17,13 @@ var (
 	// Command is the command to list things.
 	Command = &cobra.Command{
 		Use:   "list",
-		Short: "List clusters, endpoints, key pairs, node pools, organizations, releases",
+		Short: "List apps, clusters, endpoints, key pairs, node pools, organizations, releases",
 		Long:  `Prints a list of the things you have access to.`,
 	}
 )
 
 func init() {
+	Command.AddCommand(apps.Command)
 	Command.AddCommand(clusters.Command)
 	Command.AddCommand(endpoints.Command)
 	Command.AddCommand(keypairs.Command)
@@ -3,15 +3,18 @@ package endpoints
 
 import (
 	"fmt"
+	"os"
 	"sort"
-	"strings"
 
 	"github.com/fatih/color"
-	"github.com/giantswarm/columnize"
 	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/table"
 )
 
 var (
@@ -20,18 +23,41 @@ var (
 		Use:     "endpoints",
 		Aliases: []string{"endpoint"},
 		Short:   "List API endpoints",
-		Long:    `Prints a list of API endpoints you have used so far`,
-		Run:     listEndpoints,
+		Long: `Prints a list of API endpoints you have used so far
+
+With --output json or --output yaml, each endpoint is printed with the fields
+alias, url, email, selected and logged_in.
+
+Examples:
+
+  gsctl list endpoints
+  gsctl list endpoints --output json
+`,
+		Run: listEndpoints,
 	}
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Use '%s=<HEADER>:<expression>,...' to choose the columns, or '%s' or '%s' for comma or tab separated values. Defaults to human-friendly table output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate, table.OutputFormatCustomColumns, table.OutputFormatCSV, table.OutputFormatTSV))
+	Command.Flags().StringVarP(&flags.Sort, "sort", "s", "", "Sort by one or more comma separated fields, like 'logged-in,endpoint-url'. Prefix a field with '-' to sort in descending order. Defaults to sorting by alias and URL.")
+	Command.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Only list endpoints meeting a condition, like 'email=me@example.com' or 'logged-in=yes'. Supported operators are =, !=, >, >=, < and <=. Can be specified multiple times.")
+}
+
 // Arguments are the arguments we pass to the actual functions
 // listing endpoints and printing endpoints lists
 // TODO: apiEndpoint is the only argument used. The rest can be removed.
 type Arguments struct {
-	apiEndpoint string
-	scheme      string
-	token       string
+	apiEndpoint  string
+	filters      []string
+	outputFormat string
+	scheme       string
+	sortBy       string
+	token        string
 }
 
 // collectArguments returns Arguments
@@ -41,26 +67,55 @@ func collectArguments() Arguments {
 	token := config.Config.ChooseToken(endpoint, flags.Token)
 	scheme := config.Config.ChooseScheme(endpoint, flags.Token)
 	return Arguments{
-		apiEndpoint: endpoint,
-		token:       token,
-		scheme:      scheme,
+		apiEndpoint:  endpoint,
+		filters:      flags.Filter,
+		outputFormat: flags.OutputFormat,
+		token:        token,
+		scheme:       scheme,
+		sortBy:       flags.Sort,
 	}
 }
 
 // listEndpoints prints a table with all endpoint URLs the user has used
 func listEndpoints(cmd *cobra.Command, args []string) {
 	myArgs := collectArguments()
-	output := endpointsTable(myArgs)
+	output, err := endpointsTable(myArgs)
+	if err != nil {
+		errors.HandleCommonErrors(err)
+
+		fmt.Println(color.RedString(err.Error()))
+		os.Exit(errors.ExitCode(err))
+	}
 	if output != "" {
 		fmt.Println(output)
 	}
 }
 
-// endpointsTable returns a table of clusters the user has access to
-func endpointsTable(args Arguments) string {
-	if len(config.Config.Endpoints()) == 0 {
+// endpointOutput is the structure of one endpoint in the structured output.
+type endpointOutput struct {
+	Alias    string `json:"alias,omitempty"`
+	URL      string `json:"url"`
+	Email    string `json:"email,omitempty"`
+	Selected bool   `json:"selected"`
+	LoggedIn bool   `json:"logged_in"`
+}
+
+// endpointsTable returns the endpoints the user has used, in the output
+// format given via the arguments.
+func endpointsTable(args Arguments) (string, error) {
+	outputFormat := args.outputFormat
+	if outputFormat != "" && !formatting.IsStructured(outputFormat) && !table.IsTableOutput(outputFormat) {
+		return "", microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", outputFormat)
+	}
+
+	keys, filters, err := table.ParseSortAndFilter(args.sortBy, args.filters)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if len(config.Config.Endpoints()) == 0 && !formatting.IsStructured(outputFormat) && !table.IsDelimited(outputFormat) {
 		return fmt.Sprintf("No endpoints configured.\n\nTo add an endpoint and authenticate for it, use\n\n\t%s\n",
-			color.YellowString("gsctl login <email> -e <endpoint>"))
+			color.YellowString("gsctl login <email> -e <endpoint>")), nil
 	}
 
 	// get keys (URLs) and sort by them
@@ -94,22 +149,30 @@ func endpointsTable(args Arguments) string {
 		return aliasi < aliasj
 	})
 
-	// table headers
-	output := []string{}
-	headers := []string{}
-
-	if hasAlias {
-		headers = append(headers, color.CyanString("ALIAS"))
-	}
-	headers = append(headers, color.CyanString("ENDPOINT URL"))
-	headers = append(headers, color.CyanString("EMAIL"))
-	headers = append(headers, color.CyanString("SELECTED"))
-	headers = append(headers, color.CyanString("LOGGED IN"))
-	output = append(output, strings.Join(headers, "|"))
+	t := table.New()
+	t.SetGlue("  ")
+	t.SetColumns([]table.Column{
+		// The alias column is only shown if there is an alias.
+		{Name: "alias", DisplayName: "ALIAS", Hidden: !hasAlias},
+		{Name: "endpoint-url", DisplayName: "ENDPOINT URL"},
+		{Name: "email", DisplayName: "EMAIL"},
+		{Name: "selected", DisplayName: "SELECTED"},
+		{Name: "logged-in", DisplayName: "LOGGED IN"},
+	})
 
+	rows := make([][]string, 0, len(endpointURLs))
+	items := make([]endpointOutput, 0, len(endpointURLs))
 	for _, endpoint := range endpointURLs {
 		endpointConfig := config.Config.EndpointConfig(endpoint)
 
+		items = append(items, endpointOutput{
+			Alias:    endpointConfig.Alias,
+			URL:      endpoint,
+			Email:    endpointConfig.Email,
+			Selected: endpoint == args.apiEndpoint,
+			LoggedIn: endpointConfig.Token != "",
+		})
+
 		selected := "no"
 		loggedIn := "no"
 		email := "n/a"
@@ -131,27 +194,49 @@ func endpointsTable(args Arguments) string {
 			email = endpointConfig.Email
 		}
 
-		columns := []string{}
+		row := []string{alias, endpoint, email, selected, loggedIn}
 		if endpoint == args.apiEndpoint {
 			// highlight if selected
-			if hasAlias {
-				columns = append(columns, color.YellowString(alias))
+			for i := range row {
+				row[i] = color.YellowString(row[i])
 			}
-			columns = append(columns, color.YellowString(endpoint))
-			columns = append(columns, color.YellowString(email))
-			columns = append(columns, color.YellowString(selected))
-			columns = append(columns, color.YellowString(loggedIn))
-		} else {
-			if hasAlias {
-				columns = append(columns, alias)
+		}
+		rows = append(rows, row)
+	}
+	t.SetRows(rows)
+
+	indexes, err := t.SortAndFilter(keys, filters)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if formatting.IsStructured(outputFormat) || table.IsCustomColumns(outputFormat) {
+		sortedItems := make([]endpointOutput, 0, len(indexes))
+		for _, i := range indexes {
+			sortedItems = append(sortedItems, items[i])
+		}
+
+		if table.IsCustomColumns(outputFormat) {
+			output, err := table.CustomColumns(outputFormat, sortedItems)
+			if err != nil {
+				return "", microerror.Mask(err)
 			}
-			columns = append(columns, endpoint)
-			columns = append(columns, email)
-			columns = append(columns, selected)
-			columns = append(columns, loggedIn)
+
+			return output, nil
+		}
+
+		outputBytes, err := formatting.Marshal(outputFormat, sortedItems)
+		if err != nil {
+			return "", microerror.Mask(err)
 		}
-		output = append(output, strings.Join(columns, "|"))
+
+		return string(outputBytes), nil
+	}
+
+	output, err := t.Format(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
 	}
 
-	return columnize.SimpleFormat(output)
+	return output, nil
 }
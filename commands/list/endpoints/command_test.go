@@ -37,7 +37,10 @@ selected_endpoint: https://my.second.endpoint
 		apiEndpoint: config.Config.ChooseEndpoint(""),
 	}
 
-	table := endpointsTable(args)
+	table, err := endpointsTable(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
 	if table == "" {
 		t.Error("Got no output where I expected a table")
 	}
@@ -48,3 +51,89 @@ selected_endpoint: https://my.second.endpoint
 		t.Errorf("Table does not contain expected row '%s'", testString)
 	}
 }
+
+// Test_ListEndpointsFiltered tests filtering the list of endpoints.
+func Test_ListEndpointsFiltered(t *testing.T) {
+	yamlText := `last_version_check: 0001-01-01T00:00:00Z
+updated: 2017-09-29T11:23:15+02:00
+endpoints:
+  https://my.first.endpoint:
+    email: email@example.com
+    token: some-token
+  https://my.second.endpoint:
+    email: other@example.com
+selected_endpoint: https://my.second.endpoint
+`
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, yamlText)
+	if err != nil {
+		t.Error(err)
+	}
+
+	args := Arguments{
+		apiEndpoint: config.Config.ChooseEndpoint(""),
+		filters:     []string{"logged-in=yes"},
+	}
+
+	table, err := endpointsTable(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `ENDPOINT URL               EMAIL              SELECTED  LOGGED IN
+https://my.first.endpoint  email@example.com  no        yes`
+	if table != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, table)
+	}
+}
+
+// Test_ListEndpointsStructuredOutput tests JSON output of the endpoint list.
+func Test_ListEndpointsStructuredOutput(t *testing.T) {
+	yamlText := `last_version_check: 0001-01-01T00:00:00Z
+updated: 2017-09-29T11:23:15+02:00
+endpoints:
+  https://my.first.endpoint:
+    email: email@example.com
+    token: some-token
+    alias: first
+  https://my.second.endpoint:
+    email: other@example.com
+selected_endpoint: https://my.second.endpoint
+`
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, yamlText)
+	if err != nil {
+		t.Error(err)
+	}
+
+	args := Arguments{
+		apiEndpoint:  config.Config.ChooseEndpoint(""),
+		outputFormat: "json",
+	}
+
+	output, err := endpointsTable(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `[
+  {
+    "alias": "first",
+    "url": "https://my.first.endpoint",
+    "email": "email@example.com",
+    "selected": false,
+    "logged_in": true
+  },
+  {
+    "url": "https://my.second.endpoint",
+    "email": "other@example.com",
+    "selected": true,
+    "logged_in": false
+  }
+]`
+	if output != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, output)
+	}
+}
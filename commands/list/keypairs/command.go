@@ -2,15 +2,12 @@
 package keypairs
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/fatih/color"
-	"github.com/giantswarm/columnize"
 	"github.com/giantswarm/gscliauth/config"
 	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/gsctl/clustercache"
@@ -22,6 +19,8 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/sortable"
+	"github.com/giantswarm/gsctl/pkg/table"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -46,8 +45,10 @@ var (
 type Arguments struct {
 	apiEndpoint       string
 	clusterNameOrID   string
+	filters           []string
 	full              bool
 	outputFormat      string
+	sortBy            string
 	token             string
 	userProvidedToken string
 	scheme            string
@@ -63,8 +64,10 @@ func collectArguments() Arguments {
 	return Arguments{
 		apiEndpoint:       endpoint,
 		clusterNameOrID:   flags.ClusterID,
+		filters:           flags.Filter,
 		full:              flags.Full,
 		outputFormat:      flags.OutputFormat,
+		sortBy:            flags.Sort,
 		token:             token,
 		userProvidedToken: flags.Token,
 		scheme:            scheme,
@@ -85,7 +88,9 @@ func initFlags() {
 
 	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "Name/ID of the cluster to list key pairs for")
 	Command.Flags().BoolVarP(&flags.Full, "full", "", false, "Enables output of full, untruncated values")
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Use '%s' to add the TTL and show untruncated values in the table, or '%s=<HEADER>:<expression>,...' to choose the columns. Use '%s' or '%s' for comma or tab separated values with all columns. Defaults to human-friendly table output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate, table.OutputFormatWide, table.OutputFormatCustomColumns, table.OutputFormatCSV, table.OutputFormatTSV))
+	Command.Flags().StringVarP(&flags.Sort, "sort", "s", "", "Sort by one or more comma separated fields, like 'cn,-expires'. Prefix a field with '-' to sort in descending order. Defaults to sorting by creation date.")
+	Command.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Only list key pairs meeting a condition, like 'o=system:masters' or 'expires>2021-01-01'. Supported operators are =, !=, >, >=, < and <=. Can be specified multiple times.")
 
 	Command.MarkFlagRequired("cluster")
 }
@@ -100,7 +105,7 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 		errors.HandleCommonErrors(err)
 
 		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -116,9 +121,12 @@ func listKeypairsValidate(args *Arguments) error {
 	if config.Config.Token == "" && args.token == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
+	if !formatting.IsStructured(args.outputFormat) && !table.IsTableOutput(args.outputFormat) {
 		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is unknown", args.outputFormat))
 	}
+	if _, _, err := table.ParseSortAndFilter(args.sortBy, args.filters); err != nil {
+		return microerror.Mask(err)
+	}
 
 	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
 	if err != nil {
@@ -163,62 +171,125 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
-		outputBytes, err := json.MarshalIndent(result.keypairs, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	if table.IsCustomColumns(arguments.outputFormat) {
+		output, err := table.CustomColumns(arguments.outputFormat, result.keypairs)
 		if err != nil {
-			fmt.Println(color.RedString("Error while encoding JSON"))
+			fmt.Println(color.RedString("Error while encoding the output"))
 			fmt.Printf("Details: %s", err.Error())
-			os.Exit(1)
+			os.Exit(errors.ExitCode(err))
+		}
+
+		fmt.Println(output)
+	} else if formatting.IsStructured(arguments.outputFormat) {
+		outputBytes, err := formatting.Marshal(arguments.outputFormat, result.keypairs)
+		if err != nil {
+			fmt.Println(color.RedString("Error while encoding the output"))
+			fmt.Printf("Details: %s", err.Error())
+			os.Exit(errors.ExitCode(err))
 		}
 
 		fmt.Println(string(outputBytes))
 	} else {
 		// success output
-		if len(result.keypairs) == 0 {
+		if len(result.keypairs) == 0 && !table.IsDelimited(arguments.outputFormat) {
 			fmt.Println(color.YellowString("No key pairs available for this cluster."))
 			fmt.Println("You can create a new key pair using the 'gsctl create kubeconfig' or 'gsctl create keypair' command.")
 		} else {
-			output := []string{}
-
-			headers := []string{
-				color.CyanString("CREATED"),
-				color.CyanString("EXPIRES"),
-				color.CyanString("ID"),
-				color.CyanString("DESCRIPTION"),
-				color.CyanString("CN"),
-				color.CyanString("O"),
-			}
-			output = append(output, strings.Join(headers, "|"))
-
-			for _, keypair := range result.keypairs {
-				createdTime := util.ParseDate(keypair.CreateDate)
-				expiryTime := createdTime.Add(time.Duration(keypair.TTLHours) * time.Hour)
-				expiryDuration := expiryTime.Sub(time.Now())
-				expires := util.ShortDate(expiryTime)
-
-				if expiryDuration < (24 * time.Hour) {
-					expires = color.YellowString(expires)
-				}
-
-				// Idea: skip if expired, or only display when verbose
-				row := []string{
-					util.ShortDate(createdTime),
-					expires,
-					util.Truncate(formatting.CleanKeypairID(keypair.ID), 10, !arguments.full),
-					keypair.Description,
-					util.Truncate(keypair.CommonName, 24, !arguments.full),
-					keypair.CertificateOrganizations,
-				}
-				output = append(output, strings.Join(row, "|"))
+			output, err := getTable(result.keypairs, arguments.full, arguments.outputFormat)
+			if err != nil {
+				fmt.Println(color.RedString("Error while encoding the output"))
+				fmt.Printf("Details: %s", err.Error())
+				os.Exit(errors.ExitCode(err))
 			}
-			fmt.Println(columnize.SimpleFormat(output))
+
+			fmt.Println(output)
 		}
 	}
 }
 
+// getTable renders the key pairs as a table in the given output format.
+// Wide output adds the TTL and, like full output, shows untruncated values.
+func getTable(keypairs []*models.V4GetKeyPairsResponseItems, full bool, outputFormat string) (string, error) {
+	t := createTable(keypairs, full, table.IsWide(outputFormat))
+
+	output, err := t.Format(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return output, nil
+}
+
+// createTable creates the table of key pairs, with wide columns
+// and untruncated values if wide is true.
+func createTable(keypairs []*models.V4GetKeyPairsResponseItems, full, wide bool) *table.Table {
+	t := table.New()
+	t.SetGlue("  ")
+	t.SetWide(wide)
+	t.SetColumns([]table.Column{
+		{Name: "created", DisplayName: "CREATED", Sortable: sortable.Sortable{SortType: sortable.Date}},
+		{Name: "expires", DisplayName: "EXPIRES", Sortable: sortable.Sortable{SortType: sortable.Date}},
+		{Name: "id", DisplayName: "ID"},
+		{Name: "description", DisplayName: "DESCRIPTION"},
+		{Name: "cn", DisplayName: "CN"},
+		{Name: "o", DisplayName: "O"},
+		{Name: "ttl", DisplayName: "TTL", Wide: true},
+	})
+
+	truncate := !full && !wide
+
+	rows := make([][]string, 0, len(keypairs))
+	for _, keypair := range keypairs {
+		createdTime := util.ParseDate(keypair.CreateDate)
+		expiryTime := createdTime.Add(time.Duration(keypair.TTLHours) * time.Hour)
+		expiryDuration := expiryTime.Sub(time.Now())
+		expires := util.ShortDate(expiryTime)
+
+		if expiryDuration < (24 * time.Hour) {
+			expires = color.YellowString(expires)
+		}
+
+		// Idea: skip if expired, or only display when verbose
+		rows = append(rows, []string{
+			util.ShortDate(createdTime),
+			expires,
+			util.Truncate(formatting.CleanKeypairID(keypair.ID), 10, truncate),
+			keypair.Description,
+			util.Truncate(keypair.CommonName, 24, truncate),
+			keypair.CertificateOrganizations,
+			fmt.Sprintf("%dh", keypair.TTLHours),
+		})
+	}
+	t.SetRows(rows)
+
+	return &t
+}
+
+// sortAndFilter applies the filters and sort keys given via flags to the
+// key pairs, based on the values shown in the table.
+func sortAndFilter(keypairs []*models.V4GetKeyPairsResponseItems, args Arguments) ([]*models.V4GetKeyPairsResponseItems, error) {
+	keys, filters, err := table.ParseSortAndFilter(args.sortBy, args.filters)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	t := createTable(keypairs, true, true)
+	indexes, err := t.SortAndFilter(keys, filters)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	result := make([]*models.V4GetKeyPairsResponseItems, 0, len(indexes))
+	for _, i := range indexes {
+		result = append(result, keypairs[i])
+	}
+
+	return result, nil
+}
+
 // listKeypairs fetches keypairs for a cluster from the API
 // and returns them as a structured result.
 func listKeypairs(args Arguments) (listKeypairsResult, error) {
@@ -229,7 +300,7 @@ func listKeypairs(args Arguments) (listKeypairsResult, error) {
 		return result, microerror.Mask(err)
 	}
 
-	clusterID, err := clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+	clusterID, err := clustercache.GetIDCached(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
 	if err != nil {
 		return result, microerror.Mask(err)
 	}
@@ -259,7 +330,10 @@ func listKeypairs(args Arguments) (listKeypairsResult, error) {
 		})
 	}
 
-	result.keypairs = response.Payload
+	result.keypairs, err = sortAndFilter(response.Payload, args)
+	if err != nil {
+		return result, microerror.Mask(err)
+	}
 
 	return result, nil
 }
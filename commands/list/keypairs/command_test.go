@@ -246,7 +246,36 @@ func Test_ListKeyPairsOutput(t *testing.T) {
 			}, "\n"),
 		},
 		{
-			name: "case 2: JSON output",
+			name: "case 2: wide table output",
+			args: []string{"-c=foo", "-o=wide"},
+			expectedOutput: strings.Join([]string{
+				"CREATED                 EXPIRES                 ID                                        DESCRIPTION                                                      CN  O  TTL",
+				"2017 Jan 23, 13:57 UTC  2017 Feb 22, 13:57 UTC  742dded26b9f4da5e50deb6e9814026c7940f658  Added by user oliver.ponder@gmail.com using Happa web interface         720h",
+				"2017 Mar 17, 12:41 UTC  2017 Apr 16, 12:41 UTC  52647dca753c7b46062fa0ce429a76c92b76aa9e  Added by user marian@sendung.de using 'gsctl create kubeconfig'         720h",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "case 3: table output, sorted and filtered",
+			args: []string{"-c=foo", "--sort=-created", "--filter=description!=foo", "--filter=expires<2017-03-01"},
+			expectedOutput: strings.Join([]string{
+				"CREATED                 EXPIRES                 ID          DESCRIPTION                                                      CN  O",
+				"2017 Jan 23, 13:57 UTC  2017 Feb 22, 13:57 UTC  742dded26…  Added by user oliver.ponder@gmail.com using Happa web interface      ",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "case 4: table output, sorted in descending order",
+			args: []string{"-c=foo", "--sort=-cr"},
+			expectedOutput: strings.Join([]string{
+				"CREATED                 EXPIRES                 ID          DESCRIPTION                                                      CN  O",
+				"2017 Mar 17, 12:41 UTC  2017 Apr 16, 12:41 UTC  52647dca7…  Added by user marian@sendung.de using 'gsctl create kubeconfig'      ",
+				"2017 Jan 23, 13:57 UTC  2017 Feb 22, 13:57 UTC  742dded26…  Added by user oliver.ponder@gmail.com using Happa web interface      ",
+				"",
+			}, "\n"),
+		},
+		{
+			name: "case 5: JSON output",
 			args: []string{"-c=foo", "-o=json"},
 			expectedOutput: strings.Join([]string{
 				jsonOutput,
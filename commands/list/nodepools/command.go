@@ -2,15 +2,14 @@
 package nodepools
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
-	"github.com/giantswarm/columnize"
 	"github.com/giantswarm/gscliauth/config"
 	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/microerror"
@@ -24,6 +23,8 @@ import (
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/nodespec"
+	"github.com/giantswarm/gsctl/pkg/table"
+	"github.com/giantswarm/gsctl/pkg/watch"
 )
 
 var (
@@ -55,6 +56,15 @@ columns:
 	CPUS:                  Sum of CPU cores in nodes that are in state Ready
 	RAM (GB):              Sum of memory in GB of all nodes that are in state Ready
 
+With --output wide, the docker and kubelet volume sizes of the worker nodes
+are shown in addition.
+
+With --watch, the list is updated every few seconds. Rows of node pools which
+have been added since the last update are marked with '+', changed rows with
+'~' and removed rows with '-'. With --output json or --output yaml, one event
+with the fields 'type' (ADDED, MODIFIED or DELETED) and 'object' is printed
+per change instead.
+
 To see all available details for a cluster, use 'gsctl show nodepool <cluster-id>/<nodepool-id>'.
 
 To list all clusters you have access to, use 'gsctl list clusters'.
@@ -73,17 +83,25 @@ func init() {
 }
 
 func initFlags() {
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Use '%s' to add volume sizes to the table, or '%s=<HEADER>:<expression>,...' to choose the columns. Use '%s' or '%s' for comma or tab separated values with all columns. Defaults to human-friendly table output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate, table.OutputFormatWide, table.OutputFormatCustomColumns, table.OutputFormatCSV, table.OutputFormatTSV))
+	Command.Flags().StringVarP(&flags.Sort, "sort", "s", "", "Sort by one or more comma separated fields, like 'instance-type,-nodes-ready'. Prefix a field with '-' to sort in descending order. Defaults to sorting by ID.")
+	Command.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Only list node pools meeting a condition, like 'az=A' or 'nodes-ready>=3'. Supported operators are =, !=, >, >=, < and <=. Can be specified multiple times.")
+	Command.Flags().BoolVarP(&flags.Watch, "watch", "w", false, "Keep polling and update the list when node pools change.")
+	Command.Flags().DurationVarP(&flags.WatchInterval, "watch-interval", "", watch.DefaultInterval, "Time between two updates with --watch.")
 }
 
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
 	clusterNameOrID   string
+	filters           []string
 	outputFormat      string
 	scheme            string
+	sortBy            string
 	userProvidedToken string
 	verbose           bool
+	watch             bool
+	watchInterval     time.Duration
 }
 
 // collectArguments creates arguments based on command line flags and config.
@@ -96,10 +114,14 @@ func collectArguments(cmdLineArgs []string) Arguments {
 		apiEndpoint:       endpoint,
 		authToken:         token,
 		clusterNameOrID:   cmdLineArgs[0],
+		filters:           flags.Filter,
 		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
+		sortBy:            flags.Sort,
 		userProvidedToken: flags.Token,
 		verbose:           flags.Verbose,
+		watch:             flags.Watch,
+		watchInterval:     flags.WatchInterval,
 	}
 }
 
@@ -110,21 +132,29 @@ func verifyPreconditions(args Arguments, positionalArgs []string) error {
 	if config.Config.Token == "" && args.authToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
+	if !formatting.IsStructured(args.outputFormat) && !table.IsTableOutput(args.outputFormat) {
 		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
 	}
+	if args.watch && !watch.SupportsOutputFormat(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is not supported with --watch", args.outputFormat)
+	}
+	if _, _, err := table.ParseSortAndFilter(args.sortBy, args.filters); err != nil {
+		return microerror.Mask(err)
+	}
 
 	return nil
 }
 
 func printValidation(cmd *cobra.Command, positionalArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "list nodepools", "get nodepools", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-nodepools/"))
+	// The notice goes to stderr, so that it does not end up in output
+	// processed by other tools, like CSV or JSON.
+	fmt.Fprint(os.Stderr, util.GetDeprecatedNotice(config.Config.Provider, "list nodepools", "get nodepools", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-nodepools/"))
 
 	arguments = collectArguments(positionalArgs)
 	err := verifyPreconditions(arguments, positionalArgs)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -136,7 +166,7 @@ func fetchNodePools(args Arguments) ([]*models.V5GetNodePoolsResponseItems, erro
 		return nil, microerror.Mask(err)
 	}
 
-	clusterID, err := clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+	clusterID, err := clustercache.GetIDCached(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
@@ -170,10 +200,25 @@ func fetchNodePools(args Arguments) ([]*models.V5GetNodePoolsResponseItems, erro
 }
 
 func printResult(cmd *cobra.Command, positionalArgs []string) {
+	if arguments.watch {
+		err := watch.Run(watch.Config{
+			Fetch:        func() (*watch.Snapshot, error) { return getSnapshot(arguments) },
+			Interval:     arguments.watchInterval,
+			OutputFormat: arguments.outputFormat,
+			Title:        cmd.CommandPath() + " " + arguments.clusterNameOrID,
+			Redraw:       watch.IsTerminal(os.Stdout),
+		})
+		if err != nil {
+			handleError(err)
+			os.Exit(errors.ExitCode(err))
+		}
+		return
+	}
+
 	nodePools, err := fetchNodePools(arguments)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	if len(nodePools) == 0 {
@@ -181,10 +226,10 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		return
 	}
 
-	output, err := getOutput(nodePools, arguments.outputFormat)
+	output, err := getOutput(nodePools, arguments)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 	// Display output.
 	fmt.Println(output)
@@ -198,13 +243,29 @@ func formatNodesReady(nodes, nodesReady int64) string {
 	return color.YellowString(strconv.FormatInt(nodesReady, 10))
 }
 
-func getOutput(nps []*models.V5GetNodePoolsResponseItems, outputFormat string) (string, error) {
+func getOutput(nps []*models.V5GetNodePoolsResponseItems, args Arguments) (string, error) {
 	if len(nps) < 0 {
 		return "", nil
 	}
 
-	if outputFormat == formatting.OutputFormatJSON {
-		outputBytes, err := json.MarshalIndent(nps, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	outputFormat := args.outputFormat
+
+	t, items, err := getSortedTable(nps, args)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if formatting.IsStructured(outputFormat) || table.IsCustomColumns(outputFormat) {
+		if table.IsCustomColumns(outputFormat) {
+			output, err := table.CustomColumns(outputFormat, items)
+			if err != nil {
+				return "", microerror.Mask(err)
+			}
+
+			return output, nil
+		}
+
+		outputBytes, err := formatting.Marshal(outputFormat, items)
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
@@ -212,58 +273,134 @@ func getOutput(nps []*models.V5GetNodePoolsResponseItems, outputFormat string) (
 		return string(outputBytes), nil
 	}
 
-	var output string
+	output, err := t.Format(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return output, nil
+}
+
+// getSortedTable returns the node pools table, sorted and filtered
+// according to the arguments, and the node pools of the table rows in the
+// same order.
+func getSortedTable(nps []*models.V5GetNodePoolsResponseItems, args Arguments) (*table.Table, []*models.V5GetNodePoolsResponseItems, error) {
+	var t *table.Table
 	var err error
 	np := nps[0]
 
 	if np.NodeSpec.Aws != nil && np.NodeSpec.Azure == nil {
-		output, err = getOutputAWS(nps)
+		t, err = getTableAWS(nps)
 		if err != nil {
-			return "", microerror.Mask(err)
+			return nil, nil, microerror.Mask(err)
 		}
 	} else if np.NodeSpec.Azure != nil && np.NodeSpec.Aws == nil {
-		output, err = getOutputAzure(nps)
+		t, err = getTableAzure(nps)
 		if err != nil {
-			return "", microerror.Mask(err)
+			return nil, nil, microerror.Mask(err)
 		}
 	} else {
-		return "", microerror.Mask(errors.ClusterDoesNotSupportNodePoolsError)
+		return nil, nil, microerror.Mask(errors.ClusterDoesNotSupportNodePoolsError)
 	}
 
-	return output, nil
+	keys, filters, err := table.ParseSortAndFilter(args.sortBy, args.filters)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	indexes, err := t.SortAndFilter(keys, filters)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	items := make([]*models.V5GetNodePoolsResponseItems, 0, len(indexes))
+	for _, i := range indexes {
+		items = append(items, nps[i])
+	}
+
+	t.SetGlue("  ")
+	t.SetWide(table.IsWide(args.outputFormat))
+
+	return t, items, nil
+}
+
+// getSnapshot returns the node pools of the cluster for watching, with
+// node pool IDs as keys.
+func getSnapshot(args Arguments) (*watch.Snapshot, error) {
+	nodePools, err := fetchNodePools(args)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if len(nodePools) == 0 {
+		return &watch.Snapshot{Footer: color.YellowString("This cluster has no node pools")}, nil
+	}
+
+	t, items, err := getSortedTable(nodePools, args)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	keys := make([]string, 0, len(items))
+	objects := make([]interface{}, 0, len(items))
+	for _, np := range items {
+		keys = append(keys, np.ID)
+		objects = append(objects, np)
+	}
+
+	return watch.NewTableSnapshot(t, keys, objects), nil
+}
+
+// volumeSizeColumns are the columns only displayed in wide output,
+// common to all providers.
+var volumeSizeColumns = []table.Column{
+	{Name: "docker-volume", DisplayName: "DOCKER VOLUME (GB)", Wide: true, AlignRight: true},
+	{Name: "kubelet-volume", DisplayName: "KUBELET VOLUME (GB)", Wide: true, AlignRight: true},
 }
 
-func getOutputAWS(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
+// getVolumeSizes returns the values for the volume size columns.
+func getVolumeSizes(np *models.V5GetNodePoolsResponseItems) []string {
+	if np.NodeSpec.VolumeSizesGb == nil {
+		return []string{"n/a", "n/a"}
+	}
+
+	return []string{
+		strconv.FormatInt(np.NodeSpec.VolumeSizesGb.Docker, 10),
+		strconv.FormatInt(np.NodeSpec.VolumeSizesGb.Kubelet, 10),
+	}
+}
+
+func getTableAWS(nps []*models.V5GetNodePoolsResponseItems) (*table.Table, error) {
 	awsInfo, err := nodespec.NewAWS()
 	if err != nil {
-		return "", microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
-	headers := []string{
-		color.CyanString("ID"),
-		color.CyanString("NAME"),
-		color.CyanString("AZ"),
-		color.CyanString("INSTANCE TYPE"),
-		color.CyanString("ALIKE"),
-		color.CyanString("ON-DEMAND BASE"),
-		color.CyanString("SPOT PERCENTAGE"),
-		color.CyanString("NODES MIN/MAX"),
-		color.CyanString("NODES DESIRED"),
-		color.CyanString("NODES READY"),
-		color.CyanString("SPOT INSTANCES COUNT"),
-		color.CyanString("CPUS"),
-		color.CyanString("RAM (GB)"),
+	columns := []table.Column{
+		{Name: "id", DisplayName: "ID"},
+		{Name: "name", DisplayName: "NAME"},
+		{Name: "az", DisplayName: "AZ"},
+		{Name: "instance-type", DisplayName: "INSTANCE TYPE"},
+		{Name: "alike", DisplayName: "ALIKE"},
+		{Name: "on-demand-base", DisplayName: "ON-DEMAND BASE", AlignRight: true},
+		{Name: "spot-percentage", DisplayName: "SPOT PERCENTAGE", AlignRight: true},
+		{Name: "nodes-min-max", DisplayName: "NODES MIN/MAX", AlignRight: true},
+		{Name: "nodes-desired", DisplayName: "NODES DESIRED", AlignRight: true},
+		{Name: "nodes-ready", DisplayName: "NODES READY", AlignRight: true},
+		{Name: "spot-instances-count", DisplayName: "SPOT INSTANCES COUNT", AlignRight: true},
+		{Name: "cpus", DisplayName: "CPUS", AlignRight: true},
+		{Name: "ram", DisplayName: "RAM (GB)", AlignRight: true},
 	}
+	columns = append(columns, volumeSizeColumns...)
 
-	table := make([]string, 0, len(nps)+1)
-	table = append(table, strings.Join(headers, "|"))
+	rows := make([][]string, 0, len(nps))
 
 	for _, np := range nps {
 		it, err := awsInfo.GetInstanceTypeDetails(np.NodeSpec.Aws.InstanceType)
 		if nodespec.IsInstanceTypeNotFoundErr(err) {
 			// We deliberately ignore "instance type not found", but respect all other errors.
 		} else if err != nil {
-			return "", microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
 
 		var sumCPUs string
@@ -300,7 +437,7 @@ func getOutputAWS(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
 			scalingMin = *np.Scaling.Min
 		}
 
-		table = append(table, strings.Join([]string{
+		row := []string{
 			np.ID,
 			np.Name,
 			formatting.AvailabilityZonesList(np.AvailabilityZones),
@@ -314,57 +451,45 @@ func getOutputAWS(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
 			strconv.FormatInt(np.Status.SpotInstances, 10),
 			sumCPUs,
 			sumMemory,
-		}, "|"))
-	}
-
-	colConfig := columnize.DefaultConfig()
-	colConfig.ColumnSpec = []*columnize.ColumnSpecification{
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-	}
-
-	return columnize.Format(table, colConfig), nil
+		}
+		rows = append(rows, append(row, getVolumeSizes(np)...))
+	}
+
+	t := table.New()
+	t.SetColumns(columns)
+	t.SetRows(rows)
+
+	return &t, nil
 }
 
-func getOutputAzure(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
+func getTableAzure(nps []*models.V5GetNodePoolsResponseItems) (*table.Table, error) {
 	azureInfo, err := nodespec.NewAzureProvider()
 	if err != nil {
-		return "", microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
-	headers := []string{
-		color.CyanString("ID"),
-		color.CyanString("NAME"),
-		color.CyanString("AZ"),
-		color.CyanString("VM SIZE"),
-		color.CyanString("NODES MIN/MAX"),
-		color.CyanString("NODES DESIRED"),
-		color.CyanString("NODES READY"),
-		color.CyanString("SPOT INSTANCES"),
-		color.CyanString("CPUS"),
-		color.CyanString("RAM (GB)"),
+	columns := []table.Column{
+		{Name: "id", DisplayName: "ID"},
+		{Name: "name", DisplayName: "NAME"},
+		{Name: "az", DisplayName: "AZ"},
+		{Name: "vm-size", DisplayName: "VM SIZE"},
+		{Name: "nodes-min-max", DisplayName: "NODES MIN/MAX", AlignRight: true},
+		{Name: "nodes-desired", DisplayName: "NODES DESIRED", AlignRight: true},
+		{Name: "nodes-ready", DisplayName: "NODES READY", AlignRight: true},
+		{Name: "spot-instances", DisplayName: "SPOT INSTANCES", AlignRight: true},
+		{Name: "cpus", DisplayName: "CPUS"},
+		{Name: "ram", DisplayName: "RAM (GB)"},
 	}
+	columns = append(columns, volumeSizeColumns...)
 
-	table := make([]string, 0, len(nps)+1)
-	table = append(table, strings.Join(headers, "|"))
+	rows := make([][]string, 0, len(nps))
 
 	for _, np := range nps {
 		vmSize, err := azureInfo.GetVMSizeDetails(np.NodeSpec.Azure.VMSize)
 		if nodespec.IsVMSizeNotFoundErr(err) {
 			// We deliberately ignore "vm size not found", but respect all other errors.
 		} else if err != nil {
-			return "", microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
 
 		var sumCPUs string
@@ -410,7 +535,7 @@ func getOutputAzure(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
 			}
 		}
 
-		table = append(table, strings.Join([]string{
+		row := []string{
 			np.ID,
 			np.Name,
 			formatting.AvailabilityZonesList(np.AvailabilityZones),
@@ -421,22 +546,15 @@ func getOutputAzure(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
 			spotInstances,
 			sumCPUs,
 			sumMemory,
-		}, "|"))
+		}
+		rows = append(rows, append(row, getVolumeSizes(np)...))
 	}
 
-	colConfig := columnize.DefaultConfig()
-	colConfig.ColumnSpec = []*columnize.ColumnSpecification{
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignLeft},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-		&columnize.ColumnSpecification{Alignment: columnize.AlignRight},
-	}
+	t := table.New()
+	t.SetColumns(columns)
+	t.SetRows(rows)
 
-	return columnize.Format(table, colConfig), nil
+	return &t, nil
 }
 
 func handleError(err error) {
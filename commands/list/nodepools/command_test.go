@@ -17,6 +17,8 @@ func Test_ListNodePools(t *testing.T) {
 	testCases := []struct {
 		npResponse   string
 		outputFormat string
+		sortBy       string
+		filters      []string
 		output       string
 	}{
 		{
@@ -31,6 +33,17 @@ func Test_ListNodePools(t *testing.T) {
 a6bf4  New node pool           C      m5.2xlarge     false               0              100            3/3              0            0                     0     0       0.0
 a7rc4  Batch number crunching  D      p3.8xlarge     false               0              100            2/5              4            4                     0   128     976.0`,
 		},
+		{
+			npResponse: `[
+                {"id": "a7rc4", "name": "Batch number crunching", "availability_zones": ["eu-west-1d"], "scaling": {"min": 2, "max": 5}, "node_spec": {"aws": {"instance_type": "p3.8xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 4, "nodes_ready": 4}},
+                {"id": "6feel", "name": "Application servers", "availability_zones": ["eu-west-1a", "eu-west-1b", "eu-west-1c"], "scaling": {"min": 3, "max": 15}, "node_spec": {"aws": {"instance_type": "p3.2xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 10, "nodes_ready": 9}},
+                {"id": "a6bf4", "name": "New node pool", "availability_zones": ["eu-west-1c"], "scaling": {"min": 3, "max": 3}, "node_spec": {"aws": {"instance_type": "m5.2xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 0, "nodes_ready": 0}}
+            ]`,
+			outputFormat: "jsonpath={.items[*].id}",
+			sortBy:       "-nodes-ready",
+			filters:      []string{"instance-type!=m5.2xlarge"},
+			output:       "6feel a7rc4",
+		},
 		{
 			npResponse: `[
                 {"id": "a6bf4", "name": "New node pool", "availability_zones": ["eu-west-1c"], "scaling": {"min": 3, "max": 3}, "node_spec": {"aws": {"instance_type": "m5.2xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 0, "nodes_ready": 0}}
@@ -70,6 +83,52 @@ a6bf4  New node pool  C   m5.2xlarge     false               0              100
     }
   }
 ]`,
+		},
+		{
+			npResponse: `[
+                {"id": "a6bf4", "name": "New node pool", "availability_zones": ["eu-west-1c"], "scaling": {"min": 3, "max": 3}, "node_spec": {"aws": {"instance_type": "m5.2xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 0, "nodes_ready": 0}}
+            ]`,
+			outputFormat: "wide",
+			output: `ID     NAME           AZ  INSTANCE TYPE  ALIKE  ON-DEMAND BASE  SPOT PERCENTAGE  NODES MIN/MAX  NODES DESIRED  NODES READY  SPOT INSTANCES COUNT  CPUS  RAM (GB)  DOCKER VOLUME (GB)  KUBELET VOLUME (GB)
+a6bf4  New node pool  C   m5.2xlarge     false               0              100            3/3              0            0                     0     0       0.0                 100                  100`,
+		},
+		{
+			npResponse: `[
+                {"id": "a6bf4", "name": "New node pool", "availability_zones": ["eu-west-1c"], "scaling": {"min": 3, "max": 3}, "node_spec": {"aws": {"instance_type": "m5.2xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 0, "nodes_ready": 0}}
+            ]`,
+			outputFormat: "csv",
+			output: `ID,NAME,AZ,INSTANCE TYPE,ALIKE,ON-DEMAND BASE,SPOT PERCENTAGE,NODES MIN/MAX,NODES DESIRED,NODES READY,SPOT INSTANCES COUNT,CPUS,RAM (GB),DOCKER VOLUME (GB),KUBELET VOLUME (GB)
+a6bf4,New node pool,C,m5.2xlarge,false,0,100,3/3,0,0,0,0,0.0,100,100`,
+		},
+		{
+			npResponse: `[
+                {"id": "a6bf4", "name": "New node pool", "availability_zones": ["eu-west-1c"], "scaling": {"min": 3, "max": 3}, "node_spec": {"aws": {"instance_type": "m5.2xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 0, "nodes_ready": 0}}
+            ]`,
+			outputFormat: "custom-columns=ID:.id,TYPE:.node_spec.aws.instance_type,MAX:.scaling.max",
+			output: `ID      TYPE         MAX
+a6bf4   m5.2xlarge   3`,
+		},
+		{
+			npResponse: `[
+                {"id": "a6bf4", "name": "New node pool", "availability_zones": ["eu-west-1c"], "scaling": {"min": 3, "max": 3}, "node_spec": {"aws": {"instance_type": "m5.2xlarge", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}, "volume_sizes_gb": {"docker": 100, "kubelet": 100}}, "status": {"nodes": 0, "nodes_ready": 0}}
+            ]`,
+			outputFormat: "yaml",
+			output: `- availability_zones:
+  - eu-west-1c
+  id: a6bf4
+  name: New node pool
+  node_spec:
+    aws:
+      instance_distribution: {}
+      instance_type: m5.2xlarge
+    volume_sizes_gb:
+      docker: 100
+      kubelet: 100
+  scaling:
+    max: 3
+    min: 3
+  status:
+    instance_types: null`,
 		},
 		{
 			npResponse: `[
@@ -219,6 +278,8 @@ np002-3  np002-3  2    Standard_D4s_v3           3/10              0
 				apiEndpoint:     mockServer.URL,
 				authToken:       "my-token",
 				outputFormat:    tc.outputFormat,
+				sortBy:          tc.sortBy,
+				filters:         tc.filters,
 			}
 
 			err := verifyPreconditions(args, []string{args.clusterNameOrID})
@@ -231,7 +292,7 @@ np002-3  np002-3  2    Standard_D4s_v3           3/10              0
 				t.Errorf("Case %d: %s", i, err)
 			}
 
-			output, err := getOutput(results, args.outputFormat)
+			output, err := getOutput(results, args)
 			if err != nil {
 				t.Errorf("Case %d: %s", i, err)
 			}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/cobra"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/table"
 )
 
 var (
@@ -23,9 +26,18 @@ var (
 		Use:     "organizations",
 		Aliases: []string{"orgs", "organisations"},
 		Short:   "List organizations",
-		Long:    `Prints a list of the organizations you are a member of`,
-		PreRun:  printValidation,
-		Run:     printResult,
+		Long: `Prints a list of the organizations you are a member of
+
+With --output json or --output yaml, each organization is printed with the
+field id.
+
+Examples:
+
+  gsctl list organizations
+  gsctl list organizations --output json
+`,
+		PreRun: printValidation,
+		Run:    printResult,
 	}
 
 	arguments Arguments
@@ -35,10 +47,24 @@ const (
 	listOrgsActivityName = "list-organizations"
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Use '%s=<HEADER>:<expression>,...' to choose the columns, or '%s' or '%s' for comma or tab separated values. Defaults to human-friendly table output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate, table.OutputFormatCustomColumns, table.OutputFormatCSV, table.OutputFormatTSV))
+	Command.Flags().StringVarP(&flags.Sort, "sort", "s", "", "Sort by the organization name. Use '-organization' to sort in descending order.")
+	Command.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Only list organizations meeting a condition, like 'organization!=giantswarm'. Supported operators are =, !=, >, >=, < and <=. Can be specified multiple times.")
+}
+
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
+	filters           []string
+	outputFormat      string
 	scheme            string
+	sortBy            string
 	userProvidedToken string
 }
 
@@ -51,7 +77,10 @@ func collectArguments() Arguments {
 	return Arguments{
 		apiEndpoint:       endpoint,
 		authToken:         token,
+		filters:           flags.Filter,
+		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
+		sortBy:            flags.Sort,
 		userProvidedToken: flags.Token,
 	}
 }
@@ -74,6 +103,12 @@ func verifyListOrgsPreconditions(args Arguments) error {
 	if config.Config.Token == "" && args.authToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
+	if args.outputFormat != "" && !formatting.IsStructured(args.outputFormat) && !table.IsTableOutput(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	}
+	if _, _, err := table.ParseSortAndFilter(args.sortBy, args.filters); err != nil {
+		return microerror.Mask(err)
+	}
 	return nil
 }
 
@@ -96,14 +131,14 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 		} else {
 			fmt.Println(color.RedString("Error: %s", err.Error()))
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Print(output)
 }
 
 // orgsTable fetches the organizations the user is a member of
-// and returns a table in string form.
+// and returns them in the output format given via the arguments.
 func orgsTable(args Arguments) (string, error) {
 	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
 
@@ -126,20 +161,64 @@ func orgsTable(args Arguments) (string, error) {
 		return "", microerror.Mask(err)
 	}
 
-	var output string
-	if len(response.Payload) == 0 {
-		output = color.YellowString("No organizations available\n")
-	} else {
-		// sort orgs by Id
-		sort.Slice(response.Payload[:], func(i, j int) bool {
-			return response.Payload[i].ID < response.Payload[j].ID
-		})
+	if len(response.Payload) == 0 && !formatting.IsStructured(args.outputFormat) && !table.IsDelimited(args.outputFormat) {
+		return color.YellowString("No organizations available\n"), nil
+	}
+
+	// sort orgs by Id
+	sort.Slice(response.Payload[:], func(i, j int) bool {
+		return response.Payload[i].ID < response.Payload[j].ID
+	})
+
+	t := table.New()
+	t.SetColumns([]table.Column{
+		{Name: "organization", DisplayName: "ORGANIZATION"},
+	})
+
+	rows := make([][]string, 0, len(response.Payload))
+	for _, org := range response.Payload {
+		rows = append(rows, []string{org.ID})
+	}
+	t.SetRows(rows)
 
-		output = color.CyanString("ORGANIZATION") + "\n"
-		for _, org := range response.Payload {
-			output = output + org.ID + "\n"
+	keys, filters, err := table.ParseSortAndFilter(args.sortBy, args.filters)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	indexes, err := t.SortAndFilter(keys, filters)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if formatting.IsStructured(args.outputFormat) || table.IsCustomColumns(args.outputFormat) {
+		// Render an empty list instead of null.
+		items := make([]*models.V4OrganizationListItem, 0, len(indexes))
+		for _, i := range indexes {
+			items = append(items, response.Payload[i])
+		}
+
+		if table.IsCustomColumns(args.outputFormat) {
+			output, err := table.CustomColumns(args.outputFormat, items)
+			if err != nil {
+				return "", microerror.Mask(err)
+			}
+
+			return output + "\n", nil
+		}
+
+		outputBytes, err := formatting.Marshal(args.outputFormat, items)
+		if err != nil {
+			return "", microerror.Mask(err)
 		}
+
+		return string(outputBytes) + "\n", nil
+	}
+
+	output, err := t.Format(args.outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
 	}
 
-	return output, nil
+	return output + "\n", nil
 }
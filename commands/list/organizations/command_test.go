@@ -52,3 +52,45 @@ func Test_ListOrganizationsSuccess(t *testing.T) {
 
 	}
 }
+
+// Test_ListOrganizationsSortAndFilter tests sorting and filtering organizations.
+func Test_ListOrganizationsSortAndFilter(t *testing.T) {
+	orgsMockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "acme"}, {"id": "giantswarm"}, {"id": "foo"}]`))
+	}))
+	defer orgsMockServer.Close()
+
+	testCases := []struct {
+		sortBy       string
+		filters      []string
+		outputFormat string
+		expected     string
+	}{
+		{"", nil, "", "ORGANIZATION\nacme\nfoo\ngiantswarm\n"},
+		{"-organization", []string{"organization!=giantswarm"}, "", "ORGANIZATION\nfoo\nacme\n"},
+		{"-organization", []string{"organization!=giantswarm"}, "json", "[\n  {\n    \"id\": \"foo\"\n  },\n  {\n    \"id\": \"acme\"\n  }\n]\n"},
+		{"", nil, "yaml", "- id: acme\n- id: foo\n- id: giantswarm\n"},
+		{"", []string{"organization=none"}, "json", "[]\n"},
+		{"", nil, "csv", "ORGANIZATION\nacme\nfoo\ngiantswarm\n"},
+	}
+
+	for i, tc := range testCases {
+		args := Arguments{
+			authToken:    "some-token",
+			apiEndpoint:  orgsMockServer.URL,
+			sortBy:       tc.sortBy,
+			filters:      tc.filters,
+			outputFormat: tc.outputFormat,
+		}
+
+		output, err := orgsTable(args)
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %s", i, err)
+		}
+		if output != tc.expected {
+			t.Errorf("Case %d - Expected %q, got %q", i, tc.expected, output)
+		}
+	}
+}
@@ -2,7 +2,6 @@
 package releases
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -10,7 +9,6 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/fatih/color"
-	"github.com/giantswarm/columnize"
 	"github.com/giantswarm/gscliauth/config"
 	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/gsctl/pkg/releaseinfo"
@@ -22,6 +20,8 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/sortable"
+	"github.com/giantswarm/gsctl/pkg/table"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -61,6 +61,9 @@ Output
 - COREDNS: The CodeDNS version provided.
 
 - CALICO: The Project Calico version provided.
+
+With --output wide, all other components of a release are listed in addition,
+in the column OTHER COMPONENTS.
 `,
 		PreRun: printValidation,
 		Run:    printResult,
@@ -76,15 +79,19 @@ func init() {
 func initFlags() {
 	Command.ResetFlags()
 
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Use '%s' to list all components in the table, or '%s=<HEADER>:<expression>,...' to choose the columns. Use '%s' or '%s' for comma or tab separated values with all columns. Defaults to human-friendly table output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate, table.OutputFormatWide, table.OutputFormatCustomColumns, table.OutputFormatCSV, table.OutputFormatTSV))
+	Command.Flags().StringVarP(&flags.Sort, "sort", "s", "", "Sort by one or more comma separated fields, like 'status,-version'. Prefix a field with '-' to sort in descending order. Defaults to sorting by version.")
+	Command.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Only list releases meeting a condition, like 'status=active' or 'version>=12.0.0'. Supported operators are =, !=, >, >=, < and <=. Can be specified multiple times.")
 }
 
 // Arguments are the actual arguments used to call the
 // listReleases() function.
 type Arguments struct {
 	apiEndpoint       string
+	filters           []string
 	outputFormat      string
 	scheme            string
+	sortBy            string
 	token             string
 	userProvidedToken string
 }
@@ -98,9 +105,11 @@ func collectArguments() Arguments {
 
 	return Arguments{
 		apiEndpoint:       endpoint,
+		filters:           flags.Filter,
 		outputFormat:      flags.OutputFormat,
 		token:             token,
 		scheme:            scheme,
+		sortBy:            flags.Sort,
 		userProvidedToken: flags.Token,
 	}
 }
@@ -108,7 +117,9 @@ func collectArguments() Arguments {
 // printValidation does our pre-checks and shows errors, in case
 // something is missing.
 func printValidation(cmd *cobra.Command, extraArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "list releases", "get releases", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-releases/"))
+	// The notice goes to stderr, so that it does not end up in output
+	// processed by other tools, like CSV or JSON.
+	fmt.Fprint(os.Stderr, util.GetDeprecatedNotice(config.Config.Provider, "list releases", "get releases", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-releases/"))
 
 	arguments = collectArguments()
 	err := listReleasesPreconditions(&arguments)
@@ -121,7 +132,7 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 	errors.HandleCommonErrors(err)
 
 	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 // listReleasesPreconditions validates our pre-conditions and returns an error in
@@ -133,9 +144,12 @@ func listReleasesPreconditions(args *Arguments) error {
 	if config.Config.Token == "" && args.token == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
+	if !formatting.IsStructured(args.outputFormat) && !table.IsTableOutput(args.outputFormat) {
 		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is unknown", args.outputFormat))
 	}
+	if _, _, err := table.ParseSortAndFilter(args.sortBy, args.filters); err != nil {
+		return microerror.Mask(err)
+	}
 
 	return nil
 }
@@ -146,13 +160,13 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 	clientWrapper, err := client.NewWithConfig(arguments.apiEndpoint, arguments.userProvidedToken)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	releases, err := listReleases(clientWrapper, arguments)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	releaseInfoConfig := releaseinfo.Config{
@@ -161,15 +175,32 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 	releaseInfo, err := releaseinfo.New(releaseInfoConfig)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	releases, err = sortAndFilter(releases, releaseInfo, arguments)
+	if err != nil {
+		handleError(microerror.Mask(err))
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if table.IsCustomColumns(arguments.outputFormat) {
+		output, err := table.CustomColumns(arguments.outputFormat, releases)
+		if err != nil {
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+
+		fmt.Println(output)
+		return
 	}
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
-		outputBytes, err := json.MarshalIndent(releases, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	if formatting.IsStructured(arguments.outputFormat) {
+		outputBytes, err := formatting.Marshal(arguments.outputFormat, releases)
 		if err != nil {
-			fmt.Println(color.RedString("Error while encoding JSON"))
+			fmt.Println(color.RedString("Error while encoding the output"))
 			fmt.Printf("Details: %s", err.Error())
-			os.Exit(1)
+			os.Exit(errors.ExitCode(err))
 		}
 
 		fmt.Println(string(outputBytes))
@@ -177,22 +208,55 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 	}
 
 	// success
-	if len(releases) == 0 {
+	if len(releases) == 0 && len(arguments.filters) > 0 && !table.IsDelimited(arguments.outputFormat) {
+		fmt.Println(color.YellowString("No releases matching the filters."))
+		return
+	}
+	if len(releases) == 0 && !table.IsDelimited(arguments.outputFormat) {
 		fmt.Println(color.RedString("No releases available."))
 		fmt.Println("We cannot find any releases. Please contact the Giant Swarm support team to find out if there is a problem to be solved.")
 		return
 	}
 
-	// table headers
-	output := []string{strings.Join([]string{
-		color.CyanString("VERSION"),
-		color.CyanString("STATUS"),
-		color.CyanString("CREATED"),
-		color.CyanString("KUBERNETES"),
-		color.CyanString("CONTAINERLINUX"),
-		color.CyanString("COREDNS"),
-		color.CyanString("CALICO"),
-	}, "|")}
+	output, err := getTable(releases, releaseInfo, arguments.outputFormat)
+	if err != nil {
+		handleError(microerror.Mask(err))
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(output)
+}
+
+// getTable renders the releases as a table in the given output format. In
+// wide output, components without a dedicated column are listed in addition.
+func getTable(releases []*models.V4ReleaseListItem, releaseInfo *releaseinfo.ReleaseInfo, outputFormat string) (string, error) {
+	t := createTable(releases, releaseInfo)
+	t.SetWide(table.IsWide(outputFormat))
+
+	output, err := t.Format(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return output, nil
+}
+
+// createTable creates the table of releases.
+func createTable(releases []*models.V4ReleaseListItem, releaseInfo *releaseinfo.ReleaseInfo) *table.Table {
+	t := table.New()
+	t.SetGlue("  ")
+	t.SetColumns([]table.Column{
+		{Name: "version", DisplayName: "VERSION", Sortable: sortable.Sortable{SortType: sortable.Semver}},
+		{Name: "status", DisplayName: "STATUS"},
+		{Name: "created", DisplayName: "CREATED", Sortable: sortable.Sortable{SortType: sortable.Date}},
+		{Name: "kubernetes", DisplayName: "KUBERNETES"},
+		{Name: "containerlinux", DisplayName: "CONTAINERLINUX"},
+		{Name: "coredns", DisplayName: "COREDNS"},
+		{Name: "calico", DisplayName: "CALICO"},
+		{Name: "other-components", DisplayName: "OTHER COMPONENTS", Wide: true},
+	})
+
+	rows := make([][]string, 0, len(releases))
 
 	for _, release := range releases {
 		created := util.ShortDate(util.ParseDate(*release.Timestamp))
@@ -200,6 +264,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 		containerLinuxVersion := "n/a"
 		coreDNSVersion := "n/a"
 		calicoVersion := "n/a"
+		var otherComponents []string
 
 		status := "inactive"
 		if release.Active {
@@ -207,44 +272,65 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 		}
 
 		for _, component := range release.Components {
-			if *component.Name == "kubernetes" {
+			switch *component.Name {
+			case "kubernetes":
 				kubernetesVersion = formatKubernetesVersion(releaseInfo, *release.Version)
-			}
-			if *component.Name == "containerlinux" {
+			case "containerlinux":
 				containerLinuxVersion = *component.Version
-			}
-			if *component.Name == "coredns" {
+			case "coredns":
 				coreDNSVersion = *component.Version
-			}
-			if *component.Name == "calico" {
+			case "calico":
 				calicoVersion = *component.Version
+			default:
+				otherComponents = append(otherComponents, *component.Name+" "+*component.Version)
 			}
 		}
+		sort.Strings(otherComponents)
+
+		row := []string{
+			*release.Version,
+			status,
+			created,
+			kubernetesVersion,
+			containerLinuxVersion,
+			coreDNSVersion,
+			calicoVersion,
+			strings.Join(otherComponents, ", "),
+		}
 
 		if status == "active" {
-			output = append(output, strings.Join([]string{
-				color.YellowString(*release.Version),
-				color.YellowString(status),
-				color.YellowString(created),
-				color.YellowString(kubernetesVersion),
-				color.YellowString(containerLinuxVersion),
-				color.YellowString(coreDNSVersion),
-				color.YellowString(calicoVersion),
-			}, "|"))
-		} else {
-			output = append(output, strings.Join([]string{
-				*release.Version,
-				status,
-				created,
-				kubernetesVersion,
-				containerLinuxVersion,
-				coreDNSVersion,
-				calicoVersion,
-			}, "|"))
+			for i := range row {
+				row[i] = color.YellowString(row[i])
+			}
 		}
+
+		rows = append(rows, row)
+	}
+	t.SetRows(rows)
+
+	return &t
+}
+
+// sortAndFilter applies the filters and sort keys given via flags to the
+// releases, based on the values shown in the table.
+func sortAndFilter(releases []*models.V4ReleaseListItem, releaseInfo *releaseinfo.ReleaseInfo, args Arguments) ([]*models.V4ReleaseListItem, error) {
+	keys, filters, err := table.ParseSortAndFilter(args.sortBy, args.filters)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	t := createTable(releases, releaseInfo)
+	indexes, err := t.SortAndFilter(keys, filters)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	result := make([]*models.V4ReleaseListItem, 0, len(indexes))
+	for _, i := range indexes {
+		result = append(result, releases[i])
 	}
 
-	fmt.Println(columnize.SimpleFormat(output))
+	return result, nil
 }
 
 // listReleases fetches releases and returns them as a structured result.
@@ -3,8 +3,11 @@ package releases
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/gsctl/client"
 	"github.com/spf13/afero"
 
@@ -224,3 +227,99 @@ func Test_ListReleases_Nonempty(t *testing.T) {
 		t.Error("Releases returned were not in the expected order.")
 	}
 }
+
+// Test_getTable tests the additional column in wide table output,
+// and CSV output.
+func Test_getTable(t *testing.T) {
+	timestamp := "2017-10-15T12:00:00Z"
+	version := "0.1.0"
+	names := []string{"vault", "calico", "etcd"}
+	versions := []string{"0.7.2", "2.6.1", "3.2.2"}
+
+	release := &models.V4ReleaseListItem{
+		Timestamp: &timestamp,
+		Version:   &version,
+	}
+	for i := range names {
+		release.Components = append(release.Components, &models.V4ReleaseListItemComponentsItems{
+			Name:    &names[i],
+			Version: &versions[i],
+		})
+	}
+
+	testCases := []struct {
+		outputFormat string
+		expected     string
+	}{
+		{
+			"wide",
+			`VERSION  STATUS    CREATED                 KUBERNETES  CONTAINERLINUX  COREDNS  CALICO  OTHER COMPONENTS
+0.1.0    inactive  2017 Oct 15, 12:00 UTC  n/a         n/a             n/a      2.6.1   etcd 3.2.2, vault 0.7.2`,
+		},
+		{
+			"csv",
+			`VERSION,STATUS,CREATED,KUBERNETES,CONTAINERLINUX,COREDNS,CALICO,OTHER COMPONENTS
+0.1.0,inactive,"2017 Oct 15, 12:00 UTC",n/a,n/a,n/a,2.6.1,"etcd 3.2.2, vault 0.7.2"`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			output, err := getTable([]*models.V4ReleaseListItem{release}, nil, tc.outputFormat)
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error: %s", i, err)
+			}
+			if output != tc.expected {
+				t.Errorf("Case %d - Expected\n%s\ngot\n%s", i, tc.expected, output)
+			}
+		})
+	}
+}
+
+// Test_sortAndFilter tests sorting releases by several keys, and filtering
+// them by version.
+func Test_sortAndFilter(t *testing.T) {
+	newRelease := func(version, timestamp string, active bool) *models.V4ReleaseListItem {
+		return &models.V4ReleaseListItem{
+			Version:   &version,
+			Timestamp: &timestamp,
+			Active:    active,
+		}
+	}
+
+	releases := []*models.V4ReleaseListItem{
+		newRelease("9.0.0", "2019-10-15T12:00:00Z", false),
+		newRelease("11.2.0", "2020-03-01T12:00:00Z", true),
+		newRelease("12.0.0", "2020-06-01T12:00:00Z", true),
+		newRelease("12.1.0", "2020-07-01T12:00:00Z", false),
+	}
+
+	testCases := []struct {
+		sortBy   string
+		filters  []string
+		expected []string
+	}{
+		{"status,-version", nil, []string{"12.0.0", "11.2.0", "12.1.0", "9.0.0"}},
+		{"-created", []string{"version>=11.2.0", "status=active"}, []string{"12.0.0", "11.2.0"}},
+		{"", []string{"created<2020-01-01"}, []string{"9.0.0"}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			args := Arguments{sortBy: tc.sortBy, filters: tc.filters}
+
+			result, err := sortAndFilter(releases, nil, args)
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error: %s", i, err)
+			}
+
+			var versions []string
+			for _, r := range result {
+				versions = append(versions, *r.Version)
+			}
+			if strings.Join(versions, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Case %d - Expected %v, got %v", i, tc.expected, versions)
+			}
+		})
+	}
+}
@@ -134,7 +134,7 @@ func loginPreRunOutput(cmd *cobra.Command, positionalArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 // verifyLoginPreconditions does the pre-checks and returns an error in case something's wrong.
@@ -229,7 +229,7 @@ func loginRunOutput(cmd *cobra.Command, positionalArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	if result.loggedOutBefore && arguments.verbose {
@@ -271,6 +271,11 @@ func getInstallationInfo(apiEndpoint string, scheme string, accessToken string)
 		Timeout:          10 * time.Second,
 		UserAgent:        config.UserAgent(),
 		AuthHeaderGetter: authHeaderGetter,
+		RecordDir:        client.RecordDir,
+		ReplayDir:        client.ReplayDir,
+	}
+	if client.Trace {
+		clientConfig.TraceLog = os.Stderr
 	}
 
 	clientWrapper, err := client.New(clientConfig)
@@ -15,6 +15,7 @@ import (
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
 )
 
 const (
@@ -60,7 +61,7 @@ func collectArguments() Arguments {
 func printValidation(cmd *cobra.Command, args []string) {
 	if config.Config.Token == "" && flags.Token == "" {
 		fmt.Println("You weren't logged in here, but better be safe than sorry.")
-		os.Exit(1)
+		os.Exit(exitcode.Auth)
 	}
 }
 
@@ -84,7 +85,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 
 		// handle non-common errors
 		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Printf("You have logged out from endpoint %s.\n", color.CyanString(logoutArgs.apiEndpoint))
@@ -58,7 +58,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 
 	// handle non-common errors
 	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func verifyPreconditions(args Arguments, cmdLineArgs []string) error {
@@ -81,7 +81,7 @@ func runCommand(cmd *cobra.Command, cmdLineArgs []string) {
 
 		fmt.Println(color.RedString("Could not reach API"))
 		fmt.Println(err.Error())
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(color.GreenString("API connection is fine"))
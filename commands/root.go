@@ -5,15 +5,24 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/giantswarm/gscliauth/config"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/apply"
 	"github.com/giantswarm/gsctl/commands/create"
 	deletecmd "github.com/giantswarm/gsctl/commands/delete"
+	"github.com/giantswarm/gsctl/commands/devapiserver"
+	"github.com/giantswarm/gsctl/commands/diff"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/commands/export"
 	"github.com/giantswarm/gsctl/commands/info"
+	"github.com/giantswarm/gsctl/commands/install"
 	"github.com/giantswarm/gsctl/commands/list"
 	"github.com/giantswarm/gsctl/commands/login"
 	"github.com/giantswarm/gsctl/commands/logout"
@@ -24,7 +33,10 @@ import (
 	"github.com/giantswarm/gsctl/commands/update"
 	"github.com/giantswarm/gsctl/commands/upgrade"
 	"github.com/giantswarm/gsctl/commands/version"
+	"github.com/giantswarm/gsctl/commands/wait"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/colormode"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -39,6 +51,14 @@ const (
         fi
     fi
 	`
+
+	// Environment variables setting the default of the --timeout
+	// and --retries flags.
+	envTimeout = "GSCTL_TIMEOUT"
+	envRetries = "GSCTL_RETRIES"
+
+	// envTrace enables the --trace flag if set to "1" or "true".
+	envTrace = "GSCTL_TRACE"
 )
 
 // RootCommand is the main command of the CLI
@@ -55,6 +75,7 @@ func init() {
 	// Use the auth token defined as an environmental variable,
 	// if it exists.
 	tokenFromEnv := os.Getenv("GSCTL_AUTH_TOKEN")
+	traceFromEnv, _ := strconv.ParseBool(os.Getenv(envTrace))
 
 	defaultConfigDir := config.DefaultConfigDirPath
 	configDirFromEnv := os.Getenv("GSCTL_CONFIG_DIR")
@@ -65,14 +86,28 @@ func init() {
 	RootCommand.PersistentFlags().StringVarP(&flags.Token, "auth-token", "", tokenFromEnv, "Authorization token to use")
 	RootCommand.PersistentFlags().StringVarP(&flags.ConfigDirPath, "config-dir", "", defaultConfigDir, "Configuration directory path to use")
 	RootCommand.PersistentFlags().BoolVarP(&flags.Verbose, "verbose", "v", false, "Print more information")
+	RootCommand.PersistentFlags().BoolVarP(&flags.Trace, "trace", "", traceFromEnv, fmt.Sprintf("Print all API requests and responses to stderr, with secrets redacted. Can also be enabled via the %s environment variable.", envTrace))
+	RootCommand.PersistentFlags().StringVarP(&flags.RecordDir, "record", "", "", "Store all API requests and responses as fixture files in the given directory, with secrets redacted.")
+	RootCommand.PersistentFlags().StringVarP(&flags.ReplayDir, "replay", "", "", "Serve API responses from fixture files created via --record in the given directory, instead of sending requests.")
+	RootCommand.PersistentFlags().StringVarP(&flags.DryRun, "dry-run", "", "", fmt.Sprintf("Print the body of requests which would modify data as '%s' or '%s', instead of sending them.", formatting.OutputFormatJSON, formatting.OutputFormatYAML))
+	RootCommand.PersistentFlags().Lookup("dry-run").NoOptDefVal = formatting.OutputFormatJSON
+	RootCommand.PersistentFlags().BoolVarP(&flags.NoCache, "no-cache", "", false, "Always request the installation info and releases from the API, instead of using cached responses.")
+	RootCommand.PersistentFlags().BoolVarP(&flags.NoColor, "no-color", "", false, "Disable colored output. Colors are also disabled if the NO_COLOR environment variable is set, or if the output is not a terminal.")
+	RootCommand.PersistentFlags().DurationVarP(&flags.RequestTimeout, "timeout", "", client.RequestTimeout, fmt.Sprintf("Maximum time to wait for an API request, including retries, e. g. '1m'. Can also be set via the %s environment variable.", envTimeout))
+	RootCommand.PersistentFlags().IntVarP(&flags.Retries, "retries", "", 0, fmt.Sprintf("Number of times to retry API requests failing for a temporary reason, with exponential backoff. Requests modifying data are only retried if the API signals that it is safe. Can also be set via the %s environment variable.", envRetries))
 	RootCommand.PersistentFlags().BoolVarP(&flags.SilenceHTTPEndpointWarning, "silence-http-endpoint-warning", "", false, "Dont't print warnings when deliberately using an insecure HTTP endpoint")
 	RootCommand.Flags().Bool("version", false, version.Command.Short)
 
 	// add subcommands
+	RootCommand.AddCommand(apply.Command)
 	RootCommand.AddCommand(CompletionCommand)
 	RootCommand.AddCommand(create.Command)
 	RootCommand.AddCommand(deletecmd.Command)
+	RootCommand.AddCommand(devapiserver.Command)
+	RootCommand.AddCommand(diff.Command)
+	RootCommand.AddCommand(export.Command)
 	RootCommand.AddCommand(info.Command)
+	RootCommand.AddCommand(install.Command)
 	RootCommand.AddCommand(list.Command)
 	RootCommand.AddCommand(login.Command)
 	RootCommand.AddCommand(logout.Command)
@@ -83,6 +118,7 @@ func init() {
 	RootCommand.AddCommand(update.Command)
 	RootCommand.AddCommand(upgrade.Command)
 	RootCommand.AddCommand(version.Command)
+	RootCommand.AddCommand(wait.Command)
 
 	// Custom auto-completion
 	util.SetFlagBashCompletionFn(&util.BashCompletionFunc{
@@ -98,6 +134,31 @@ func init() {
 // initConfig calls the config.Initialize() function
 // before any command is executed (see PersistentPreRunE above).
 func initConfig(cmd *cobra.Command, args []string) error {
+	colormode.Configure(flags.NoColor)
+
+	if flags.DryRun != "" && flags.DryRun != formatting.OutputFormatJSON && flags.DryRun != formatting.OutputFormatYAML {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "dry run format '%s' is unknown, use '%s' or '%s'", flags.DryRun, formatting.OutputFormatJSON, formatting.OutputFormatYAML)
+	}
+	client.DryRunFormat = flags.DryRun
+	// Request bodies are printed to stdout, the notes about the requests
+	// not sent go to stderr.
+	client.DryRunOutput = os.Stdout
+	client.Trace = flags.Trace
+	client.Verbose = flags.Verbose
+	client.OutputFormat = flags.OutputFormat
+
+	if flags.RecordDir != "" && flags.ReplayDir != "" {
+		return microerror.Maskf(errors.ConflictingFlagsError, "the flags --record and --replay cannot be combined")
+	}
+	client.RecordDir = flags.RecordDir
+	client.ReplayDir = flags.ReplayDir
+	client.NoCache = flags.NoCache
+
+	err := initRequestSettings(cmd)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	fs := afero.NewOsFs()
 
 	var configLogger io.Writer
@@ -107,7 +168,7 @@ func initConfig(cmd *cobra.Command, args []string) error {
 		configLogger = os.Stdout
 	}
 
-	err := config.InitializeWithLogger(fs, flags.ConfigDirPath, configLogger)
+	err = config.InitializeWithLogger(fs, flags.ConfigDirPath, configLogger)
 	if err != nil {
 		if flags.Verbose {
 			fmt.Printf("Error initializing configuration: %#v\n", err)
@@ -118,6 +179,37 @@ func initConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// initRequestSettings applies the --timeout and --retries flags,
+// or the according environment variables if the flags are not given.
+func initRequestSettings(cmd *cobra.Command) error {
+	if value := os.Getenv(envTimeout); value != "" && !cmd.Flags().Changed("timeout") {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return microerror.Maskf(errors.InvalidDurationError, "%s value '%s' is not a valid duration, use e. g. '30s'", envTimeout, value)
+		}
+		flags.RequestTimeout = timeout
+	}
+	if value := os.Getenv(envRetries); value != "" && !cmd.Flags().Changed("retries") {
+		retries, err := strconv.Atoi(value)
+		if err != nil {
+			return microerror.Maskf(errors.InvalidRetriesError, "%s value '%s' is not a number", envRetries, value)
+		}
+		flags.Retries = retries
+	}
+
+	if flags.RequestTimeout <= 0 {
+		return microerror.Maskf(errors.InvalidDurationError, "the request timeout must be positive")
+	}
+	if flags.Retries < 0 {
+		return microerror.Maskf(errors.InvalidRetriesError, "the number of retries must not be negative")
+	}
+
+	client.RequestTimeout = flags.RequestTimeout
+	client.Retries = flags.Retries
+
+	return nil
+}
+
 func printResult(cmd *cobra.Command, args []string) {
 	isVersion, _ := cmd.Flags().GetBool("version")
 	if isVersion {
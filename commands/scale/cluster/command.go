@@ -244,7 +244,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 
 	if err != nil {
 		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	clientWrapper, err := client.NewWithConfig(arguments.APIEndpoint, arguments.UserProvidedToken)
@@ -293,7 +293,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 // scaleCluster is the actual function submitting the API call and handling the response.
@@ -408,7 +408,7 @@ func printResult(cmd *cobra.Command, commandLineArgs []string) {
 		errors.HandleCommonErrors(err)
 
 		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	// Actually make the scaling request to the API.
@@ -442,7 +442,7 @@ func printResult(cmd *cobra.Command, commandLineArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(color.GreenString("The cluster is being scaled"))
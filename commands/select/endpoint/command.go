@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/exitcode"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -69,7 +70,7 @@ func selectEndpointPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func verifySelectEndpointPreconditions(cmdLineArgs []string) error {
@@ -87,9 +88,10 @@ func selectEndpointRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		if config.IsEndpointNotDefinedError(err) {
 			fmt.Println(color.RedString("The endpoint given is not defined."))
 			fmt.Println("Please use 'gsctl login <email> -e <endpoint>' to add a new endpoint first.")
-			os.Exit(1)
+			os.Exit(exitcode.NotFound)
 		}
 		fmt.Println(color.RedString("Error: " + err.Error()))
+		os.Exit(errors.ExitCode(err))
 	} else {
 		fmt.Println(color.GreenString("Endpoint selected: %s", config.Config.SelectedEndpoint))
 	}
@@ -0,0 +1,274 @@
+// Package app implements the 'show app' command.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/clustercache"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/precondition"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// ShowAppCommand is the cobra command for 'gsctl show app'
+	ShowAppCommand = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>/<app-name>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Show app details",
+		Long: `Display details of an app installed in a cluster.
+
+Examples:
+
+  gsctl show app f01r4/nginx-ingress-controller
+  gsctl show app "Cluster name"/nginx-ingress-controller --output json
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "show-app"
+)
+
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowAppCommand.ResetFlags()
+	ShowAppCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AppName           string
+	AuthToken         string
+	ClusterNameOrID   string
+	OutputFormat      string
+	UserProvidedToken string
+}
+
+func collectArguments(positionalArgs []string) (Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	parts := strings.Split(positionalArgs[0], "/")
+	if len(parts) != 2 {
+		return Arguments{}, microerror.Maskf(errors.InvalidAppArgumentError, "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details.")
+	}
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AppName:           strings.TrimSpace(parts[1]),
+		AuthToken:         token,
+		ClusterNameOrID:   strings.TrimSpace(parts[0]),
+		OutputFormat:      flags.OutputFormat,
+		UserProvidedToken: flags.Token,
+	}, nil
+}
+
+func verifyPreconditions(args Arguments) error {
+	err := precondition.VerifyApp(args.APIEndpoint, args.AuthToken, args.UserProvidedToken, args.ClusterNameOrID, args.AppName)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !formatting.IsStructured(args.OutputFormat) && args.OutputFormat != formatting.OutputFormatTable {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.OutputFormat)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+	arguments, err = collectArguments(positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+		if err == nil {
+			return
+		}
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// fetchApp fetches the details of one app installed in a cluster.
+func fetchApp(args Arguments) (*models.V4GetClusterAppsResponseItems, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetIDCached(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	app, err := clientWrapper.GetApp(clusterID, args.AppName, auxParams)
+	if err != nil {
+		switch {
+		case clienterror.IsAccessForbiddenError(err):
+			return nil, microerror.Mask(errors.AccessForbiddenError)
+		case clienterror.IsNotFoundError(err):
+			return nil, microerror.Mask(errors.ClusterNotFoundError)
+		}
+
+		return nil, microerror.Mask(err)
+	}
+	if app == nil {
+		return nil, microerror.Mask(errors.AppNotFoundError)
+	}
+
+	return app, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	app, err := fetchApp(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	output, err := getOutput(app, arguments.OutputFormat)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(output)
+}
+
+func getOutput(app *models.V4GetClusterAppsResponseItems, outputFormat string) (string, error) {
+	if formatting.IsStructured(outputFormat) {
+		outputBytes, err := formatting.Marshal(outputFormat, app)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return string(outputBytes), nil
+	}
+
+	var (
+		namespace, catalog, chart, version string
+		appVersion, status, lastDeployed   = "n/a", "n/a", "n/a"
+		userConfigMap, userSecret          = "n/a", "n/a"
+	)
+
+	if app.Spec != nil {
+		namespace = app.Spec.Namespace
+		catalog = app.Spec.Catalog
+		chart = app.Spec.Name
+		version = app.Spec.Version
+
+		if app.Spec.UserConfig != nil {
+			if cm := app.Spec.UserConfig.Configmap; cm != nil && cm.Name != "" {
+				userConfigMap = cm.Namespace + "/" + cm.Name
+			}
+			if s := app.Spec.UserConfig.Secret; s != nil && s.Name != "" {
+				userSecret = s.Namespace + "/" + s.Name
+			}
+		}
+	}
+	if app.Status != nil {
+		if app.Status.AppVersion != "" {
+			appVersion = app.Status.AppVersion
+		}
+		if app.Status.Release != nil {
+			if app.Status.Release.Status != "" {
+				status = app.Status.Release.Status
+				if status == "FAILED" {
+					status = color.RedString(status)
+				}
+			}
+			if app.Status.Release.LastDeployed != "" {
+				lastDeployed = util.ShortDate(util.ParseDate(app.Status.Release.LastDeployed))
+			}
+		}
+	}
+
+	name := ""
+	if app.Metadata != nil {
+		name = app.Metadata.Name
+	}
+
+	var table []string
+	{
+		table = append(table, color.YellowString("Name:")+"|"+name)
+		table = append(table, color.YellowString("Namespace:")+"|"+namespace)
+		table = append(table, color.YellowString("Catalog:")+"|"+catalog)
+		table = append(table, color.YellowString("Chart:")+"|"+chart)
+		table = append(table, color.YellowString("Chart version:")+"|"+version)
+		table = append(table, color.YellowString("App version:")+"|"+appVersion)
+		table = append(table, color.YellowString("Status:")+"|"+status)
+		table = append(table, color.YellowString("Last deployed:")+"|"+lastDeployed)
+		table = append(table, color.YellowString("User config map:")+"|"+userConfigMap)
+		table = append(table, color.YellowString("User secret:")+"|"+userSecret)
+	}
+
+	return columnize.SimpleFormat(table), nil
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var (
+		headline string
+		subtext  string
+	)
+	{
+		switch {
+		case errors.IsInvalidAppArgument(err):
+			headline = "Invalid argument syntax"
+			subtext = "Please give the cluster name or ID, followed by /, followed by the app name."
+
+		case errors.IsAppNameMissingError(err):
+			headline = "No app name specified."
+			subtext = "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details."
+
+		case errors.IsAppNotFound(err):
+			headline = "App not found"
+			subtext = fmt.Sprintf("Could not find an app named '%s' in this cluster. Check 'gsctl list apps' to make sure.", arguments.AppName)
+
+		case errors.IsClusterNotFoundError(err):
+			headline = "Cluster not found"
+			subtext = "Check 'gsctl list clusters' to make sure the cluster exists."
+
+		default:
+			headline = err.Error()
+		}
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const appsResponse = `[
+	{
+		"metadata": {"name": "my-awesome-prometheus"},
+		"spec": {
+			"catalog": "sample-catalog",
+			"name": "prometheus-chart",
+			"namespace": "giantswarm",
+			"version": "0.2.0",
+			"user_config": {"configmap": {"name": "prometheus-user-values", "namespace": "cluster-id"}}
+		},
+		"status": {
+			"app_version": "1.0.0",
+			"release": {"last_deployed": "2019-04-08T12:34:00Z", "status": "DEPLOYED"},
+			"version": "0.2.0"
+		}
+	}
+]`
+
+func newMockServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch uri := r.URL.Path; uri {
+		case "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acme"}]`))
+
+		case "/v4/clusters/cluster-id/apps/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(appsResponse))
+
+		default:
+			t.Errorf("Unsupported route %s called in mock server", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+}
+
+// Test_ShowApp tests fetching and displaying an existing app.
+func Test_ShowApp(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:     mockServer.URL,
+		AppName:         "my-awesome-prometheus",
+		AuthToken:       "some-token",
+		ClusterNameOrID: "Name of the cluster",
+		OutputFormat:    "table",
+	}
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := fetchApp(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := getOutput(app, args.OutputFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"my-awesome-prometheus", "sample-catalog", "prometheus-chart", "DEPLOYED", "cluster-id/prometheus-user-values"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	output, err = getOutput(app, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, `"app_version": "1.0.0"`) {
+		t.Errorf("Expected JSON output to contain the app version, got:\n%s", output)
+	}
+}
+
+// Test_ShowAppNotFound tests the case where the app does not exist.
+func Test_ShowAppNotFound(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:     mockServer.URL,
+		AppName:         "non-existing",
+		AuthToken:       "some-token",
+		ClusterNameOrID: "cluster-id",
+		OutputFormat:    "table",
+	}
+
+	_, err = fetchApp(args)
+	if !errors.IsAppNotFound(err) {
+		t.Errorf("Expected AppNotFoundError, got %#v", err)
+	}
+}
+
+// Test_CollectArguments tests the parsing of the positional argument.
+func Test_CollectArguments(t *testing.T) {
+	_, err := collectArguments([]string{"cluster-id"})
+	if !errors.IsInvalidAppArgument(err) {
+		t.Errorf("Expected InvalidAppArgumentError, got %#v", err)
+	}
+
+	args, err := collectArguments([]string{"cluster-id/my-app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.ClusterNameOrID != "cluster-id" || args.AppName != "my-app" {
+		t.Errorf("Unexpected arguments %#v", args)
+	}
+}
@@ -3,6 +3,7 @@ package cluster
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strconv"
@@ -23,7 +24,11 @@ import (
 	"github.com/giantswarm/gsctl/clustercache"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/nodespec"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+	"github.com/giantswarm/gsctl/pkg/table"
+	"github.com/giantswarm/gsctl/pkg/watch"
 	"github.com/giantswarm/gsctl/util"
 	"github.com/giantswarm/gsctl/webui"
 )
@@ -40,6 +45,32 @@ Examples:
 
   gsctl show cluster c7t2o
   gsctl show cluster "Cluster name"
+  gsctl show cluster c7t2o --output json
+  gsctl show cluster c7t2o --watch
+
+With --watch, the details are updated every few seconds. Lines which have
+changed since the last update are marked with '~', new lines with '+' and
+removed lines with '-'. With --output json or --output yaml, an event with
+the fields 'type' (ADDED, MODIFIED or DELETED) and 'object' is printed
+whenever the cluster changes.
+
+With --output json or --output yaml, the details are printed with these
+fields:
+
+  id, name, create_date, owner, api_endpoint, release_version
+  kubernetes          version, end_of_life, end_of_life_date
+  labels              user-defined cluster labels
+  credential          id, aws_account_id, azure_subscription_id,
+                      azure_tenant_id (only for clusters in your own
+                      cloud provider account)
+  masters             availability_zones, count, high_availability, ready
+  workers             instance_type, vm_size, availability_zones,
+                      scaling_min, scaling_max, desired, running, cpus,
+                      memory_gb, storage_gb (clusters without node pools)
+  node_pools          count, nodes_ready, cpus, memory_gb, items (clusters
+                      with node pools, items as in 'gsctl list nodepools')
+  ingress_ports       protocol, port (KVM only)
+  web_ui_url
 `,
 
 		// PreRun checks a few general things, like authentication.
@@ -55,6 +86,17 @@ Examples:
 	arguments Arguments
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowClusterCommand.ResetFlags()
+	ShowClusterCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+	ShowClusterCommand.Flags().BoolVarP(&flags.Watch, "watch", "w", false, "Keep polling and update the details when the cluster changes.")
+	ShowClusterCommand.Flags().DurationVarP(&flags.WatchInterval, "watch-interval", "", watch.DefaultInterval, "Time between two updates with --watch.")
+}
+
 const (
 	activityName = "show-cluster"
 
@@ -68,8 +110,11 @@ type Arguments struct {
 	authToken         string
 	scheme            string
 	clusterNameOrID   string
+	outputFormat      string
 	userProvidedToken string
 	verbose           bool
+	watch             bool
+	watchInterval     time.Duration
 }
 
 // collectArguments fills arguments from user input, config, and environment.
@@ -83,8 +128,11 @@ func collectArguments() Arguments {
 		authToken:         token,
 		scheme:            scheme,
 		clusterNameOrID:   "",
+		outputFormat:      flags.OutputFormat,
 		userProvidedToken: flags.Token,
 		verbose:           flags.Verbose,
+		watch:             flags.Watch,
+		watchInterval:     flags.WatchInterval,
 	}
 }
 
@@ -101,7 +149,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 
 	// handle non-common errors
 	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func verifyPreconditions(args Arguments, cmdLineArgs []string) error {
@@ -114,6 +162,12 @@ func verifyPreconditions(args Arguments, cmdLineArgs []string) error {
 	if len(cmdLineArgs) == 0 {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	}
+	if args.outputFormat != "" && args.outputFormat != formatting.OutputFormatTable && !formatting.IsStructured(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	}
+	if args.watch && !watch.SupportsOutputFormat(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is not supported with --watch", args.outputFormat)
+	}
 	return nil
 }
 
@@ -231,14 +285,14 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 	var nodePools *models.V5GetNodePoolsResponse
 
 	var err error
-	args.clusterNameOrID, err = clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+	args.clusterNameOrID, err = clustercache.GetIDCached(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
 	if err != nil {
 		return nil, nil, nil, nil, nil, microerror.Mask(err)
 	}
 
 	// first try v5
 	if args.verbose {
-		fmt.Println(color.WhiteString("Fetching details for cluster via v5 API endpoint."))
+		fmt.Fprintln(messageWriter(args), color.WhiteString("Fetching details for cluster via v5 API endpoint."))
 	}
 	clusterDetailsV5, v5Err := getClusterDetailsV5(args)
 	if v5Err == nil {
@@ -263,7 +317,7 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 
 		// Fall back to v4.
 		if args.verbose {
-			fmt.Println(color.WhiteString("No usable v5 response. Fetching details for cluster via v4 API endpoint."))
+			fmt.Fprintln(messageWriter(args), color.WhiteString("No usable v5 response. Fetching details for cluster via v4 API endpoint."))
 		}
 
 		var clusterDetailsV4Err error
@@ -275,7 +329,7 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 		}
 
 		if args.verbose {
-			fmt.Println(color.WhiteString("Fetching status for v4 cluster."))
+			fmt.Fprintln(messageWriter(args), color.WhiteString("Fetching status for v4 cluster."))
 		}
 		auxParams := clientWrapper.DefaultAuxiliaryParams()
 		auxParams.ActivityName = activityName
@@ -308,18 +362,18 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 
 		if credentialID != "" {
 			if args.verbose {
-				fmt.Println(color.WhiteString("Fetching credential details for organization %s", clusterOwner))
+				fmt.Fprintln(messageWriter(args), color.WhiteString("Fetching credential details for organization %s", clusterOwner))
 			}
 
 			var credentialDetailsErr error
 			credentialDetails, credentialDetailsErr = getOrgCredentials(clusterOwner, credentialID, args)
 			if credentialDetailsErr != nil {
 				if time.Since(created) < clusterCreationExpectedDuration {
-					fmt.Println("This is expected for clusters which are most likely still in creation.")
+					fmt.Fprintln(messageWriter(args), "This is expected for clusters which are most likely still in creation.")
 				}
 				// Print any error occurring here, but don't return, as this is non-critical.
-				fmt.Printf(color.YellowString("Warning: credential details for org %s (credential ID %s) could not be fetched.\n", clusterOwner, credentialID))
-				fmt.Printf("Error details: %s\n", credentialDetailsErr)
+				fmt.Fprint(messageWriter(args), color.YellowString("Warning: credential details for org %s (credential ID %s) could not be fetched.\n", clusterOwner, credentialID))
+				fmt.Fprintf(messageWriter(args), "Error details: %s\n", credentialDetailsErr)
 			}
 		}
 	}
@@ -351,40 +405,152 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	arguments.clusterNameOrID = cmdLineArgs[0]
 
 	if arguments.verbose {
-		fmt.Println(color.WhiteString("Fetching details for cluster %s.", arguments.clusterNameOrID))
+		fmt.Fprintln(messageWriter(arguments), color.WhiteString("Fetching details for cluster %s.", arguments.clusterNameOrID))
 	}
 
-	clientWrapper, err := client.NewWithConfig(arguments.apiEndpoint, arguments.userProvidedToken)
+	if arguments.watch {
+		err := watch.Run(watch.Config{
+			Fetch:        newSnapshotFetcher(arguments),
+			Interval:     arguments.watchInterval,
+			OutputFormat: arguments.outputFormat,
+			Title:        cmd.CommandPath() + " " + arguments.clusterNameOrID,
+			Redraw:       watch.IsTerminal(os.Stdout),
+		})
+		if err != nil {
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+		return
+	}
+
+	cluster, err := fetchCluster(arguments)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
-	clusterDetailsV4, clusterDetailsV5, nodePools, clusterStatus, credentialDetails, err := getClusterDetails(clientWrapper, arguments)
+	if formatting.IsStructured(arguments.outputFormat) {
+		structuredOutput, err := getStructuredOutput(arguments.outputFormat, cluster.output(arguments))
+		if err != nil {
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+
+		fmt.Println(structuredOutput)
+		return
+	}
 
-	var capabilitiesService *capabilities.Service
-	if err == nil {
-		capabilitiesService, err = getCapabilitiesService(arguments)
+	if cluster.detailsV4 != nil {
+		printV4Result(arguments, cluster.detailsV4, cluster.status, cluster.credentialDetails, cluster.releaseInfo)
+	} else if cluster.detailsV5 != nil {
+		printV5Result(arguments, cluster.detailsV5, cluster.credentialDetails, cluster.nodePools, cluster.capabilitiesService, cluster.releaseInfo)
 	}
+}
+
+// clusterData holds everything fetched from the API to show a cluster.
+type clusterData struct {
+	detailsV4           *models.V4ClusterDetailsResponse
+	detailsV5           *models.V5ClusterDetailsResponse
+	nodePools           *models.V5GetNodePoolsResponse
+	status              *client.ClusterStatus
+	credentialDetails   *models.V4GetCredentialResponse
+	capabilitiesService *capabilities.Service
+	releaseInfo         *releaseinfo.ReleaseInfo
+}
+
+// fetchCluster performs all API calls required to show a cluster.
+func fetchCluster(args Arguments) (*clusterData, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
 	if err != nil {
-		handleError(microerror.Mask(err))
-		os.Exit(1)
+		return nil, microerror.Mask(err)
+	}
+
+	c := &clusterData{}
+
+	c.detailsV4, c.detailsV5, c.nodePools, c.status, c.credentialDetails, err = getClusterDetails(clientWrapper, args)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	c.capabilitiesService, err = getCapabilitiesService(args)
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
 	releaseInfoConfig := releaseinfo.Config{
 		ClientWrapper: clientWrapper,
 	}
-	releaseInfo, err := releaseinfo.New(releaseInfoConfig)
+	c.releaseInfo, err = releaseinfo.New(releaseInfoConfig)
 	if err != nil {
-		handleError(microerror.Mask(err))
-		os.Exit(1)
+		return nil, microerror.Mask(err)
 	}
 
-	if clusterDetailsV4 != nil {
-		printV4Result(arguments, clusterDetailsV4, clusterStatus, credentialDetails, releaseInfo)
-	} else if clusterDetailsV5 != nil {
-		printV5Result(arguments, clusterDetailsV5, credentialDetails, nodePools, capabilitiesService, releaseInfo)
+	return c, nil
+}
+
+// output returns the cluster details as printed in structured output.
+func (c *clusterData) output(args Arguments) *clusterOutput {
+	if c.detailsV4 != nil {
+		return getV4Output(args, c.detailsV4, c.status, c.credentialDetails, c.releaseInfo)
 	}
+
+	return getV5Output(args, c.detailsV5, c.credentialDetails, c.nodePools, c.capabilitiesService, c.releaseInfo)
+}
+
+// newSnapshotFetcher returns the function fetching the cluster for
+// watching. Once the cluster has been shown, it disappearing is reported
+// as a deletion instead of an error.
+func newSnapshotFetcher(args Arguments) func() (*watch.Snapshot, error) {
+	found := false
+
+	return func() (*watch.Snapshot, error) {
+		snapshot, err := getSnapshot(args)
+		if errors.IsClusterNotFoundError(err) && found {
+			return &watch.Snapshot{Footer: color.RedString("The cluster %s does not exist anymore.", args.clusterNameOrID)}, nil
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		found = true
+
+		return snapshot, nil
+	}
+}
+
+// getSnapshot returns the cluster details for watching. In structured
+// output, the snapshot consists of the cluster only. Otherwise, every
+// line of the details table is an item, identified by its label.
+func getSnapshot(args Arguments) (*watch.Snapshot, error) {
+	cluster, err := fetchCluster(args)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	output := cluster.output(args)
+	if formatting.IsStructured(args.outputFormat) {
+		return &watch.Snapshot{Items: []watch.Item{{Key: output.ID, Object: output}}}, nil
+	}
+
+	var rows []string
+	snapshot := &watch.Snapshot{}
+	if cluster.detailsV4 != nil {
+		rows = getV4Table(args, cluster.detailsV4, cluster.status, cluster.credentialDetails, cluster.releaseInfo)
+	} else {
+		rows = getV5Table(args, cluster.detailsV5, cluster.credentialDetails, cluster.nodePools, cluster.capabilitiesService, cluster.releaseInfo)
+		snapshot.Footer = strings.TrimSpace(getNodePoolsHint(cluster.detailsV5.ID, cluster.nodePools))
+	}
+
+	lines := strings.Split(columnize.SimpleFormat(rows), "\n")
+	for i, row := range rows {
+		if i >= len(lines) {
+			break
+		}
+
+		label := strings.SplitN(table.RemoveColors(row), "|", 2)[0]
+		snapshot.Items = append(snapshot.Items, watch.Item{Key: label, Line: lines[i], Object: lines[i]})
+	}
+
+	return snapshot, nil
 }
 
 // printV4Result prints the detils for a V4 cluster.
@@ -395,23 +561,22 @@ func printV4Result(
 	credentialDetails *models.V4GetCredentialResponse,
 	releaseInfo *releaseinfo.ReleaseInfo,
 ) {
-	// Calculate worker node count.
-	numWorkers := 0
-	if clusterStatus != nil && clusterStatus.Cluster.Nodes != nil {
-		// Count all nodes as workers which are not explicitly marked as master.
-		for _, node := range clusterStatus.Cluster.Nodes {
-			val, ok := node.Labels["role"]
-			if !ok {
-				// Workaround for k8s 1.14 because the label changed.
-				val, ok = node.Labels["kubernetes.io/role"]
-			}
-			if ok && val == "master" {
-				// don't count this
-			} else {
-				numWorkers++
-			}
-		}
-	}
+	output := getV4Table(args, clusterDetails, clusterStatus, credentialDetails, releaseInfo)
+	fmt.Println(columnize.SimpleFormat(output))
+}
+
+// getV4Table returns the rows of the details table for a V4 cluster,
+// with label and value separated by '|'.
+func getV4Table(
+	args Arguments,
+	clusterDetails *models.V4ClusterDetailsResponse,
+	clusterStatus *client.ClusterStatus,
+	credentialDetails *models.V4GetCredentialResponse,
+	releaseInfo *releaseinfo.ReleaseInfo,
+) []string {
+	// Calculate worker node count. All nodes not explicitly marked as
+	// master are counted as workers.
+	_, numWorkers := clusterwait.CountNodes(clusterStatus)
 
 	webUIURL, _ := webui.ClusterDetailsURL(args.apiEndpoint, clusterDetails.ID, clusterDetails.Owner)
 
@@ -489,7 +654,7 @@ func printV4Result(
 		output = append(output, color.YellowString("Web UI:")+"|"+webUIURL)
 	}
 
-	fmt.Println(columnize.SimpleFormat(output))
+	return output
 }
 
 // printV5Result prints details for a v5 clsuter.
@@ -501,6 +666,25 @@ func printV5Result(
 	capabilitiesService *capabilities.Service,
 	releaseInfo *releaseinfo.ReleaseInfo,
 ) {
+	clusterTable := getV5Table(args, details, credentialDetails, nodePools, capabilitiesService, releaseInfo)
+
+	if nodePools != nil {
+		fmt.Println(columnize.SimpleFormat(clusterTable))
+		fmt.Println()
+		fmt.Print(getNodePoolsHint(details.ID, nodePools))
+	}
+}
+
+// getV5Table returns the rows of the details table for a v5 cluster,
+// with label and value separated by '|'.
+func getV5Table(
+	args Arguments,
+	details *models.V5ClusterDetailsResponse,
+	credentialDetails *models.V4GetCredentialResponse,
+	nodePools *models.V5GetNodePoolsResponse,
+	capabilitiesService *capabilities.Service,
+	releaseInfo *releaseinfo.ReleaseInfo,
+) []string {
 
 	webUIURL, _ := webui.ClusterDetailsURL(args.apiEndpoint, details.ID, details.Owner)
 
@@ -554,25 +738,29 @@ func printV5Result(
 	// once KVM is supported in V5.
 
 	// Aggregate of node pools.
-	if nodePools != nil {
-		if len(*nodePools) > 0 {
-			clusterTable = append(clusterTable, formatNodePoolDetails(nodePools)...)
+	if nodePools != nil && len(*nodePools) > 0 {
+		clusterTable = append(clusterTable, formatNodePoolDetails(nodePools)...)
+	}
 
-			fmt.Println(columnize.SimpleFormat(clusterTable))
+	return clusterTable
+}
 
-			fmt.Println()
-			fmt.Printf("This cluster has node pools. For details, use\n\n")
-			fmt.Printf("    %s\n\n", color.YellowString("gsctl list nodepools %s", details.ID))
-			fmt.Printf("For details on a specific node pool, use\n\n")
-			fmt.Printf("    %s\n\n", color.YellowString("gsctl show nodepool %s/<nodepool-id>", details.ID))
-		} else {
-			fmt.Println(columnize.SimpleFormat(clusterTable))
+// getNodePoolsHint returns the hint on how to get node pool details, or how
+// to add a node pool, printed below the details of a v5 cluster.
+func getNodePoolsHint(clusterID string, nodePools *models.V5GetNodePoolsResponse) string {
+	if nodePools == nil {
+		return ""
+	}
 
-			fmt.Println()
-			fmt.Print("This cluster has no node pools. Find out how to add a node pool using\n\n")
-			fmt.Printf("    %s\n\n", color.YellowString("gsctl create nodepool --help"))
-		}
+	if len(*nodePools) > 0 {
+		return "This cluster has node pools. For details, use\n\n" +
+			fmt.Sprintf("    %s\n\n", color.YellowString("gsctl list nodepools %s", clusterID)) +
+			"For details on a specific node pool, use\n\n" +
+			fmt.Sprintf("    %s\n\n", color.YellowString("gsctl show nodepool %s/<nodepool-id>", clusterID))
 	}
+
+	return "This cluster has no node pools. Find out how to add a node pool using\n\n" +
+		fmt.Sprintf("    %s\n\n", color.YellowString("gsctl create nodepool --help"))
 }
 
 // formatDate takes a date/time string from the API and returns a formated version.
@@ -596,12 +784,7 @@ func formatCredentialDetails(credentialDetails *models.V4GetCredentialResponse)
 	rows := []string{}
 
 	if credentialDetails.Aws != nil {
-		parts := strings.Split(credentialDetails.Aws.Roles.Awsoperator, ":")
-		if len(parts) > 3 {
-			rows = append(rows, color.YellowString("AWS account:")+"|"+parts[4])
-		} else {
-			rows = append(rows, color.YellowString("AWS account:")+"|n/a")
-		}
+		rows = append(rows, color.YellowString("AWS account:")+"|"+stringOrPlaceholder(getAWSAccountID(credentialDetails)))
 	} else if credentialDetails.Azure != nil {
 		rows = append(rows, color.YellowString("Azure subscription:")+"|"+credentialDetails.Azure.Credential.SubscriptionID)
 		rows = append(rows, color.YellowString("Azure tenant:")+"|"+credentialDetails.Azure.Credential.TenantID)
@@ -610,9 +793,22 @@ func formatCredentialDetails(credentialDetails *models.V4GetCredentialResponse)
 	return rows
 }
 
-func formatNodePoolDetails(nodePools *models.V5GetNodePoolsResponse) []string {
-	var err error
+// getAWSAccountID extracts the AWS account ID from the role ARN of an
+// AWS credential. It returns an empty string if the ARN can't be parsed.
+func getAWSAccountID(credentialDetails *models.V4GetCredentialResponse) string {
+	if credentialDetails.Aws == nil || credentialDetails.Aws.Roles == nil {
+		return ""
+	}
 
+	parts := strings.Split(credentialDetails.Aws.Roles.Awsoperator, ":")
+	if len(parts) > 4 {
+		return parts[4]
+	}
+
+	return ""
+}
+
+func formatNodePoolDetails(nodePools *models.V5GetNodePoolsResponse) []string {
 	var numNodePools int
 	{
 		if nodePools != nil {
@@ -623,50 +819,7 @@ func formatNodePoolDetails(nodePools *models.V5GetNodePoolsResponse) []string {
 		}
 	}
 
-	providerInfo, err := guessProviderInfo(nodePools)
-	if err != nil {
-		fmt.Println(color.RedString(microerror.Pretty(err, false)))
-	}
-
-	var numNodes int
-	var nodesReady int
-	var cpus int
-	var ramGB float64
-	{
-		if numNodePools > 0 {
-			for _, np := range *nodePools {
-				numNodes += int(np.Status.NodesReady)
-				nodesReady = int(np.Status.NodesReady)
-
-				switch info := providerInfo.(type) {
-				case *nodespec.ProviderAWS:
-					if np.NodeSpec.Aws != nil && len(np.NodeSpec.Aws.InstanceType) > 0 {
-						var it *nodespec.InstanceType
-						it, err = info.GetInstanceTypeDetails(np.NodeSpec.Aws.InstanceType)
-						if err != nil {
-							fmt.Println(color.YellowString("Warning: Cannot provide info on AWS instance type '%s'. Please kindly report this to the Giant Swarm support team.", np.NodeSpec.Aws.InstanceType))
-							continue
-						}
-
-						cpus += it.CPUCores * nodesReady
-						ramGB += float64(it.MemorySizeGB * nodesReady)
-					}
-				case *nodespec.ProviderAzure:
-					if np.NodeSpec.Azure != nil && len(np.NodeSpec.Azure.VMSize) > 0 {
-						var vs *nodespec.VMSize
-						vs, err = info.GetVMSizeDetails(np.NodeSpec.Azure.VMSize)
-						if err != nil {
-							fmt.Println(color.YellowString("Warning: Cannot provide info on Azure VM size '%s'. Please kindly report this to the Giant Swarm support team.", np.NodeSpec.Azure.VMSize))
-							continue
-						}
-
-						cpus += int(vs.NumberOfCores) * nodesReady
-						ramGB += vs.MemoryInMB * float64(nodesReady) / 1000
-					}
-				}
-			}
-		}
-	}
+	numNodes, cpus, ramGB := sumNodePoolResources(nodePools, os.Stdout)
 
 	var rows []string
 	{
@@ -688,6 +841,55 @@ func formatNodePoolDetails(nodePools *models.V5GetNodePoolsResponse) []string {
 	return rows
 }
 
+// sumNodePoolResources adds up the ready nodes, CPU cores and RAM (in GB) of
+// all node pools. Warnings about unknown instance types or VM sizes are
+// printed to messages.
+func sumNodePoolResources(nodePools *models.V5GetNodePoolsResponse, messages io.Writer) (numNodes int, cpus int, ramGB float64) {
+	if nodePools == nil || len(*nodePools) == 0 {
+		return 0, 0, 0
+	}
+
+	providerInfo, err := guessProviderInfo(nodePools)
+	if err != nil {
+		fmt.Fprintln(messages, color.RedString(microerror.Pretty(err, false)))
+	}
+
+	for _, np := range *nodePools {
+		if np.Status == nil {
+			continue
+		}
+		nodesReady := int(np.Status.NodesReady)
+		numNodes += nodesReady
+
+		switch info := providerInfo.(type) {
+		case *nodespec.ProviderAWS:
+			if np.NodeSpec.Aws != nil && len(np.NodeSpec.Aws.InstanceType) > 0 {
+				it, err := info.GetInstanceTypeDetails(np.NodeSpec.Aws.InstanceType)
+				if err != nil {
+					fmt.Fprintln(messages, color.YellowString("Warning: Cannot provide info on AWS instance type '%s'. Please kindly report this to the Giant Swarm support team.", np.NodeSpec.Aws.InstanceType))
+					continue
+				}
+
+				cpus += it.CPUCores * nodesReady
+				ramGB += float64(it.MemorySizeGB * nodesReady)
+			}
+		case *nodespec.ProviderAzure:
+			if np.NodeSpec.Azure != nil && len(np.NodeSpec.Azure.VMSize) > 0 {
+				vs, err := info.GetVMSizeDetails(np.NodeSpec.Azure.VMSize)
+				if err != nil {
+					fmt.Fprintln(messages, color.YellowString("Warning: Cannot provide info on Azure VM size '%s'. Please kindly report this to the Giant Swarm support team.", np.NodeSpec.Azure.VMSize))
+					continue
+				}
+
+				cpus += int(vs.NumberOfCores) * nodesReady
+				ramGB += vs.MemoryInMB * float64(nodesReady) / 1000
+			}
+		}
+	}
+
+	return numNodes, cpus, ramGB
+}
+
 func formatClusterLabels(labels map[string]string) []string {
 	formattedClusterLabels := []string{color.YellowString("Labels:|") + naString}
 
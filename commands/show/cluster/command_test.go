@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/releaseinfo"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -901,3 +902,203 @@ func toInt8Ptr(i int) *int8 {
 
 	return &c
 }
+
+// Test_getV5Output tests the structured output for a cluster with node pools,
+// which merges cluster details, node pools and release information.
+func Test_getV5Output(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"general": {"kubernetes_versions": [{"minor_version": "1.16", "eol_date": "1960-01-01"}]}}`))
+		case "/v4/releases/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"timestamp": "2020-01-01T12:00:00Z", "version": "11.0.0", "active": true, "changelog": [], "components": [{"name": "kubernetes", "version": "1.16.3"}]}]`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(mockServer.URL, "my-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseInfo, err := releaseinfo.New(releaseinfo.Config{ClientWrapper: clientWrapper})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details := &models.V5ClusterDetailsResponse{
+		ID:             "f01r4",
+		Name:           "Production",
+		CreateDate:     "2020-01-02T12:00:00Z",
+		Owner:          "acme",
+		APIEndpoint:    "https://api.f01r4.example.com",
+		ReleaseVersion: "11.0.0",
+		Labels:         map[string]string{"team": "blue", "giantswarm.io/cluster": "f01r4"},
+		MasterNodes: &models.V5ClusterDetailsResponseMasterNodes{
+			AvailabilityZones: []string{"eu-west-1a"},
+		},
+	}
+	nodePools := &models.V5GetNodePoolsResponse{
+		&models.V5GetNodePoolsResponseItems{
+			ID: "a7k4",
+			NodeSpec: &models.V5GetNodePoolsResponseItemsNodeSpec{
+				Aws: &models.V5GetNodePoolsResponseItemsNodeSpecAws{InstanceType: "m5.xlarge"},
+			},
+			Status: &models.V5GetNodePoolsResponseItemsStatus{Nodes: 3, NodesReady: 2},
+		},
+	}
+	credential := &models.V4GetCredentialResponse{
+		ID: "cred1",
+		Aws: &models.V4GetCredentialResponseAws{
+			Roles: &models.V4GetCredentialResponseAwsRoles{Awsoperator: "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator"},
+		},
+	}
+
+	args := Arguments{apiEndpoint: mockServer.URL, outputFormat: "json"}
+	output := getV5Output(args, details, credential, nodePools, nil, releaseInfo)
+
+	if output.Kubernetes.Version != "1.16.3" || !output.Kubernetes.EndOfLife || output.Kubernetes.EndOfLifeDate != "1960-01-01" {
+		t.Errorf("Unexpected Kubernetes details %#v", output.Kubernetes)
+	}
+	if len(output.Labels) != 1 || output.Labels["team"] != "blue" {
+		t.Errorf("Expected only the user-defined label, got %v", output.Labels)
+	}
+	if output.Credential == nil || output.Credential.AWSAccountID != "123456789012" {
+		t.Errorf("Unexpected credential details %#v", output.Credential)
+	}
+	if output.Masters == nil || output.Masters.Count != 1 || output.Masters.Ready != nil {
+		t.Errorf("Unexpected master details %#v", output.Masters)
+	}
+	if output.NodePools == nil || output.NodePools.Count != 1 || output.NodePools.NodesReady != 2 || output.NodePools.CPUs != 8 || output.NodePools.MemoryGB != 32 {
+		t.Errorf("Unexpected node pool details %#v", output.NodePools)
+	}
+	if output.Workers != nil {
+		t.Errorf("Expected no workers for a cluster with node pools, got %#v", output.Workers)
+	}
+
+	yamlOutput, err := getStructuredOutput("yaml", output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expected := range []string{"id: f01r4\n", "release_version: 11.0.0", "  end_of_life: true\n", "  aws_account_id: \"123456789012\"\n", "  nodes_ready: 2\n", "    id: a7k4\n"} {
+		if !strings.Contains(yamlOutput, expected) {
+			t.Errorf("Expected %q in YAML output, got:\n%s", expected, yamlOutput)
+		}
+	}
+}
+
+// Test_newSnapshotFetcher tests the snapshots used with --watch, and that a
+// cluster disappearing after the first poll results in an empty snapshot.
+func Test_newSnapshotFetcher(t *testing.T) {
+	deleted := false
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"general": {"provider": "aws"}}`))
+		case "/v4/releases/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			if deleted {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acmeorg"}]`))
+		case "/v4/clusters/cluster-id/":
+			if deleted {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Cluster does not exist or is not accessible."}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "cluster-id",
+				"name": "Name of the cluster",
+				"api_endpoint": "https://api.foo.bar",
+				"create_date": "2017-11-20T12:00:00.000000Z",
+				"owner": "acmeorg",
+				"release_version": "0.3.0",
+				"scaling": {"min": 3, "max": 3},
+				"workers": [
+					{"aws": {"instance_type": "m3.large"}, "memory": {"size_gb": 5}, "storage": {"size_gb": 50}, "cpu": {"cores": 2}}
+				]
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	yamlText := `
+endpoints:
+  ` + mockServer.URL + `:
+    email: email@example.com
+    token: my-token
+    provider: aws
+selected_endpoint: ` + mockServer.URL
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, yamlText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		apiEndpoint:     mockServer.URL,
+		authToken:       "my-token",
+		clusterNameOrID: "cluster-id",
+		outputFormat:    "table",
+		watch:           true,
+	}
+
+	err = verifyPreconditions(args, []string{args.clusterNameOrID})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	fetch := newSnapshotFetcher(args)
+
+	snapshot, err := fetch()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(snapshot.Items) == 0 || snapshot.Items[0].Key != "ID:" || !strings.Contains(snapshot.Items[0].Line, "cluster-id") {
+		t.Errorf("Expected the cluster ID as first line, got %#v", snapshot.Items)
+	}
+
+	deleted = true
+	snapshot, err = fetch()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(snapshot.Items) != 0 {
+		t.Errorf("Expected no items for a deleted cluster, got %#v", snapshot.Items)
+	}
+
+	// Without a previous successful poll, errors are returned.
+	_, err = newSnapshotFetcher(args)()
+	if !errors.IsClusterNotFoundError(err) {
+		t.Errorf("Expected ClusterNotFoundError, got %#v", err)
+	}
+
+	args.outputFormat = "jsonpath={.id}"
+	err = verifyPreconditions(args, []string{args.clusterNameOrID})
+	if !errors.IsOutputFormatInvalid(err) {
+		t.Errorf("Expected OutputFormatInvalidError, got %#v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package cluster
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/capabilities"
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+	"github.com/giantswarm/gsctl/pkg/releaseinfo"
+	"github.com/giantswarm/gsctl/util"
+	"github.com/giantswarm/gsctl/webui"
+)
+
+// clusterOutput is the structure printed with '--output json' and
+// '--output yaml'. It combines the cluster details, the cluster status,
+// node pools, credential details and release information, which are
+// fetched via separate API calls. The field names are documented in the
+// command help and must be kept stable.
+type clusterOutput struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	CreateDate     string `json:"create_date"`
+	Owner          string `json:"owner"`
+	APIEndpoint    string `json:"api_endpoint"`
+	ReleaseVersion string `json:"release_version"`
+
+	Kubernetes kubernetesOutput `json:"kubernetes"`
+
+	// Labels contains the user-defined cluster labels (v5 only).
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Credential is only set for clusters using an organization's own
+	// cloud provider credentials.
+	Credential *credentialOutput `json:"credential,omitempty"`
+
+	Masters *mastersOutput `json:"masters,omitempty"`
+
+	// Workers is only set for clusters without node pools (v4).
+	Workers *workersOutput `json:"workers,omitempty"`
+
+	// NodePools is only set for clusters supporting node pools (v5).
+	NodePools *nodePoolsOutput `json:"node_pools,omitempty"`
+
+	// IngressPorts contains the ingress port mappings on KVM.
+	IngressPorts []*models.V4ClusterDetailsResponseKvmPortMappingsItems `json:"ingress_ports,omitempty"`
+
+	WebUIURL string `json:"web_ui_url,omitempty"`
+}
+
+// kubernetesOutput describes the Kubernetes version of the cluster release.
+type kubernetesOutput struct {
+	Version       string `json:"version,omitempty"`
+	EndOfLife     bool   `json:"end_of_life"`
+	EndOfLifeDate string `json:"end_of_life_date,omitempty"`
+}
+
+// credentialOutput describes the cloud provider account a cluster runs in.
+type credentialOutput struct {
+	ID                string `json:"id"`
+	AWSAccountID      string `json:"aws_account_id,omitempty"`
+	AzureSubscription string `json:"azure_subscription_id,omitempty"`
+	AzureTenant       string `json:"azure_tenant_id,omitempty"`
+}
+
+// mastersOutput describes the master nodes of a cluster.
+type mastersOutput struct {
+	AvailabilityZones []string `json:"availability_zones"`
+	Count             int      `json:"count"`
+	HighAvailability  bool     `json:"high_availability"`
+	// Ready is only set if the release supports high-availability masters.
+	Ready *int `json:"ready,omitempty"`
+}
+
+// workersOutput describes the worker nodes of a cluster without node pools.
+type workersOutput struct {
+	InstanceType      string   `json:"instance_type,omitempty"`
+	VMSize            string   `json:"vm_size,omitempty"`
+	AvailabilityZones []string `json:"availability_zones,omitempty"`
+	ScalingMin        int64    `json:"scaling_min"`
+	ScalingMax        int64    `json:"scaling_max"`
+	// Desired is only set if the cluster status is available and the
+	// worker count isn't pinned.
+	Desired   *int    `json:"desired,omitempty"`
+	Running   int     `json:"running"`
+	CPUs      uint    `json:"cpus"`
+	MemoryGB  float64 `json:"memory_gb"`
+	StorageGB float64 `json:"storage_gb,omitempty"`
+}
+
+// nodePoolsOutput sums up the node pools of a cluster and contains the
+// node pools as returned by the API.
+type nodePoolsOutput struct {
+	Count      int                           `json:"count"`
+	NodesReady int                           `json:"nodes_ready"`
+	CPUs       int                           `json:"cpus"`
+	MemoryGB   float64                       `json:"memory_gb"`
+	Items      models.V5GetNodePoolsResponse `json:"items"`
+}
+
+// messageWriter returns where verbose messages and warnings are printed to.
+// With structured output they go to stderr, so stdout stays parseable.
+func messageWriter(args Arguments) io.Writer {
+	if formatting.IsStructured(args.outputFormat) {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// getStructuredOutput renders the cluster output in the given structured
+// output format.
+func getStructuredOutput(outputFormat string, output *clusterOutput) (string, error) {
+	outputBytes, err := formatting.Marshal(outputFormat, output)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return string(outputBytes), nil
+}
+
+// getV4Output assembles the structured output for a cluster without
+// node pools.
+func getV4Output(
+	args Arguments,
+	clusterDetails *models.V4ClusterDetailsResponse,
+	clusterStatus *client.ClusterStatus,
+	credentialDetails *models.V4GetCredentialResponse,
+	releaseInfo *releaseinfo.ReleaseInfo,
+) *clusterOutput {
+	output := &clusterOutput{
+		ID:             clusterDetails.ID,
+		Name:           clusterDetails.Name,
+		CreateDate:     clusterDetails.CreateDate,
+		Owner:          clusterDetails.Owner,
+		APIEndpoint:    clusterDetails.APIEndpoint,
+		ReleaseVersion: clusterDetails.ReleaseVersion,
+		Kubernetes:     getKubernetesOutput(releaseInfo, clusterDetails.ReleaseVersion),
+		Credential:     getCredentialOutput(credentialDetails),
+	}
+	output.WebUIURL, _ = webui.ClusterDetailsURL(args.apiEndpoint, clusterDetails.ID, clusterDetails.Owner)
+
+	_, numWorkers := clusterwait.CountNodes(clusterStatus)
+
+	workers := &workersOutput{
+		Running: numWorkers,
+	}
+	if len(clusterDetails.AvailabilityZones) > 0 {
+		workers.AvailabilityZones = append(workers.AvailabilityZones, clusterDetails.AvailabilityZones...)
+		sort.Strings(workers.AvailabilityZones)
+	}
+	if len(clusterDetails.Workers) > 0 {
+		if clusterDetails.Workers[0].Aws != nil {
+			workers.InstanceType = clusterDetails.Workers[0].Aws.InstanceType
+		} else if clusterDetails.Workers[0].Azure != nil {
+			workers.VMSize = clusterDetails.Workers[0].Azure.VMSize
+		}
+
+		workers.CPUs = sumWorkerCPUs(numWorkers, clusterDetails.Workers)
+		workers.MemoryGB = sumWorkerMemory(numWorkers, clusterDetails.Workers)
+		if clusterDetails.Kvm != nil {
+			workers.StorageGB = sumWorkerStorage(numWorkers, clusterDetails.Workers)
+		}
+	}
+	if clusterDetails.Scaling != nil {
+		if clusterDetails.Scaling.Min != nil {
+			workers.ScalingMin = *clusterDetails.Scaling.Min
+		}
+		workers.ScalingMax = clusterDetails.Scaling.Max
+
+		if clusterStatus != nil && clusterStatus.Cluster != nil && workers.ScalingMin != workers.ScalingMax {
+			desired := clusterStatus.Cluster.Scaling.DesiredCapacity
+			workers.Desired = &desired
+		}
+	}
+	output.Workers = workers
+
+	if clusterDetails.Kvm != nil {
+		output.IngressPorts = clusterDetails.Kvm.PortMappings
+	}
+
+	return output
+}
+
+// getV5Output assembles the structured output for a cluster supporting
+// node pools.
+func getV5Output(
+	args Arguments,
+	details *models.V5ClusterDetailsResponse,
+	credentialDetails *models.V4GetCredentialResponse,
+	nodePools *models.V5GetNodePoolsResponse,
+	capabilitiesService *capabilities.Service,
+	releaseInfo *releaseinfo.ReleaseInfo,
+) *clusterOutput {
+	output := &clusterOutput{
+		ID:             details.ID,
+		Name:           details.Name,
+		CreateDate:     details.CreateDate,
+		Owner:          details.Owner,
+		APIEndpoint:    details.APIEndpoint,
+		ReleaseVersion: details.ReleaseVersion,
+		Kubernetes:     getKubernetesOutput(releaseInfo, details.ReleaseVersion),
+		Credential:     getCredentialOutput(credentialDetails),
+	}
+	output.WebUIURL, _ = webui.ClusterDetailsURL(args.apiEndpoint, details.ID, details.Owner)
+
+	for key, value := range details.Labels {
+		if strings.Contains(key, util.LabelFilterKeySubstring) {
+			continue
+		}
+		if output.Labels == nil {
+			output.Labels = map[string]string{}
+		}
+		output.Labels[key] = value
+	}
+
+	if details.MasterNodes != nil {
+		masters := &mastersOutput{
+			AvailabilityZones: details.MasterNodes.AvailabilityZones,
+			Count:             1,
+			HighAvailability:  details.MasterNodes.HighAvailability,
+		}
+		if masters.HighAvailability {
+			masters.Count = 3
+		}
+
+		haMastersEnabled := false
+		if capabilitiesService != nil {
+			haMastersEnabled, _ = capabilitiesService.HasCapability(details.ReleaseVersion, capabilities.HAMasters)
+		}
+		if haMastersEnabled && details.MasterNodes.NumReady != nil && *details.MasterNodes.NumReady >= 0 {
+			ready := int(*details.MasterNodes.NumReady)
+			masters.Ready = &ready
+		}
+
+		output.Masters = masters
+	} else if details.Master != nil {
+		output.Masters = &mastersOutput{
+			AvailabilityZones: []string{details.Master.AvailabilityZone},
+			Count:             1,
+		}
+	}
+
+	if nodePools != nil {
+		nodePoolsSummary := &nodePoolsOutput{
+			Count: len(*nodePools),
+			Items: *nodePools,
+		}
+		nodePoolsSummary.NodesReady, nodePoolsSummary.CPUs, nodePoolsSummary.MemoryGB = sumNodePoolResources(nodePools, messageWriter(args))
+
+		output.NodePools = nodePoolsSummary
+	}
+
+	return output
+}
+
+// getKubernetesOutput returns the Kubernetes version details of a release.
+// The version is left empty if the release details are not available.
+func getKubernetesOutput(releaseInfo *releaseinfo.ReleaseInfo, releaseVersion string) kubernetesOutput {
+	releaseData, err := releaseInfo.GetReleaseData(releaseVersion)
+	if err != nil {
+		return kubernetesOutput{}
+	}
+
+	return kubernetesOutput{
+		Version:       releaseData.K8sVersion,
+		EndOfLife:     releaseData.IsK8sVersionEOL,
+		EndOfLifeDate: releaseData.K8sVersionEOLDate,
+	}
+}
+
+// getCredentialOutput returns the details of a BYOC credential, or nil if
+// the cluster doesn't use one.
+func getCredentialOutput(credentialDetails *models.V4GetCredentialResponse) *credentialOutput {
+	if credentialDetails == nil || credentialDetails.ID == "" {
+		return nil
+	}
+
+	output := &credentialOutput{ID: credentialDetails.ID}
+	if credentialDetails.Aws != nil {
+		output.AWSAccountID = getAWSAccountID(credentialDetails)
+	} else if credentialDetails.Azure != nil && credentialDetails.Azure.Credential != nil {
+		output.AzureSubscription = credentialDetails.Azure.Credential.SubscriptionID
+		output.AzureTenant = credentialDetails.Azure.Credential.TenantID
+	}
+
+	return output
+}
@@ -3,6 +3,7 @@ package show
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/commands/show/app"
 	"github.com/giantswarm/gsctl/commands/show/cluster"
 	"github.com/giantswarm/gsctl/commands/show/nodepool"
 	"github.com/giantswarm/gsctl/commands/show/release"
@@ -12,12 +13,13 @@ var (
 	// Command is the command to display single items
 	Command = &cobra.Command{
 		Use:   "show",
-		Short: "Show clusters, node pools, releases",
+		Short: "Show apps, clusters, node pools, releases",
 		Long:  `Print details of a cluster or a release`,
 	}
 )
 
 func init() {
+	Command.AddCommand(app.ShowAppCommand)
 	Command.AddCommand(cluster.ShowClusterCommand)
 	Command.AddCommand(nodepool.ShowNodepoolCommand)
 	Command.AddCommand(release.ShowReleaseCommand)
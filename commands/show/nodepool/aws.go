@@ -13,7 +13,7 @@ import (
 	"github.com/giantswarm/gsctl/nodespec"
 )
 
-func getOutputAWS(nodePool *models.V5GetNodePoolResponse) (string, error) {
+func getOutputAWS(nodePool *models.V5GetNodePoolResponse, outputFormat string) (string, error) {
 	awsInfo, err := nodespec.NewAWS()
 	if err != nil {
 		return "", microerror.Mask(err)
@@ -26,6 +26,18 @@ func getOutputAWS(nodePool *models.V5GetNodePoolResponse) (string, error) {
 		return "", microerror.Mask(err)
 	}
 
+	if formatting.IsStructured(outputFormat) {
+		var cpus *int64
+		var memoryGB *float64
+		if instanceTypeDetails != nil {
+			sumCPUs := nodePool.Status.NodesReady * int64(instanceTypeDetails.CPUCores)
+			sumMemory := float64(nodePool.Status.NodesReady * int64(instanceTypeDetails.MemorySizeGB))
+			cpus, memoryGB = &sumCPUs, &sumMemory
+		}
+
+		return getStructuredOutput(outputFormat, nodePool, cpus, memoryGB)
+	}
+
 	var instanceTypes string
 	{
 		if len(nodePool.Status.InstanceTypes) > 0 {
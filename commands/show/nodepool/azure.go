@@ -10,10 +10,11 @@ import (
 	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/microerror"
 
+	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/nodespec"
 )
 
-func getOutputAzure(nodePool *models.V5GetNodePoolResponse) (string, error) {
+func getOutputAzure(nodePool *models.V5GetNodePoolResponse, outputFormat string) (string, error) {
 	azureInfo, err := nodespec.NewAzureProvider()
 	if err != nil {
 		return "", microerror.Mask(err)
@@ -26,6 +27,18 @@ func getOutputAzure(nodePool *models.V5GetNodePoolResponse) (string, error) {
 		return "", microerror.Mask(err)
 	}
 
+	if formatting.IsStructured(outputFormat) {
+		var cpus *int64
+		var memoryGB *float64
+		if vmSizeDetails != nil {
+			sumCPUs := nodePool.Status.NodesReady * vmSizeDetails.NumberOfCores
+			sumMemory := float64(nodePool.Status.NodesReady) * vmSizeDetails.MemoryInMB / 1000
+			cpus, memoryGB = &sumCPUs, &sumMemory
+		}
+
+		return getStructuredOutput(outputFormat, nodePool, cpus, memoryGB)
+	}
+
 	var vmSizes string
 	{
 		if len(nodePool.Status.InstanceTypes) > 0 {
@@ -17,6 +17,7 @@ import (
 	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/nodespec"
 )
 
@@ -35,6 +36,12 @@ Examples:
 
   gsctl show nodepool f01r4/75rh1
   gsctl show nodepool "Cluster name"/75rh1
+  gsctl show nodepool f01r4/75rh1 --output json
+
+With --output json or --output yaml, the node pool is printed with the fields
+returned by the API (id, name, availability_zones, scaling, node_spec, status,
+subnet), plus 'cpus' and 'memory_gb' with the sum of CPU cores and RAM of all
+ready nodes, if the instance type or VM size is known.
 `,
 
 		// PreRun checks a few general things, like authentication.
@@ -49,11 +56,21 @@ const (
 	activityName = "show-nodepool"
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowNodepoolCommand.ResetFlags()
+	ShowNodepoolCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
 	clusterNameOrID   string
 	nodePoolID        string
+	outputFormat      string
 	userProvidedToken string
 }
 
@@ -67,6 +84,17 @@ type result struct {
 	sumMemory           float64
 }
 
+// nodePoolOutput is the structure printed with '--output json' and
+// '--output yaml'. It contains the node pool as returned by the API, plus
+// the sums of CPU cores and RAM of all nodes in state Ready. The sums are
+// omitted if the instance type or VM size is unknown.
+type nodePoolOutput struct {
+	*models.V5GetNodePoolResponse
+
+	CPUs     *int64   `json:"cpus,omitempty"`
+	MemoryGB *float64 `json:"memory_gb,omitempty"`
+}
+
 func collectArguments(positionalArgs []string) (*Arguments, error) {
 	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
 	token := config.Config.ChooseToken(endpoint, flags.Token)
@@ -82,6 +110,7 @@ func collectArguments(positionalArgs []string) (*Arguments, error) {
 		authToken:         token,
 		clusterNameOrID:   parts[0],
 		nodePoolID:        parts[1],
+		outputFormat:      flags.OutputFormat,
 		userProvidedToken: flags.Token,
 	}, nil
 }
@@ -100,6 +129,9 @@ func verifyPreconditions(args *Arguments) error {
 	if args.nodePoolID == "" {
 		return microerror.Mask(errors.NodePoolIDMissingError)
 	}
+	if args.outputFormat != "" && args.outputFormat != formatting.OutputFormatTable && !formatting.IsStructured(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	}
 
 	return nil
 }
@@ -114,14 +146,14 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	handleError(err)
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func printResult(cmd *cobra.Command, positionalArgs []string) {
 	output, err := getOutput(positionalArgs)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(output)
@@ -164,7 +196,7 @@ func fetchNodePool(args *Arguments) (*models.V5GetNodePoolResponse, error) {
 		return nil, microerror.Mask(err)
 	}
 
-	clusterID, err := clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+	clusterID, err := clustercache.GetIDCached(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
@@ -195,13 +227,13 @@ func getOutput(positionalArgs []string) (string, error) {
 	{
 		switch {
 		case nodePool.NodeSpec.Aws != nil:
-			output, err = getOutputAWS(nodePool)
+			output, err = getOutputAWS(nodePool, args.outputFormat)
 			if err != nil {
 				return "", microerror.Mask(err)
 			}
 
 		case nodePool.NodeSpec.Azure != nil:
-			output, err = getOutputAzure(nodePool)
+			output, err = getOutputAzure(nodePool, args.outputFormat)
 			if err != nil {
 				return "", microerror.Mask(err)
 			}
@@ -213,3 +245,20 @@ func getOutput(positionalArgs []string) (string, error) {
 
 	return output, nil
 }
+
+// getStructuredOutput renders the node pool and the given sums of CPU cores
+// and RAM in the given structured output format.
+func getStructuredOutput(outputFormat string, nodePool *models.V5GetNodePoolResponse, cpus *int64, memoryGB *float64) (string, error) {
+	output := nodePoolOutput{
+		V5GetNodePoolResponse: nodePool,
+		CPUs:                  cpus,
+		MemoryGB:              memoryGB,
+	}
+
+	outputBytes, err := formatting.Marshal(outputFormat, output)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return string(outputBytes), nil
+}
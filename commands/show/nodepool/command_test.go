@@ -1,6 +1,7 @@
 package nodepool
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/spf13/afero"
 
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -141,3 +144,63 @@ selected_endpoint: ` + mockServer.URL
 	}
 
 }
+
+// Test_ShowNodePoolStructuredOutput tests JSON output, which contains the
+// API fields plus the sums of CPUs and RAM.
+func Test_ShowNodePoolStructuredOutput(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acme"}]`))
+		case "/v5/clusters/cluster-id/nodepools/nodepool-id/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "nodepool-id",
+				"name": "Application servers",
+				"availability_zones": ["eu-west-1a"],
+				"scaling": {"min": 3, "max": 10},
+				"node_spec": {"aws": {"instance_type": "c5.large", "instance_distribution": {"on_demand_base_capacity": 0, "on_demand_percentage_above_base_capacity": 0}}},
+				"status": {"nodes": 3, "nodes_ready": 3}
+			}`))
+		default:
+			t.Errorf("Unsupported route %s called in mock server", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags.APIEndpoint = mockServer.URL
+	flags.Token = "some-token"
+	flags.OutputFormat = formatting.OutputFormatJSON
+	defer func() {
+		flags.APIEndpoint = ""
+		flags.Token = ""
+		flags.OutputFormat = ""
+	}()
+
+	output, err := getOutput([]string{"cluster-id/nodepool-id"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var nodePool map[string]interface{}
+	err = json.Unmarshal([]byte(output), &nodePool)
+	if err != nil {
+		t.Fatalf("Could not parse output as JSON: %s\n%s", err, output)
+	}
+
+	if nodePool["id"] != "nodepool-id" || nodePool["cpus"] != float64(6) || nodePool["memory_gb"] != float64(12) {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	if _, ok := nodePool["node_spec"]; !ok {
+		t.Errorf("Expected node_spec in output:\n%s", output)
+	}
+}
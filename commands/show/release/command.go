@@ -16,6 +16,7 @@ import (
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -29,6 +30,12 @@ var (
 Examples:
 
   gsctl show release 14.0.0
+  gsctl show release 14.0.0 --output json
+
+With --output json or --output yaml, the release is printed with the fields
+version, timestamp, active, kubernetes (version, end_of_life,
+end_of_life_date), components (name, version) and changelog (component,
+description).
 `,
 
 		// PreRun checks a few general things, like authentication.
@@ -45,9 +52,19 @@ const (
 	showReleaseActivityName = "show-release"
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowReleaseCommand.ResetFlags()
+	ShowReleaseCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
+	outputFormat      string
 	releaseVersion    string
 	scheme            string
 	userProvidedToken string
@@ -62,6 +79,7 @@ func collectArguments() Arguments {
 	return Arguments{
 		apiEndpoint:       endpoint,
 		authToken:         token,
+		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
 		releaseVersion:    "",
 		userProvidedToken: flags.Token,
@@ -82,7 +100,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 
 	// handle non-common errors
 	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func verifyShowReleasePreconditions(args Arguments, cmdLineArgs []string) error {
@@ -95,6 +113,9 @@ func verifyShowReleasePreconditions(args Arguments, cmdLineArgs []string) error
 	if len(cmdLineArgs) == 0 {
 		return microerror.Mask(errors.ReleaseVersionMissingError)
 	}
+	if args.outputFormat != "" && args.outputFormat != formatting.OutputFormatTable && !formatting.IsStructured(args.outputFormat) {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	}
 	return nil
 }
 
@@ -133,19 +154,30 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	clientWrapper, err := client.NewWithConfig(arguments.apiEndpoint, arguments.userProvidedToken)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	release, err := getReleaseDetails(clientWrapper, arguments)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	releaseData, err := getReleaseData(clientWrapper, *release.Version)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if formatting.IsStructured(arguments.outputFormat) {
+		output, err := getStructuredOutput(arguments.outputFormat, release, releaseData)
+		if err != nil {
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+
+		fmt.Println(output)
+		return
 	}
 
 	// success output
@@ -174,6 +206,49 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	}
 }
 
+// releaseOutput is the structure printed with '--output json' and
+// '--output yaml'. Next to the release details returned by the API, it
+// contains the end of life information for the Kubernetes version.
+type releaseOutput struct {
+	Version    string                                     `json:"version"`
+	Timestamp  string                                     `json:"timestamp"`
+	Active     bool                                       `json:"active"`
+	Kubernetes kubernetesOutput                           `json:"kubernetes"`
+	Components []*models.V4ReleaseListItemComponentsItems `json:"components"`
+	Changelog  []*models.V4ReleaseListItemChangelogItems  `json:"changelog"`
+}
+
+// kubernetesOutput describes the Kubernetes version of a release.
+type kubernetesOutput struct {
+	Version       string `json:"version,omitempty"`
+	EndOfLife     bool   `json:"end_of_life"`
+	EndOfLifeDate string `json:"end_of_life_date,omitempty"`
+}
+
+// getStructuredOutput renders the release details in the given structured
+// output format.
+func getStructuredOutput(outputFormat string, release *models.V4ReleaseListItem, releaseData releaseinfo.ReleaseData) (string, error) {
+	output := releaseOutput{
+		Version:    *release.Version,
+		Timestamp:  *release.Timestamp,
+		Active:     release.Active,
+		Components: release.Components,
+		Changelog:  release.Changelog,
+		Kubernetes: kubernetesOutput{
+			Version:       releaseData.K8sVersion,
+			EndOfLife:     releaseData.IsK8sVersionEOL,
+			EndOfLifeDate: releaseData.K8sVersionEOLDate,
+		},
+	}
+
+	outputBytes, err := formatting.Marshal(outputFormat, output)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return string(outputBytes), nil
+}
+
 func handleError(err error) {
 	client.HandleErrors(err)
 	errors.HandleCommonErrors(err)
@@ -6,10 +6,13 @@ import (
 	"testing"
 
 	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/gsctl/client"
 	"github.com/spf13/afero"
 
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/releaseinfo"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -405,3 +408,51 @@ func TestShowReleaseMissingID(t *testing.T) {
 	}
 
 }
+
+// Test_getStructuredOutput tests the YAML output of release details.
+func Test_getStructuredOutput(t *testing.T) {
+	version := "0.10.0"
+	timestamp := "2017-10-27T16:21:00Z"
+	componentName := "kubernetes"
+	componentVersion := "1.8.1"
+
+	release := &models.V4ReleaseListItem{
+		Version:   &version,
+		Timestamp: &timestamp,
+		Active:    true,
+		Components: []*models.V4ReleaseListItemComponentsItems{
+			{Name: &componentName, Version: &componentVersion},
+		},
+		Changelog: []*models.V4ReleaseListItemChangelogItems{
+			{Component: "kubernetes", Description: "Kubernetes version updated."},
+		},
+	}
+	releaseData := releaseinfo.ReleaseData{
+		Version:           version,
+		K8sVersion:        componentVersion,
+		IsK8sVersionEOL:   true,
+		K8sVersionEOLDate: "1960-01-01",
+	}
+
+	expected := `active: true
+changelog:
+- component: kubernetes
+  description: Kubernetes version updated.
+components:
+- name: kubernetes
+  version: 1.8.1
+kubernetes:
+  end_of_life: true
+  end_of_life_date: "1960-01-01"
+  version: 1.8.1
+timestamp: "2017-10-27T16:21:00Z"
+version: 0.10.0`
+
+	output, err := getStructuredOutput(formatting.OutputFormatYAML, release, releaseData)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if output != expected {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+}
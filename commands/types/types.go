@@ -1,19 +1,23 @@
 // Package types defines types to be used in several commands.
+//
+// The cluster definition types carry optional 'jsonschema' struct tags with
+// constraints (minimum, maximum, enum) which are used when generating the
+// JSON Schema for cluster definition files.
 package types
 
 // CPUDefinition defines worker node CPU specs.
 type CPUDefinition struct {
-	Cores int `yaml:"cores,omitempty"`
+	Cores int `yaml:"cores,omitempty" jsonschema:"minimum=0"`
 }
 
 // MemoryDefinition defines worker node memory specs.
 type MemoryDefinition struct {
-	SizeGB float32 `yaml:"size_gb,omitempty"`
+	SizeGB float32 `yaml:"size_gb,omitempty" jsonschema:"minimum=0"`
 }
 
 // StorageDefinition defines worker node storage specs.
 type StorageDefinition struct {
-	SizeGB float32 `yaml:"size_gb,omitempty"`
+	SizeGB float32 `yaml:"size_gb,omitempty" jsonschema:"minimum=0"`
 }
 
 // AWSSpecificDefinition defines worker node specs for AWS.
@@ -25,8 +29,8 @@ type AWSSpecificDefinition struct {
 
 // AWSInstanceDistribution defines the distribution between on-demand and spot instances.
 type AWSInstanceDistribution struct {
-	OnDemandBaseCapacity                int64 `yaml:"on_demand_base_capacity"`
-	OnDemandPercentageAboveBaseCapacity int64 `yaml:"on_demand_percentage_above_base_capacity"`
+	OnDemandBaseCapacity                int64 `yaml:"on_demand_base_capacity" jsonschema:"minimum=0"`
+	OnDemandPercentageAboveBaseCapacity int64 `yaml:"on_demand_percentage_above_base_capacity" jsonschema:"minimum=0,maximum=100"`
 }
 
 // AzureSpecificDefinition defines worker node specs for Azure.
@@ -56,14 +60,14 @@ type ClusterDefinitionV4 struct {
 	Name              string            `yaml:"name,omitempty"`
 	Owner             string            `yaml:"owner,omitempty"`
 	ReleaseVersion    string            `yaml:"release_version,omitempty"`
-	AvailabilityZones int               `yaml:"availability_zones,omitempty"`
+	AvailabilityZones int               `yaml:"availability_zones,omitempty" jsonschema:"minimum=0"`
 	Scaling           ScalingDefinition `yaml:"scaling,omitempty"`
 	Workers           []NodeDefinition  `yaml:"workers,omitempty"`
 }
 
 // ClusterDefinitionV5 defines a workload cluster spec compatible with the v5 API.
 type ClusterDefinitionV5 struct {
-	APIVersion     string                `yaml:"api_version,omitempty"`
+	APIVersion     string                `yaml:"api_version,omitempty" jsonschema:"enum=v5"`
 	Name           string                `yaml:"name,omitempty"`
 	Owner          string                `yaml:"owner,omitempty"`
 	ReleaseVersion string                `yaml:"release_version,omitempty"`
@@ -75,8 +79,8 @@ type ClusterDefinitionV5 struct {
 
 // ScalingDefinition defines how a workload cluster can scale.
 type ScalingDefinition struct {
-	Min int64 `yaml:"min,omitempty"`
-	Max int64 `yaml:"max,omitempty"`
+	Min *int64 `yaml:"min,omitempty" jsonschema:"minimum=0"`
+	Max int64  `yaml:"max,omitempty" jsonschema:"minimum=0"`
 }
 
 // MasterDefinition defines a master in cluster creation, as introduced by the V5 API.
@@ -97,7 +101,7 @@ type MasterNodesAzure struct {
 
 // AvailabilityZonesDefinition defines the availability zones for a node pool, as intgroduc ed in the V5 API.
 type AvailabilityZonesDefinition struct {
-	Number int64    `yaml:"number,omitempty"`
+	Number int64    `yaml:"number,omitempty" jsonschema:"minimum=0"`
 	Zones  []string `yaml:"zones,omitempty"`
 }
 
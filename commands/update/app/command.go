@@ -0,0 +1,270 @@
+// Package app implements the "update app" command.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/clustercache"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/precondition"
+)
+
+var (
+	// Command is the cobra command for 'gsctl update app'
+	Command = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>/<app-name>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Modify app details",
+		Long: `Change the chart version of an app installed in a cluster.
+
+Examples:
+
+  gsctl update app f01r4/nginx-ingress-controller --version 1.6.11
+
+  gsctl update app "Cluster name"/efk --version 0.3.0 --force
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "update-app"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.AppVersion, "version", "", "", "Chart version to update the app to.")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON or '%s' for YAML output, or '%s=<expression>' and '%s=<template>' to extract fields. Defaults to human-friendly output.", formatting.OutputFormatJSON, formatting.OutputFormatYAML, formatting.OutputFormatJSONPath, formatting.OutputFormatGoTemplate))
+}
+
+// Arguments represents all the ways the user can influence the command.
+type Arguments struct {
+	APIEndpoint       string
+	AppName           string
+	AuthToken         string
+	ClusterNameOrID   string
+	Force             bool
+	OutputFormat      string
+	UserProvidedToken string
+	Verbose           bool
+	Version           string
+}
+
+// JSONOutput is the structure printed when the command is called with JSON output.
+type JSONOutput struct {
+	// Result of the command. Should be 'updated'.
+	Result string `json:"result"`
+	// ClusterID is the ID of the cluster the app is installed in.
+	ClusterID string `json:"cluster_id,omitempty"`
+	// Name of the app.
+	Name string `json:"name,omitempty"`
+	// Version is the chart version the app has been updated to.
+	Version string `json:"version,omitempty"`
+	// Error which occurred.
+	Error *errors.Output `json:"error,omitempty"`
+}
+
+func collectArguments(positionalArgs []string) (Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	parts := strings.Split(positionalArgs[0], "/")
+	if len(parts) != 2 {
+		return Arguments{}, microerror.Mask(errors.InvalidAppArgumentError)
+	}
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AppName:           strings.TrimSpace(parts[1]),
+		AuthToken:         token,
+		ClusterNameOrID:   strings.TrimSpace(parts[0]),
+		Force:             flags.Force,
+		OutputFormat:      flags.OutputFormat,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+		Version:           flags.AppVersion,
+	}, nil
+}
+
+func verifyPreconditions(args Arguments) error {
+	err := precondition.VerifyApp(args.APIEndpoint, args.AuthToken, args.UserProvidedToken, args.ClusterNameOrID, args.AppName)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if args.Version == "" {
+		return microerror.Maskf(errors.NoOpError, "Nothing to update.")
+	}
+	if !formatting.IsStructured(args.OutputFormat) && args.OutputFormat != formatting.OutputFormatTable {
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.OutputFormat)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+	arguments, err = collectArguments(positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+	}
+
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// updateApp modifies the app's chart version. It returns the cluster ID
+// and true if the app has been modified, or false if the user aborted.
+func updateApp(args Arguments) (string, bool, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	existingApp, err := clientWrapper.GetApp(clusterID, args.AppName, auxParams)
+	if err != nil {
+		if clienterror.IsNotFoundError(err) {
+			return "", false, microerror.Mask(errors.ClusterNotFoundError)
+		}
+
+		return "", false, microerror.Mask(err)
+	}
+	if existingApp == nil {
+		return "", false, microerror.Mask(errors.AppNotFoundError)
+	}
+	if existingApp.Spec != nil && existingApp.Spec.Version == args.Version {
+		return "", false, microerror.Maskf(errors.NoOpError, "App '%s' already uses chart version %s.", args.AppName, args.Version)
+	}
+
+	if !args.Force && !formatting.IsStructured(args.OutputFormat) {
+		confirmed := confirm.Ask(fmt.Sprintf("Do you really want to update app '%s' in cluster '%s' to chart version %s?", args.AppName, args.ClusterNameOrID, args.Version))
+		if !confirmed {
+			return clusterID, false, nil
+		}
+	}
+
+	requestBody := &models.V4ModifyAppRequest{
+		Spec: &models.V4ModifyAppRequestSpec{
+			Version: args.Version,
+		},
+	}
+
+	_, err = clientWrapper.ModifyApp(clusterID, args.AppName, requestBody, auxParams)
+	if err != nil {
+		if clienterror.IsAccessForbiddenError(err) {
+			return "", false, microerror.Mask(errors.AccessForbiddenError)
+		}
+
+		return "", false, microerror.Mask(err)
+	}
+
+	return clusterID, true, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	clusterID, updated, err := updateApp(arguments)
+
+	if formatting.IsStructured(arguments.OutputFormat) {
+		printJSONOutput(clusterID, err)
+		return
+	}
+
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if updated {
+		fmt.Println(color.GreenString("App '%s' in cluster '%s' is being updated to chart version %s.", arguments.AppName, clusterID, arguments.Version))
+	} else if arguments.Verbose {
+		fmt.Println(color.WhiteString("Aborted."))
+	}
+}
+
+func printJSONOutput(clusterID string, updateErr error) {
+	var result JSONOutput
+	if updateErr != nil {
+		result = JSONOutput{Result: "error", Error: errors.NewOutput(updateErr)}
+	} else {
+		result = JSONOutput{Result: "updated", ClusterID: clusterID, Name: arguments.AppName, Version: arguments.Version}
+	}
+
+	outputBytes, err := formatting.Marshal(arguments.OutputFormat, result)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(string(outputBytes))
+	if updateErr != nil {
+		os.Exit(errors.ExitCode(updateErr))
+	}
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsInvalidAppArgument(err):
+		headline = "Bad format for cluster name/ID or app name argument"
+		subtext = "Please provide cluster name/ID and app name separated by a slash. See --help for examples."
+	case errors.IsAppNameMissingError(err):
+		headline = "No app name specified."
+		subtext = "Please provide cluster name/ID and app name separated by a slash. See --help for examples."
+	case errors.IsAppNotFound(err):
+		headline = "App not found"
+		subtext = fmt.Sprintf("Could not find an app named '%s' in this cluster. Check 'gsctl list apps' to make sure.", arguments.AppName)
+	case errors.IsNoOpError(err):
+		headline = microerror.Pretty(err, false)
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
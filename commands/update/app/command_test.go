@@ -0,0 +1,123 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+func newMockServer(t *testing.T, requestBody *models.V4ModifyAppRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acme"}]`))
+
+		case r.Method == "GET" && r.URL.Path == "/v4/clusters/cluster-id/apps/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"metadata": {"name": "efk"}, "spec": {"catalog": "giantswarm", "name": "efk-stack-app", "namespace": "logging", "version": "0.2.0"}}]`))
+
+		case r.Method == "PATCH" && r.URL.Path == "/v4/clusters/cluster-id/apps/efk/":
+			bodyBytes, _ := ioutil.ReadAll(r.Body)
+			err := json.Unmarshal(bodyBytes, requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"metadata": {"name": "efk"}, "spec": {"version": "0.3.0"}}`))
+
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+}
+
+// TestUpdateApp tests updating an app's chart version.
+func TestUpdateApp(t *testing.T) {
+	requestBody := &models.V4ModifyAppRequest{}
+	mockServer := newMockServer(t, requestBody)
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:     mockServer.URL,
+		AppName:         "efk",
+		AuthToken:       "token",
+		ClusterNameOrID: "Name of the cluster",
+		Force:           true,
+		OutputFormat:    "table",
+		Version:         "0.3.0",
+	}
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterID, updated, err := updateApp(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated || clusterID != "cluster-id" {
+		t.Errorf("Expected app in 'cluster-id' to be updated, got updated=%t, cluster ID %q", updated, clusterID)
+	}
+	if requestBody.Spec == nil || requestBody.Spec.Version != "0.3.0" {
+		t.Errorf("Unexpected request body %#v", requestBody)
+	}
+}
+
+// TestUpdateAppFailures tests cases where updating an app must fail.
+func TestUpdateAppFailures(t *testing.T) {
+	mockServer := newMockServer(t, &models.V4ModifyAppRequest{})
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			args:         Arguments{APIEndpoint: mockServer.URL, AppName: "efk", AuthToken: "token", ClusterNameOrID: "cluster-id", Force: true, OutputFormat: "table", Version: "0.2.0"},
+			errorMatcher: errors.IsNoOpError,
+		},
+		{
+			args:         Arguments{APIEndpoint: mockServer.URL, AppName: "non-existing", AuthToken: "token", ClusterNameOrID: "cluster-id", Force: true, OutputFormat: "table", Version: "0.3.0"},
+			errorMatcher: errors.IsAppNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		_, _, err := updateApp(tc.args)
+		if err == nil {
+			t.Errorf("Case %d - Expected error, got nil", i)
+		} else if !tc.errorMatcher(err) {
+			t.Errorf("Case %d - Error did not match expected type. Got '%s'", i, err)
+		}
+	}
+
+	err = verifyPreconditions(Arguments{APIEndpoint: mockServer.URL, AppName: "efk", AuthToken: "token", ClusterNameOrID: "cluster-id", OutputFormat: "table"})
+	if !errors.IsNoOpError(err) {
+		t.Errorf("Expected NoOpError, got %#v", err)
+	}
+}
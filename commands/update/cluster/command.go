@@ -77,15 +77,19 @@ type Arguments struct {
 	Verbose           bool
 }
 
-func collectArguments(positionalArgs []string) Arguments {
+func collectArguments(cmd *cobra.Command, positionalArgs []string) Arguments {
 	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
 	token := config.Config.ChooseToken(endpoint, flags.Token)
 
+	// flags.MasterHA is shared with 'create cluster', where it defaults to
+	// true, so it is only used here if the flag has been given.
+	masterHA := cmd.Flag("master-ha").Changed && flags.MasterHA
+
 	return Arguments{
 		APIEndpoint:       endpoint,
 		AuthToken:         token,
 		ClusterNameOrID:   strings.TrimSpace(positionalArgs[0]),
-		MasterHA:          flags.MasterHA,
+		MasterHA:          masterHA,
 		Labels:            flags.Label,
 		Name:              flags.Name,
 		UserProvidedToken: flags.Token,
@@ -117,7 +121,7 @@ func verifyPreconditions(cmd *cobra.Command, args Arguments) error {
 }
 
 func printValidation(cmd *cobra.Command, positionalArgs []string) {
-	arguments = collectArguments(positionalArgs)
+	arguments = collectArguments(cmd, positionalArgs)
 	err := verifyPreconditions(cmd, arguments)
 
 	if err == nil {
@@ -150,7 +154,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		fmt.Println(subtext)
 	}
 
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func updateCluster(args Arguments) (*result, error) {
@@ -231,6 +235,7 @@ func updateClusterSpec(args Arguments) (*result, error) {
 		{
 			if args.Name != "" {
 				r.ClusterName = response.Payload.Name
+				clustercache.CacheCluster(args.APIEndpoint, clustercache.Cluster{ID: clusterID, Name: response.Payload.Name})
 			}
 			if args.MasterHA {
 				r.HasHAMaster = response.Payload.MasterNodes.HighAvailability
@@ -266,6 +271,7 @@ func updateClusterSpec(args Arguments) (*result, error) {
 		r := &result{
 			ClusterName: response.Payload.Name,
 		}
+		clustercache.CacheCluster(args.APIEndpoint, clustercache.Cluster{ID: clusterID, Name: response.Payload.Name})
 
 		return r, nil
 	}
@@ -358,7 +364,7 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(color.GreenString("Cluster '%s' has been modified.", arguments.ClusterNameOrID))
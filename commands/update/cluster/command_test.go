@@ -70,7 +70,7 @@ func TestCollectArgs(t *testing.T) {
 	for i, tc := range testCases {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			tc.commandExecution()
-			args := collectArguments(tc.positionalArguments)
+			args := collectArguments(Command, tc.positionalArguments)
 
 			if diff := cmp.Diff(tc.resultingArgs, args); diff != "" {
 				t.Errorf("Case %d - Resulting args unequal. (-expected +got):\n%s", i, diff)
@@ -158,7 +158,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	handleError(err)
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func updateNodePool(args Arguments) (*result, error) {
@@ -220,7 +220,7 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 	r, err := updateNodePool(arguments)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(color.GreenString("Node pool '%s' (ID '%s') in cluster '%s' has been modified.", r.NodePool.Name, r.NodePool.ID, arguments.ClusterNameOrID))
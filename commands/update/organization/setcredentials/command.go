@@ -166,7 +166,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 	if subtext != "" {
 		fmt.Println(subtext)
 	}
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func verifyPreconditions(args Arguments) error {
@@ -304,7 +304,7 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	// success
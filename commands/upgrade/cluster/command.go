@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/fatih/color"
@@ -19,6 +20,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/confirm"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -29,6 +31,9 @@ const (
 	upgradeDocsURL = "https://docs.giantswarm.io/general/cluster-upgrades/"
 )
 
+// waitInterval is the time between two status requests when waiting.
+var waitInterval = clusterwait.DefaultInterval
+
 var (
 	// Command performs the "upgrade cluster" function
 	Command = &cobra.Command{
@@ -56,6 +61,12 @@ Example:
   gsctl upgrade cluster 6iec4
   gsctl upgrade cluster "Cluster name"
   gsctl upgrade cluster "Cluster name" --release "13.0.0"
+
+To wait until the cluster status reports the new release version and all
+nodes are ready again, use --wait. If this doesn't happen within
+--wait-timeout, the command fails with a non-zero exit code.
+
+  gsctl upgrade cluster 6iec4 --force --wait --wait-timeout 1h
 `),
 
 		// We use PreRun for general input validation, authentication etc.
@@ -80,6 +91,8 @@ type Arguments struct {
 	Release           string
 	UserProvidedToken string
 	Verbose           bool
+	Wait              bool
+	WaitTimeout       time.Duration
 }
 
 // function to create arguments based on command line flags and config
@@ -91,6 +104,9 @@ func collectArguments(positionalArgs []string) Arguments {
 		clusterID = positionalArgs[0]
 	}
 
+	// In dry run mode, nothing changes, so there is nothing to wait for.
+	wait := flags.Wait && flags.DryRun == ""
+
 	return Arguments{
 		APIEndpoint:       endpoint,
 		AuthToken:         token,
@@ -99,6 +115,8 @@ func collectArguments(positionalArgs []string) Arguments {
 		Release:           flags.Release,
 		UserProvidedToken: flags.Token,
 		Verbose:           flags.Verbose,
+		Wait:              wait,
+		WaitTimeout:       flags.WaitTimeout,
 	}
 }
 
@@ -117,6 +135,8 @@ func initFlags() {
 
 	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
 	Command.Flags().StringVarP(&flags.Release, "release", "", "", "The target release version for the upgrade. If no version is specified, the first version following the running one is selected..")
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the cluster has been upgraded and all nodes are ready.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "wait-timeout", "", clusterwait.DefaultTimeout, "Maximum time to wait when using --wait, e. g. '1h'.")
 }
 
 // Prints results of our pre-validation
@@ -150,7 +170,7 @@ func upgradeClusterValidationOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -208,12 +228,60 @@ func upgradeClusterExecutionOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		if subtext != "" {
 			fmt.Println(subtext)
 		}
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(color.GreenString("Starting to upgrade cluster '%s' to release version %s",
 		result.clusterID,
 		result.versionAfter))
+
+	if arguments.Wait {
+		fmt.Printf("Waiting up to %s for the upgrade to complete\n", arguments.WaitTimeout)
+
+		err = waitForUpgrade(arguments, result)
+		if err != nil {
+			client.HandleErrors(err)
+			errors.HandleCommonErrors(err)
+
+			if clusterwait.IsTimeout(err) {
+				fmt.Println(color.RedString("Timed out waiting for the upgrade to complete"))
+				fmt.Printf("Details: %s\n", err.Error())
+			} else {
+				fmt.Println(color.RedString(err.Error()))
+			}
+			os.Exit(errors.ExitCode(err))
+		}
+
+		fmt.Println(color.GreenString("Cluster '%s' has been upgraded to release version %s.", result.clusterID, result.versionAfter))
+	}
+}
+
+// waitForUpgrade waits until the cluster status reports the target release
+// version and all nodes are ready.
+func waitForUpgrade(args Arguments, result *upgradeClusterResult) error {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = upgradeClusterActivityName
+
+	_, err = clusterwait.UntilReady(clusterwait.Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     result.clusterID,
+		Timeout:       args.WaitTimeout,
+		Interval:      waitInterval,
+		Progress: func(status string) {
+			fmt.Println(status)
+		},
+	}, result.versionAfter)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
 }
 
 // upgradeCluster performs our actual function. It usually creates an API client,
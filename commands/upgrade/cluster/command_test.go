@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/gabs"
 	"github.com/giantswarm/gscliauth/config"
@@ -12,6 +13,7 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -184,6 +186,7 @@ func Test_collectArguments(t *testing.T) {
 				ClusterNameOrID: "clusterid",
 				Force:           true,
 				Release:         "",
+				WaitTimeout:     clusterwait.DefaultTimeout,
 			},
 		},
 		{
@@ -200,6 +203,24 @@ func Test_collectArguments(t *testing.T) {
 				ClusterNameOrID: "clusterid",
 				Release:         "1.2.3",
 				Force:           false,
+				WaitTimeout:     clusterwait.DefaultTimeout,
+			},
+		},
+		{
+			name:                "Test 3: Wait with timeout",
+			positionalArguments: []string{"clusterid"},
+			commandExecution: func() {
+				initFlags()
+				Command.ParseFlags([]string{
+					"clusterid",
+					"--wait",
+					"--wait-timeout=1h",
+				})
+			},
+			resultingArgs: Arguments{
+				ClusterNameOrID: "clusterid",
+				Wait:            true,
+				WaitTimeout:     time.Hour,
 			},
 		},
 	}
@@ -0,0 +1,224 @@
+// Package cluster implements the 'wait cluster' command.
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+)
+
+var (
+	// Command is the cobra command for 'gsctl wait cluster'
+	Command = &cobra.Command{
+		Use: "cluster <cluster-name/cluster-id>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Wait for a cluster to reach a condition",
+		Long: `Waits until a cluster reaches the condition given via --for.
+
+Conditions:
+
+  condition=<type>   The cluster status has a condition of the given type
+                     which is true, e. g. Created, Updated or Deleting.
+  version=<version>  The cluster status lists the given release version,
+                     e. g. after an upgrade.
+  nodes-ready=<n>    At least n worker nodes are ready, summed up over all
+                     node pools.
+  nodes-ready=min    All master nodes are ready, and every node pool has at
+                     least its minimum number of ready worker nodes.
+  deleted            The cluster does not exist any more.
+
+Progress is printed whenever the status changes. If the condition is not
+met within --wait-timeout, the command fails with a non-zero exit code.
+
+Examples:
+
+  gsctl wait cluster f01r4 --for condition=Created
+
+  gsctl wait cluster "Cluster name" --for nodes-ready=5 --wait-timeout 45m
+
+  gsctl wait cluster f01r4 --for deleted
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "wait-cluster"
+)
+
+// waitInterval is the time between two status requests.
+var waitInterval = clusterwait.DefaultInterval
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.WaitFor, "for", "", "", "Condition to wait for, e. g. 'condition=Created', 'nodes-ready=5', 'nodes-ready=min' or 'deleted'.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "wait-timeout", "", clusterwait.DefaultTimeout, "Maximum time to wait, e. g. '45m'.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	ClusterNameOrID   string
+	Condition         string
+	Timeout           time.Duration
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(positionalArgs []string) Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		ClusterNameOrID:   positionalArgs[0],
+		Condition:         flags.WaitFor,
+		Timeout:           flags.WaitTimeout,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.ClusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+
+	_, err := clusterwait.ParseCondition(args.Condition)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments(positionalArgs)
+
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	clusterID, err := waitForCluster(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(color.GreenString("Cluster '%s' has reached the condition '%s'.", clusterID, arguments.Condition))
+}
+
+// waitForCluster blocks until the cluster has reached the condition,
+// printing progress, and returns the cluster ID.
+func waitForCluster(args Arguments) (string, error) {
+	condition, err := clusterwait.ParseCondition(args.Condition)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetIDCached(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if condition.Type == clusterwait.ConditionTypeDeleted && errors.IsClusterNotFoundError(err) {
+		// Already gone.
+		return args.ClusterNameOrID, nil
+	} else if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	if args.Verbose {
+		fmt.Println(color.WhiteString("Waiting up to %s for cluster '%s' to reach the condition '%s'", args.Timeout, clusterID, condition))
+	}
+
+	err = clusterwait.UntilCluster(clusterwait.Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     clusterID,
+		Timeout:       args.Timeout,
+		Interval:      waitInterval,
+		Progress: func(status string) {
+			fmt.Println(status)
+		},
+	}, condition)
+	if err != nil {
+		return clusterID, microerror.Mask(err)
+	}
+
+	return clusterID, nil
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case clusterwait.IsInvalidCondition(err):
+		headline = "Invalid condition"
+		subtext = fmt.Sprintf("%s\nPlease specify what to wait for via --for. See --help for details.", err.Error())
+	case errors.IsClusterNameOrIDMissingError(err):
+		headline = "No cluster name or ID specified"
+		subtext = "See --help for usage details."
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = fmt.Sprintf("Could not find a cluster '%s' to wait for. Please check whether the cluster is listed when executing 'gsctl list clusters'.", arguments.ClusterNameOrID)
+	case clusterwait.IsTimeout(err):
+		headline = "Timed out"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
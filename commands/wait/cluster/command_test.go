@@ -0,0 +1,134 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// Test_verifyPreconditions tests cases where validating preconditions fails.
+func Test_verifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		// No token provided.
+		{
+			Arguments{
+				APIEndpoint:     "https://mock-url",
+				ClusterNameOrID: "f01r4",
+				Condition:       "deleted",
+			},
+			errors.IsNotLoggedInError,
+		},
+		// No condition given.
+		{
+			Arguments{
+				APIEndpoint:     "https://mock-url",
+				AuthToken:       "token",
+				ClusterNameOrID: "f01r4",
+			},
+			clusterwait.IsInvalidCondition,
+		},
+		// Unknown condition.
+		{
+			Arguments{
+				APIEndpoint:     "https://mock-url",
+				AuthToken:       "token",
+				ClusterNameOrID: "f01r4",
+				Condition:       "healthy",
+			},
+			clusterwait.IsInvalidCondition,
+		},
+	}
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Unexpected error %#v", i, err)
+			}
+		})
+	}
+}
+
+// Test_waitForCluster tests waiting for a cluster condition, and that
+// waiting for the deletion of a cluster which doesn't exist succeeds
+// right away.
+func Test_waitForCluster(t *testing.T) {
+	polls := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "Name of the cluster", "owner": "acme"}]`))
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/f01r4/status/":
+			polls++
+			condition := "Creating"
+			if polls > 1 {
+				condition = "Created"
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"cluster": {"conditions": [{"status": "True", "type": "` + condition + `"}]}}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/f01r4/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "f01r4"}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitInterval = time.Millisecond
+	defer func() { waitInterval = clusterwait.DefaultInterval }()
+
+	args := Arguments{
+		APIEndpoint:     mockServer.URL,
+		AuthToken:       "token",
+		ClusterNameOrID: "Name of the cluster",
+		Condition:       "condition=Created",
+		Timeout:         5 * time.Second,
+	}
+
+	clusterID, err := waitForCluster(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if clusterID != "f01r4" || polls != 2 {
+		t.Errorf("Expected cluster f01r4 after 2 polls, got %s after %d polls", clusterID, polls)
+	}
+
+	args.ClusterNameOrID = "Deleted cluster"
+	args.Condition = "deleted"
+	_, err = waitForCluster(args)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,23 @@
+package wait
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/wait/cluster"
+	"github.com/giantswarm/gsctl/commands/wait/nodepool"
+)
+
+var (
+	// Command is the command to wait for clusters and node pools
+	Command = &cobra.Command{
+		Use:   "wait",
+		Short: "Wait for a cluster or node pool to reach a condition",
+		Long: `Blocks until a cluster or node pool reaches the given condition, or until
+the timeout has passed. Useful as a barrier between steps in scripts.`,
+	}
+)
+
+func init() {
+	Command.AddCommand(cluster.Command)
+	Command.AddCommand(nodepool.Command)
+}
@@ -0,0 +1,254 @@
+// Package nodepool implements the 'wait nodepool' command.
+package nodepool
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+)
+
+var (
+	// Command is the cobra command for 'gsctl wait nodepool'
+	Command = &cobra.Command{
+		Use:     "nodepool <cluster-name/cluster-id> <nodepool-id>",
+		Aliases: []string{"np"},
+		Args:    cobra.RangeArgs(1, 2),
+		Short:   "Wait for a node pool to reach a condition",
+		Long: `Waits until a node pool reaches the condition given via --for.
+
+The node pool can be given as two arguments, or as one argument in the form
+<cluster-name/cluster-id>/<nodepool-id>, like in other node pool commands.
+
+Conditions:
+
+  nodes-ready=<n>    At least n worker nodes of the node pool are ready.
+  nodes-ready=min    At least the minimum number of worker nodes, according
+                     to the node pool's scaling settings, are ready.
+  deleted            The node pool does not exist any more.
+
+Progress is printed whenever the status changes. If the condition is not
+met within --wait-timeout, the command fails with a non-zero exit code.
+
+Examples:
+
+  gsctl wait nodepool f01r4 75rh1 --for nodes-ready=min
+
+  gsctl wait nodepool "Cluster name"/75rh1 --for nodes-ready=3 --wait-timeout 20m
+
+  gsctl wait nodepool f01r4 75rh1 --for deleted
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments *Arguments
+)
+
+const (
+	activityName = "wait-nodepool"
+)
+
+// waitInterval is the time between two status requests.
+var waitInterval = clusterwait.DefaultInterval
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.WaitFor, "for", "", "", "Condition to wait for, e. g. 'nodes-ready=min', 'nodes-ready=3' or 'deleted'.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "wait-timeout", "", clusterwait.DefaultTimeout, "Maximum time to wait, e. g. '20m'.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	ClusterNameOrID   string
+	Condition         string
+	NodePoolID        string
+	Timeout           time.Duration
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(positionalArgs []string) (*Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	var clusterNameOrID, nodePoolID string
+	if len(positionalArgs) == 2 {
+		clusterNameOrID = positionalArgs[0]
+		nodePoolID = positionalArgs[1]
+	} else {
+		parts := strings.Split(positionalArgs[0], "/")
+		if len(parts) < 2 {
+			return nil, microerror.Maskf(errors.InvalidNodePoolIDArgumentError, "Please specify the node pool as <cluster-name/cluster-id> <nodepool-id>. Use --help for details.")
+		}
+		clusterNameOrID = parts[0]
+		nodePoolID = parts[1]
+	}
+
+	return &Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		ClusterNameOrID:   clusterNameOrID,
+		Condition:         flags.WaitFor,
+		NodePoolID:        nodePoolID,
+		Timeout:           flags.WaitTimeout,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}, nil
+}
+
+func verifyPreconditions(args *Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.ClusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+	if args.NodePoolID == "" {
+		return microerror.Mask(errors.NodePoolIDMissingError)
+	}
+
+	condition, err := clusterwait.ParseCondition(args.Condition)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = condition.ValidateForNodePool()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+
+	arguments, err = collectArguments(positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+		if err == nil {
+			return
+		}
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	err := waitForNodePool(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(color.GreenString("Node pool '%s' of cluster '%s' has reached the condition '%s'.", arguments.NodePoolID, arguments.ClusterNameOrID, arguments.Condition))
+}
+
+// waitForNodePool blocks until the node pool has reached the condition,
+// printing progress.
+func waitForNodePool(args *Arguments) error {
+	condition, err := clusterwait.ParseCondition(args.Condition)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetIDCached(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if condition.Type == clusterwait.ConditionTypeDeleted && errors.IsClusterNotFoundError(err) {
+		// The node pool is gone together with its cluster.
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	if args.Verbose {
+		fmt.Println(color.WhiteString("Waiting up to %s for node pool '%s' to reach the condition '%s'", args.Timeout, args.NodePoolID, condition))
+	}
+
+	err = clusterwait.UntilNodePool(clusterwait.Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     clusterID,
+		Timeout:       args.Timeout,
+		Interval:      waitInterval,
+		Progress: func(status string) {
+			fmt.Println(status)
+		},
+	}, args.NodePoolID, condition)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func handleError(err error) {
+	client.HandleErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case clusterwait.IsInvalidCondition(err):
+		headline = "Invalid condition"
+		subtext = fmt.Sprintf("%s\nPlease specify what to wait for via --for. See --help for details.", err.Error())
+	case errors.IsInvalidNodePoolIDArgument(err):
+		headline = "Invalid argument syntax"
+		subtext = "Please give the cluster name or ID and the node pool ID as arguments. See --help for details."
+	case errors.IsClusterNameOrIDMissingError(err):
+		headline = "No cluster name or ID specified"
+		subtext = "See --help for usage details."
+	case errors.IsNodePoolIDMissingError(err):
+		headline = "No node pool ID specified"
+		subtext = "See --help for usage details."
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = fmt.Sprintf("Could not find a cluster '%s'. Please check whether the cluster is listed when executing 'gsctl list clusters'.", arguments.ClusterNameOrID)
+	case clusterwait.IsTimeout(err):
+		headline = "Timed out"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
@@ -0,0 +1,198 @@
+package nodepool
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/clusterwait"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// configYAML is a mock configuration used by some of the tests.
+const configYAML = `last_version_check: 0001-01-01T00:00:00Z
+endpoints:
+  https://foo:
+    email: email@example.com
+    token: some-token
+selected_endpoint: https://foo
+updated: 2017-09-29T11:23:15+02:00
+`
+
+// TestCollectArgs tests whether collectArguments produces the expected results.
+func TestCollectArgs(t *testing.T) {
+	var testCases = []struct {
+		positionalArguments []string
+		flags               []string
+		resultingArgs       *Arguments
+		errorMatcher        func(error) bool
+	}{
+		{
+			[]string{"clusterid", "nodepoolid"},
+			[]string{"--for", "nodes-ready=min"},
+			&Arguments{
+				APIEndpoint:     "https://foo",
+				AuthToken:       "some-token",
+				ClusterNameOrID: "clusterid",
+				Condition:       "nodes-ready=min",
+				NodePoolID:      "nodepoolid",
+				Timeout:         clusterwait.DefaultTimeout,
+			},
+			nil,
+		},
+		{
+			[]string{"clusterid/nodepoolid"},
+			[]string{"--for", "deleted", "--wait-timeout", "5m"},
+			&Arguments{
+				APIEndpoint:     "https://foo",
+				AuthToken:       "some-token",
+				ClusterNameOrID: "clusterid",
+				Condition:       "deleted",
+				NodePoolID:      "nodepoolid",
+				Timeout:         5 * time.Minute,
+			},
+			nil,
+		},
+		{
+			[]string{"string-without-slash"},
+			[]string{"--for", "deleted"},
+			nil,
+			errors.IsInvalidNodePoolIDArgument,
+		},
+	}
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			initFlags()
+			Command.ParseFlags(append(tc.positionalArguments, tc.flags...))
+
+			args, err := collectArguments(tc.positionalArguments)
+			if err != nil {
+				if tc.errorMatcher == nil {
+					t.Errorf("Case %d - Unexpected error '%s'", i, err)
+				} else if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error of unexpected type: '%s'", i, err)
+				}
+			} else if tc.errorMatcher != nil {
+				t.Errorf("Case %d - Expected error but got nil", i)
+			}
+			if diff := cmp.Diff(tc.resultingArgs, args); diff != "" {
+				t.Errorf("Case %d - Resulting args unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+// Test_verifyPreconditions tests cases where validating preconditions fails.
+func Test_verifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         *Arguments
+		errorMatcher func(error) bool
+	}{
+		// Node pool ID is missing.
+		{
+			&Arguments{
+				AuthToken:       "token",
+				APIEndpoint:     "https://mock-url",
+				ClusterNameOrID: "abc",
+				Condition:       "deleted",
+			},
+			errors.IsNodePoolIDMissingError,
+		},
+		// No condition given.
+		{
+			&Arguments{
+				AuthToken:       "token",
+				APIEndpoint:     "https://mock-url",
+				ClusterNameOrID: "abc",
+				NodePoolID:      "def",
+			},
+			clusterwait.IsInvalidCondition,
+		},
+		// Condition not supported for node pools.
+		{
+			&Arguments{
+				AuthToken:       "token",
+				APIEndpoint:     "https://mock-url",
+				ClusterNameOrID: "abc",
+				NodePoolID:      "def",
+				Condition:       "condition=Created",
+			},
+			clusterwait.IsInvalidCondition,
+		},
+	}
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Unexpected error %#v", i, err)
+			}
+		})
+	}
+}
+
+// Test_waitForNodePool tests waiting for the nodes of a node pool.
+func Test_waitForNodePool(t *testing.T) {
+	polls := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "Name of the cluster", "owner": "acme"}]`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/f01r4/nodepools/a7k4/":
+			polls++
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "a7k4", "scaling": {"min": 3, "max": 5}, "status": {"nodes_ready": ` + strconv.Itoa(polls) + `}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitInterval = time.Millisecond
+	defer func() { waitInterval = clusterwait.DefaultInterval }()
+
+	err = waitForNodePool(&Arguments{
+		APIEndpoint:     mockServer.URL,
+		AuthToken:       "token",
+		ClusterNameOrID: "Name of the cluster",
+		Condition:       "nodes-ready=min",
+		NodePoolID:      "a7k4",
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if polls != 3 {
+		t.Errorf("Expected 3 polls, got %d", polls)
+	}
+}
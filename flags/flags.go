@@ -1,9 +1,23 @@
 package flags
 
+import "time"
+
 var (
 	// APIEndpoint represents the API endpoint URL flag.
 	APIEndpoint string
 
+	// AppCatalog is the name of the catalog to install an app from.
+	AppCatalog string
+
+	// AppChart is the name of the chart to use for installing an app.
+	AppChart string
+
+	// AppNamespace is the namespace an app gets installed to.
+	AppNamespace string
+
+	// AppVersion is the chart version to install or update an app to.
+	AppVersion string
+
 	// AvailabilityZones is the number of availability zones to use.
 	AvailabilityZones int
 
@@ -32,6 +46,9 @@ var (
 	// command to use the workload-cluster-internal API endpoint instead of the public one.
 	InternalAPI bool
 
+	// Trace enables logging all HTTP requests and responses.
+	Trace bool
+
 	// Verbose represents the verbosity switch passed as a flag.
 	Verbose bool
 
@@ -47,16 +64,31 @@ var (
 	// CNPrefix represents the CN prefix passed as a flag.
 	CNPrefix string
 
+	// Concurrency is the maximum number of operations to run in parallel.
+	Concurrency int
+
 	// CreateDefaultNodePool defines whether a default node pool should be created
 	// in the case that none was defined in the cluster definition.
 	CreateDefaultNodePool bool
 
+	// DeleteNodePools defines whether node pools not mentioned in a cluster
+	// definition should be deleted when applying the definition.
+	DeleteNodePools bool
+
+	// DryRun is the format ("json" or "yaml") in which request bodies are printed
+	// instead of sending them. If empty, requests are sent.
+	DryRun string
+
 	// Description represents the description passed as a flag.
 	Description string
 
 	// Use spot instances for a node pool
 	EnableSpotInstances bool
 
+	// Filter contains conditions list items have to meet, like
+	// 'release>=12.0.0', passed as multiple flags.
+	Filter []string
+
 	// Force represents the value of the force flag, passed as a flag.
 	// If true, all warnings should be suppressed.
 	Force bool
@@ -76,6 +108,9 @@ var (
 	// Name is the name of a cluster or node pool.
 	Name string
 
+	// ListenAddress is the address to serve on, like 'localhost:8080'.
+	ListenAddress string
+
 	// NumWorkers is the number of workers required via flag on execution.
 	NumWorkers int
 
@@ -88,12 +123,47 @@ var (
 	// Owner is the owner organization of the cluster as set via flag on execution.
 	Owner string
 
+	// NoCache disables the cache for API responses like the installation info.
+	NoCache bool
+
+	// NoColor disables colored output.
+	NoColor bool
+
+	// Provider is the provider ('aws', 'azure' or 'kvm') to simulate.
+	Provider string
+
+	// PrintSchema is the cluster definition version ("v4" or "v5") for which
+	// the JSON Schema should be printed.
+	PrintSchema string
+
+	// ReplayDir is a directory with fixture files to serve API responses from.
+	ReplayDir string
+
+	// RequestTimeout is the maximum time to wait for an API request,
+	// including retries.
+	RequestTimeout time.Duration
+
+	// Retries is the number of times an API request failing for a temporary
+	// reason is repeated.
+	Retries int
+
+	// RecordDir is a directory to store API interactions in as fixture files.
+	RecordDir string
+
 	// Release sets a release to use, provided as a command line flag.
 	Release string
 
+	// SetValues are overrides for cluster definition values, like
+	// 'nodepools[0].scaling.max=10'.
+	SetValues []string
+
 	// SilenceHTTPEndpointWarning represents
 	SilenceHTTPEndpointWarning bool
 
+	// Sort is a comma separated list of fields to sort list output by,
+	// like 'release,-created'.
+	Sort string
+
 	// MasterHA enables or disabled master node high availability.
 	MasterHA bool
 
@@ -107,6 +177,25 @@ var (
 	// TTL represents a TTL (time to live) value passed as a flag.
 	TTL string
 
+	// ValuesFile is the path to a YAML file with variables to substitute
+	// in a cluster definition.
+	ValuesFile string
+
+	// Wait makes commands wait until a cluster has reached the desired state.
+	Wait bool
+
+	// Watch makes list and show commands poll for changes until interrupted.
+	Watch bool
+
+	// WatchInterval is the time between two polls when Watch is set.
+	WatchInterval time.Duration
+
+	// WaitFor is the condition expression to wait for, e. g. 'condition=Created'.
+	WaitFor string
+
+	// WaitTimeout is the maximum time to wait when Wait is set.
+	WaitTimeout time.Duration
+
 	// WorkerAwsEc2InstanceType is the instance type name for nodes in AWS.
 	WorkerAwsEc2InstanceType string
 
@@ -0,0 +1,13 @@
+package formatting
+
+import "github.com/giantswarm/microerror"
+
+var invalidTemplateError = &microerror.Error{
+	Kind: "invalidTemplateError",
+	Desc: "The JSONPath expression or Go template given as output format is invalid.",
+}
+
+// IsInvalidTemplate asserts invalidTemplateError.
+func IsInvalidTemplate(err error) bool {
+	return microerror.Cause(err) == invalidTemplateError
+}
@@ -1,13 +1,76 @@
 package formatting
 
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/giantswarm/microerror"
+	yaml "gopkg.in/yaml.v2"
+)
+
 const (
 	// OutputFormatJSON contains the string value to enable JSON formatted output
 	OutputFormatJSON = "json"
 	// OutputFormatTable contains the string value to enable table formatted output
 	OutputFormatTable = "table"
+	// OutputFormatYAML contains the string value to enable YAML formatted output
+	OutputFormatYAML = "yaml"
 
 	// OutputJSONPrefix is the prefix for json formatted output
 	OutputJSONPrefix = ""
 	// OutputJSONIndent is the intendation for json formatted output
 	OutputJSONIndent = "  "
 )
+
+// IsStructured returns true if the output format is meant to be consumed
+// by machines, i. e. JSON, YAML, a JSONPath expression or a Go template.
+func IsStructured(outputFormat string) bool {
+	return outputFormat == OutputFormatJSON || outputFormat == OutputFormatYAML || isTemplate(outputFormat)
+}
+
+// Marshal renders data in the given structured output format. YAML output,
+// JSONPath expressions and Go templates are based on the JSON
+// representation, so all formats share the same field names and structure.
+// The result has no trailing line break.
+func Marshal(outputFormat string, data interface{}) ([]byte, error) {
+	jsonBytes, err := json.MarshalIndent(data, OutputJSONPrefix, OutputJSONIndent)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if isTemplate(outputFormat) {
+		out, err := renderTemplate(outputFormat, jsonBytes)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		return out, nil
+	}
+
+	if outputFormat != OutputFormatYAML {
+		return jsonBytes, nil
+	}
+
+	yamlBytes, err := JSONToYAML(jsonBytes)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return yamlBytes, nil
+}
+
+// JSONToYAML converts a JSON document to YAML, without trailing line break.
+func JSONToYAML(jsonBytes []byte) ([]byte, error) {
+	var data interface{}
+	err := json.Unmarshal(jsonBytes, &data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	yamlBytes, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return bytes.TrimSpace(yamlBytes), nil
+}
@@ -0,0 +1,92 @@
+package formatting
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	type item struct {
+		ID      string   `json:"id"`
+		Name    string   `json:"name,omitempty"`
+		Workers []string `json:"workers"`
+	}
+
+	testCases := []struct {
+		format string
+		data   interface{}
+		output string
+	}{
+		{OutputFormatJSON, item{ID: "f01r4", Workers: []string{"a"}}, "{\n  \"id\": \"f01r4\",\n  \"workers\": [\n    \"a\"\n  ]\n}"},
+		{OutputFormatYAML, item{ID: "f01r4", Workers: []string{"a"}}, "id: f01r4\nworkers:\n- a"},
+		{OutputFormatYAML, []item{}, "[]"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			out, err := Marshal(tc.format, tc.data)
+			if err != nil {
+				t.Fatalf("#%d: unexpected error: %s", i, err)
+			}
+			if string(out) != tc.output {
+				t.Errorf("#%d: Marshal(%s) = %q; want %q", i, tc.format, string(out), tc.output)
+			}
+		})
+	}
+}
+
+func TestIsStructured(t *testing.T) {
+	if !IsStructured(OutputFormatJSON) || !IsStructured(OutputFormatYAML) {
+		t.Error("Expected JSON and YAML to be structured output formats")
+	}
+	if IsStructured(OutputFormatTable) || IsStructured("") {
+		t.Error("Expected table output not to be a structured output format")
+	}
+}
+
+func TestMarshalTemplates(t *testing.T) {
+	type item struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	list := []item{{ID: "f01r4", Name: "Production"}, {ID: "a7k4", Name: "Staging"}}
+
+	testCases := []struct {
+		format       string
+		data         interface{}
+		output       string
+		errorMatcher func(error) bool
+	}{
+		{"jsonpath={.items[*].id}", list, "f01r4 a7k4", nil},
+		{"jsonpath=.items[*].id", list, "f01r4 a7k4", nil},
+		{`jsonpath={.items[?(@.name=="Staging")].id}`, list, "a7k4", nil},
+		{"jsonpath={.id}", list[0], "f01r4", nil},
+		{"jsonpath={.missing}", list[0], "", nil},
+		{"go-template={{range .items}}{{.id}}\n{{end}}", list, "f01r4\na7k4", nil},
+		{"go-template={{.name}}", list[0], "Production", nil},
+		{"jsonpath={.items[*", list, "", IsInvalidTemplate},
+		{"go-template={{.name", list, "", IsInvalidTemplate},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if !IsStructured(tc.format) {
+				t.Errorf("#%d: expected %q to be a structured output format", i, tc.format)
+			}
+
+			out, err := Marshal(tc.format, tc.data)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("#%d: unexpected error: %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("#%d: unexpected error: %s", i, err)
+			}
+			if string(out) != tc.output {
+				t.Errorf("#%d: Marshal(%s) = %q; want %q", i, tc.format, string(out), tc.output)
+			}
+		})
+	}
+}
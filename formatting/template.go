@@ -0,0 +1,99 @@
+package formatting
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// OutputFormatJSONPath is the prefix of the output format to print the
+	// result of a JSONPath expression, as in 'jsonpath={.items[*].id}'.
+	OutputFormatJSONPath = "jsonpath"
+	// OutputFormatGoTemplate is the prefix of the output format to render a
+	// Go template, as in 'go-template={{range .items}}{{.id}}{{end}}'.
+	OutputFormatGoTemplate = "go-template"
+
+	// templateItemsKey is the key lists are wrapped in before templates are
+	// applied, like in kubectl.
+	templateItemsKey = "items"
+)
+
+// isTemplate returns true if the output format is a JSONPath expression or
+// a Go template.
+func isTemplate(outputFormat string) bool {
+	kind, _ := splitTemplate(outputFormat)
+	return kind != ""
+}
+
+// splitTemplate splits an output format like 'jsonpath=<expression>' into
+// the template kind and the template text. The kind is empty if the output
+// format is not a template.
+func splitTemplate(outputFormat string) (string, string) {
+	parts := strings.SplitN(outputFormat, "=", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+
+	switch parts[0] {
+	case OutputFormatJSONPath, OutputFormatGoTemplate:
+		return parts[0], parts[1]
+	}
+
+	return "", ""
+}
+
+// renderTemplate applies the JSONPath expression or Go template given via
+// the output format to the JSON representation of the output. Lists are
+// wrapped in an object with the key 'items', so that expressions like
+// '{.items[*].id}' work the same way as with kubectl.
+func renderTemplate(outputFormat string, jsonBytes []byte) ([]byte, error) {
+	var data interface{}
+	err := json.Unmarshal(jsonBytes, &data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if list, ok := data.([]interface{}); ok {
+		data = map[string]interface{}{templateItemsKey: list}
+	}
+
+	var out bytes.Buffer
+	kind, text := splitTemplate(outputFormat)
+
+	switch kind {
+	case OutputFormatJSONPath:
+		// Allow expressions without curly braces, e. g. '.items[*].id'.
+		if !strings.Contains(text, "{") {
+			text = "{" + text + "}"
+		}
+
+		j := jsonpath.New("output").AllowMissingKeys(true)
+		err = j.Parse(text)
+		if err != nil {
+			return nil, microerror.Maskf(invalidTemplateError, "could not parse JSONPath expression %q: %s", text, err.Error())
+		}
+
+		err = j.Execute(&out, data)
+		if err != nil {
+			return nil, microerror.Maskf(invalidTemplateError, "could not apply JSONPath expression %q: %s", text, err.Error())
+		}
+
+	case OutputFormatGoTemplate:
+		t, err := template.New("output").Parse(text)
+		if err != nil {
+			return nil, microerror.Maskf(invalidTemplateError, "could not parse Go template %q: %s", text, err.Error())
+		}
+
+		err = t.Execute(&out, data)
+		if err != nil {
+			return nil, microerror.Maskf(invalidTemplateError, "could not execute Go template %q: %s", text, err.Error())
+		}
+	}
+
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
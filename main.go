@@ -2,24 +2,26 @@ package main
 
 import (
 	"os"
-	"runtime"
 
-	"github.com/fatih/color"
 	"github.com/giantswarm/columnize"
 
 	"github.com/giantswarm/gsctl/commands"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/colormode"
 )
 
 func init() {
 	columnizeConfig := columnize.DefaultConfig()
 	columnizeConfig.Glue = "   "
 
-	// disable color on windows, as it is super slow
-	if runtime.GOOS == "windows" || os.Getenv("GSCTL_DISABLE_COLORS") != "" {
-		color.NoColor = true
-	}
+	// Disable colors for output printed before flags are parsed. The
+	// --no-color flag is taken into account in the root command.
+	colormode.Configure(false)
 }
 
 func main() {
-	commands.RootCommand.Execute()
+	err := commands.RootCommand.Execute()
+	if err != nil {
+		os.Exit(errors.ExitCode(err))
+	}
 }
@@ -0,0 +1,89 @@
+package clusterdefinition
+
+import "github.com/giantswarm/microerror"
+
+var nodePoolNameMissingError = &microerror.Error{
+	Kind: "nodePoolNameMissingError",
+	Desc: "Every node pool in the definition needs a name, so that it can be matched with an existing node pool.",
+}
+
+// IsNodePoolNameMissing asserts nodePoolNameMissingError.
+func IsNodePoolNameMissing(err error) bool {
+	return microerror.Cause(err) == nodePoolNameMissingError
+}
+
+var duplicateNodePoolNameError = &microerror.Error{
+	Kind: "duplicateNodePoolNameError",
+	Desc: "Node pool names in the definition must be unique.",
+}
+
+// IsDuplicateNodePoolName asserts duplicateNodePoolNameError.
+func IsDuplicateNodePoolName(err error) bool {
+	return microerror.Cause(err) == duplicateNodePoolNameError
+}
+
+var clusterNameMissingError = &microerror.Error{
+	Kind: "clusterNameMissingError",
+	Desc: "The definition needs a cluster name, so that it can be matched with an existing cluster.",
+}
+
+// IsClusterNameMissing asserts clusterNameMissingError.
+func IsClusterNameMissing(err error) bool {
+	return microerror.Cause(err) == clusterNameMissingError
+}
+
+var ownerMissingError = &microerror.Error{
+	Kind: "ownerMissingError",
+	Desc: "The definition needs an owner organization.",
+}
+
+// IsOwnerMissing asserts ownerMissingError.
+func IsOwnerMissing(err error) bool {
+	return microerror.Cause(err) == ownerMissingError
+}
+
+var clusterNotUniqueError = &microerror.Error{
+	Kind: "clusterNotUniqueError",
+}
+
+// IsClusterNotUnique asserts clusterNotUniqueError.
+func IsClusterNotUnique(err error) bool {
+	return microerror.Cause(err) == clusterNotUniqueError
+}
+
+var definitionNotV5Error = &microerror.Error{
+	Kind: "definitionNotV5Error",
+	Desc: "Only cluster definitions for the v5 API (with an 'api_version' key) are supported.",
+}
+
+// IsDefinitionNotV5 asserts definitionNotV5Error.
+func IsDefinitionNotV5(err error) bool {
+	return microerror.Cause(err) == definitionNotV5Error
+}
+
+var invalidDefinitionError = &microerror.Error{
+	Kind: "invalidDefinitionError",
+}
+
+// IsInvalidDefinition asserts invalidDefinitionError.
+func IsInvalidDefinition(err error) bool {
+	return microerror.Cause(err) == invalidDefinitionError
+}
+
+var fileNotReadableError = &microerror.Error{
+	Kind: "fileNotReadableError",
+}
+
+// IsFileNotReadable asserts fileNotReadableError.
+func IsFileNotReadable(err error) bool {
+	return microerror.Cause(err) == fileNotReadableError
+}
+
+var renderFailedError = &microerror.Error{
+	Kind: "renderFailedError",
+}
+
+// IsRenderFailed asserts renderFailedError.
+func IsRenderFailed(err error) bool {
+	return microerror.Cause(err) == renderFailedError
+}
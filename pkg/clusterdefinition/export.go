@@ -0,0 +1,121 @@
+package clusterdefinition
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/pkg/provider"
+)
+
+// systemLabelDomain is the domain of labels managed by Giant Swarm, which
+// cannot be set by users.
+const systemLabelDomain = "giantswarm.io"
+
+// FromLiveState creates a v5 cluster definition from the live state of an
+// existing cluster. The result can be used with 'gsctl create cluster --file'
+// to create a cluster with the same layout.
+func FromLiveState(live *LiveState) *types.ClusterDefinitionV5 {
+	cluster := live.Cluster
+
+	def := &types.ClusterDefinitionV5{
+		APIVersion:     "v5",
+		Name:           cluster.Name,
+		Owner:          cluster.Owner,
+		ReleaseVersion: cluster.ReleaseVersion,
+	}
+
+	if cluster.MasterNodes != nil {
+		highAvailability := cluster.MasterNodes.HighAvailability
+		def.MasterNodes = &types.MasterNodes{
+			HighAvailability:  &highAvailability,
+			AvailabilityZones: cluster.MasterNodes.AvailabilityZones,
+		}
+
+		// On Azure, masters without availability zones have been created
+		// with unspecified availability zones.
+		if live.Provider == provider.Azure {
+			def.MasterNodes.Azure = &types.MasterNodesAzure{
+				AvailabilityZonesUnspecified: len(cluster.MasterNodes.AvailabilityZones) == 0,
+			}
+		}
+	} else if cluster.Master != nil {
+		def.Master = &types.MasterDefinition{
+			AvailabilityZone: cluster.Master.AvailabilityZone,
+		}
+	}
+
+	for key, value := range cluster.Labels {
+		if isSystemLabel(key) {
+			continue
+		}
+		if def.Labels == nil {
+			def.Labels = map[string]*string{}
+		}
+		v := value
+		def.Labels[key] = &v
+	}
+
+	for _, np := range live.NodePools {
+		npDef := &types.NodePoolDefinition{
+			Name: np.Name,
+		}
+
+		if len(np.AvailabilityZones) > 0 {
+			zones := append([]string{}, np.AvailabilityZones...)
+			sort.Strings(zones)
+			npDef.AvailabilityZones = &types.AvailabilityZonesDefinition{Zones: zones}
+		}
+
+		if np.Scaling != nil {
+			npDef.Scaling = &types.ScalingDefinition{
+				Min: np.Scaling.Min,
+				Max: np.Scaling.Max,
+			}
+		}
+
+		if np.NodeSpec != nil {
+			npDef.NodeSpec = &types.NodeSpec{}
+
+			if aws := np.NodeSpec.Aws; aws != nil {
+				npDef.NodeSpec.AWS = &types.AWSSpecificDefinition{
+					InstanceType:          aws.InstanceType,
+					UseAlikeInstanceTypes: aws.UseAlikeInstanceTypes,
+				}
+				if aws.InstanceDistribution != nil {
+					npDef.NodeSpec.AWS.InstanceDistribution = &types.AWSInstanceDistribution{
+						OnDemandBaseCapacity:                aws.InstanceDistribution.OnDemandBaseCapacity,
+						OnDemandPercentageAboveBaseCapacity: aws.InstanceDistribution.OnDemandPercentageAboveBaseCapacity,
+					}
+				}
+			}
+
+			if azure := np.NodeSpec.Azure; azure != nil {
+				npDef.NodeSpec.Azure = &types.AzureSpecificDefinition{
+					VMSize: azure.VMSize,
+				}
+				if azure.SpotInstances != nil {
+					npDef.NodeSpec.Azure.AzureSpotInstances = &types.AzureSpotInstances{
+						Enabled:  azure.SpotInstances.Enabled,
+						MaxPrice: azure.SpotInstances.MaxPrice,
+					}
+				}
+			}
+		}
+
+		def.NodePools = append(def.NodePools, npDef)
+	}
+
+	return def
+}
+
+// isSystemLabel returns true if the label key belongs to the Giant Swarm domain,
+// e. g. 'giantswarm.io/cluster' or 'release.giantswarm.io/version'.
+func isSystemLabel(key string) bool {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) != 2 {
+		return false
+	}
+
+	return parts[0] == systemLabelDomain || strings.HasSuffix(parts[0], "."+systemLabelDomain)
+}
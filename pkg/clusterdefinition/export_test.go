@@ -0,0 +1,81 @@
+package clusterdefinition
+
+import (
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// TestFromLiveStateRoundTrip tests that a definition exported from an Azure
+// cluster is valid and does not differ from that cluster.
+func TestFromLiveStateRoundTrip(t *testing.T) {
+	live := &LiveState{
+		Cluster: &models.V5ClusterDetailsResponse{
+			ID:             "cluster-id",
+			Name:           "My cluster",
+			Owner:          "acme",
+			ReleaseVersion: "11.0.0",
+			MasterNodes:    &models.V5ClusterDetailsResponseMasterNodes{HighAvailability: false},
+			Labels:         map[string]string{"env": "dev", "giantswarm.io/cluster": "cluster-id"},
+		},
+		NodePools: []*models.V5GetNodePoolsResponseItems{
+			{
+				ID:                "a7k",
+				Name:              "workers",
+				AvailabilityZones: []string{"westeurope-1"},
+				Scaling:           &models.V5GetNodePoolsResponseItemsScaling{Min: int64P(3), Max: 10},
+				NodeSpec: &models.V5GetNodePoolsResponseItemsNodeSpec{
+					Azure: &models.V5GetNodePoolsResponseItemsNodeSpecAzure{
+						VMSize:        "Standard_D4s_v3",
+						SpotInstances: &models.V5GetNodePoolsResponseItemsNodeSpecAzureSpotInstances{Enabled: true, MaxPrice: 0.5},
+					},
+				},
+			},
+		},
+		Provider: "azure",
+	}
+
+	def := FromLiveState(live)
+
+	// The definition must be valid, including the Azure master settings.
+	yamlBytes, err := yaml.Marshal(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err = ParseV5(yamlBytes)
+	if err != nil {
+		t.Fatalf("Exported YAML could not be parsed: %s\n%s", err, string(yamlBytes))
+	}
+	if def.MasterNodes.Azure == nil || !def.MasterNodes.Azure.AvailabilityZonesUnspecified {
+		t.Errorf("Expected Azure master availability zones to be unspecified in\n%s", string(yamlBytes))
+	}
+
+	if _, ok := def.Labels["giantswarm.io/cluster"]; ok {
+		t.Error("Expected system label to be skipped")
+	}
+	if def.NodePools[0].NodeSpec.Azure.AzureSpotInstances.MaxPrice != 0.5 {
+		t.Errorf("Unexpected Azure spot instance settings %#v", def.NodePools[0].NodeSpec.Azure.AzureSpotInstances)
+	}
+
+	actions := Plan(def, live, true)
+	if len(actions) != 0 {
+		t.Errorf("Expected no actions, got %v", actions)
+	}
+}
+
+func TestIsSystemLabel(t *testing.T) {
+	testCases := map[string]bool{
+		"giantswarm.io/cluster":         true,
+		"release.giantswarm.io/version": true,
+		"notgiantswarm.io/foo":          false,
+		"env":                           false,
+		"example.com/giantswarm.io":     false,
+	}
+
+	for key, expected := range testCases {
+		if isSystemLabel(key) != expected {
+			t.Errorf("Expected isSystemLabel(%q) to be %v", key, expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package clusterdefinition
+
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+)
+
+// FetchLiveState looks up the cluster with the given name owned by the given
+// organization and returns its current state. If no such cluster exists, the
+// returned state has no cluster.
+func FetchLiveState(clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams, name, owner string) (*LiveState, error) {
+	response, err := clientWrapper.GetClusters(auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var matchingIDs []string
+	for _, c := range response.Payload {
+		if c.Name == name && c.Owner == owner && c.DeleteDate == nil {
+			matchingIDs = append(matchingIDs, c.ID)
+		}
+	}
+
+	if len(matchingIDs) == 0 {
+		return &LiveState{}, nil
+	}
+	if len(matchingIDs) > 1 {
+		return nil, microerror.Maskf(clusterNotUniqueError, "found %d clusters named '%s' owned by '%s'", len(matchingIDs), name, owner)
+	}
+
+	clusterResponse, err := clientWrapper.GetClusterV5(matchingIDs[0], auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	nodePoolsResponse, err := clientWrapper.GetNodePools(matchingIDs[0], auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	state := &LiveState{
+		Cluster:   clusterResponse.Payload,
+		NodePools: nodePoolsResponse.Payload,
+	}
+
+	return state, nil
+}
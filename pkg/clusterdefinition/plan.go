@@ -0,0 +1,344 @@
+package clusterdefinition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+const (
+	// ActionCreateCluster means that the cluster has to be created.
+	ActionCreateCluster = "create-cluster"
+	// ActionModifyCluster means that cluster attributes have to be modified.
+	ActionModifyCluster = "modify-cluster"
+	// ActionUpdateLabels means that cluster labels have to be set or removed.
+	ActionUpdateLabels = "update-labels"
+	// ActionCreateNodePool means that a node pool has to be added.
+	ActionCreateNodePool = "create-nodepool"
+	// ActionModifyNodePool means that a node pool has to be modified.
+	ActionModifyNodePool = "modify-nodepool"
+	// ActionDeleteNodePool means that a node pool has to be deleted.
+	ActionDeleteNodePool = "delete-nodepool"
+)
+
+// LiveState is the current state of a cluster as returned by the API.
+type LiveState struct {
+	// Cluster holds the cluster details. If nil, the cluster does not exist.
+	Cluster *models.V5ClusterDetailsResponse
+	// NodePools is the list of the cluster's node pools.
+	NodePools []*models.V5GetNodePoolsResponseItems
+	// Provider is the provider of the installation, e. g. 'azure'. It is
+	// only needed to export provider specific settings.
+	Provider string
+}
+
+// Change describes the difference of a single attribute.
+type Change struct {
+	Field   string `json:"field"`
+	Current string `json:"current"`
+	Desired string `json:"desired"`
+}
+
+// Action is one step required to make a cluster match its definition.
+// Only the request body matching the action type is set.
+type Action struct {
+	Type         string    `json:"action"`
+	NodePoolID   string    `json:"nodepool_id,omitempty"`
+	NodePoolName string    `json:"nodepool_name,omitempty"`
+	Changes      []*Change `json:"changes,omitempty"`
+
+	AddClusterRequest     *models.V5AddClusterRequest       `json:"-"`
+	ModifyClusterRequest  *models.V5ModifyClusterRequest    `json:"-"`
+	SetLabelsRequest      *models.V5SetClusterLabelsRequest `json:"-"`
+	AddNodePoolRequest    *models.V5AddNodePoolRequest      `json:"-"`
+	ModifyNodePoolRequest *models.V5ModifyNodePoolRequest   `json:"-"`
+}
+
+// ValidateForPlan checks whether a definition can be compared with a live
+// cluster. The cluster is matched by name and owner, node pools are matched
+// by name, so every node pool must have a unique name.
+func ValidateForPlan(def *types.ClusterDefinitionV5) error {
+	if def.Name == "" {
+		return microerror.Mask(clusterNameMissingError)
+	}
+	if def.Owner == "" {
+		return microerror.Mask(ownerMissingError)
+	}
+
+	names := map[string]bool{}
+	for i, np := range def.NodePools {
+		if np == nil || np.Name == "" {
+			return microerror.Maskf(nodePoolNameMissingError, "node pool %d has no name", i+1)
+		}
+		if names[np.Name] {
+			return microerror.Maskf(duplicateNodePoolNameError, "node pool name '%s' is used more than once", np.Name)
+		}
+		names[np.Name] = true
+	}
+
+	return nil
+}
+
+// Plan returns the actions required to make the live cluster match the
+// definition, in the order they should be executed. An empty result means
+// that the cluster is up to date.
+//
+// Attributes which cannot be changed after creation (e. g. availability
+// zones or instance types of a node pool) are not taken into account.
+// Labels not mentioned in the definition are left untouched, and a label
+// with a null value in the definition is removed. Node pools not mentioned
+// in the definition are only deleted if deleteNodePools is true.
+func Plan(def *types.ClusterDefinitionV5, live *LiveState, deleteNodePools bool) []*Action {
+	if live == nil || live.Cluster == nil {
+		return planCreation(def)
+	}
+
+	var actions []*Action
+
+	if a := planModifyCluster(def, live.Cluster); a != nil {
+		actions = append(actions, a)
+	}
+	if a := planLabels(def.Labels, live.Cluster.Labels); a != nil {
+		actions = append(actions, a)
+	}
+
+	actions = append(actions, planNodePools(def.NodePools, live.NodePools, deleteNodePools)...)
+
+	return actions
+}
+
+func planCreation(def *types.ClusterDefinitionV5) []*Action {
+	actions := []*Action{
+		{
+			Type:              ActionCreateCluster,
+			AddClusterRequest: AddClusterRequestV5(def),
+		},
+	}
+
+	for _, np := range def.NodePools {
+		actions = append(actions, &Action{
+			Type:               ActionCreateNodePool,
+			NodePoolName:       np.Name,
+			AddNodePoolRequest: AddNodePoolRequest(np),
+		})
+	}
+
+	if a := planLabels(def.Labels, nil); a != nil {
+		actions = append(actions, a)
+	}
+
+	return actions
+}
+
+func planModifyCluster(def *types.ClusterDefinitionV5, cluster *models.V5ClusterDetailsResponse) *Action {
+	body := &models.V5ModifyClusterRequest{}
+	var changes []*Change
+
+	desiredRelease := strings.TrimPrefix(def.ReleaseVersion, "v")
+	currentRelease := strings.TrimPrefix(cluster.ReleaseVersion, "v")
+	if desiredRelease != "" && desiredRelease != currentRelease {
+		body.ReleaseVersion = desiredRelease
+		changes = append(changes, &Change{Field: "release_version", Current: currentRelease, Desired: desiredRelease})
+	}
+
+	// Master node high availability can only be switched on, not off.
+	if def.MasterNodes != nil && def.MasterNodes.HighAvailability != nil && *def.MasterNodes.HighAvailability {
+		if cluster.MasterNodes == nil || !cluster.MasterNodes.HighAvailability {
+			body.MasterNodes = &models.V5ModifyClusterRequestMasterNodes{HighAvailability: true}
+			changes = append(changes, &Change{Field: "master_nodes.high_availability", Current: "false", Desired: "true"})
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return &Action{
+		Type:                 ActionModifyCluster,
+		Changes:              changes,
+		ModifyClusterRequest: body,
+	}
+}
+
+func planLabels(desired map[string]*string, current map[string]string) *Action {
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	labels := map[string]*string{}
+	var changes []*Change
+
+	for _, key := range keys {
+		value := desired[key]
+		currentValue, exists := current[key]
+
+		switch {
+		case value == nil && exists:
+			labels[key] = nil
+			changes = append(changes, &Change{Field: "labels." + key, Current: currentValue})
+		case value != nil && (!exists || currentValue != *value):
+			labels[key] = value
+			changes = append(changes, &Change{Field: "labels." + key, Current: currentValue, Desired: *value})
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return &Action{
+		Type:             ActionUpdateLabels,
+		Changes:          changes,
+		SetLabelsRequest: &models.V5SetClusterLabelsRequest{Labels: labels},
+	}
+}
+
+func planNodePools(desired []*types.NodePoolDefinition, current []*models.V5GetNodePoolsResponseItems, deleteNodePools bool) []*Action {
+	var actions []*Action
+	matched := make([]bool, len(current))
+
+	for _, np := range desired {
+		index := -1
+		for i, existing := range current {
+			if !matched[i] && existing.Name == np.Name {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			actions = append(actions, &Action{
+				Type:               ActionCreateNodePool,
+				NodePoolName:       np.Name,
+				AddNodePoolRequest: AddNodePoolRequest(np),
+			})
+			continue
+		}
+
+		matched[index] = true
+		if a := planModifyNodePool(np, current[index]); a != nil {
+			actions = append(actions, a)
+		}
+	}
+
+	if deleteNodePools {
+		for i, existing := range current {
+			if matched[i] {
+				continue
+			}
+			actions = append(actions, &Action{
+				Type:         ActionDeleteNodePool,
+				NodePoolID:   existing.ID,
+				NodePoolName: existing.Name,
+			})
+		}
+	}
+
+	return actions
+}
+
+func planModifyNodePool(def *types.NodePoolDefinition, nodePool *models.V5GetNodePoolsResponseItems) *Action {
+	if def.Scaling == nil {
+		return nil
+	}
+
+	var currentMin, currentMax int64
+	if nodePool.Scaling != nil {
+		currentMax = nodePool.Scaling.Max
+		if nodePool.Scaling.Min != nil {
+			currentMin = *nodePool.Scaling.Min
+		}
+	}
+
+	// An omitted minimum keeps the current one.
+	desiredMin := currentMin
+	if def.Scaling.Min != nil {
+		desiredMin = *def.Scaling.Min
+	}
+	desiredMax := currentMax
+	if def.Scaling.Max != 0 {
+		desiredMax = def.Scaling.Max
+	}
+
+	var changes []*Change
+	if desiredMin != currentMin {
+		changes = append(changes, &Change{Field: "scaling.min", Current: fmt.Sprintf("%d", currentMin), Desired: fmt.Sprintf("%d", desiredMin)})
+	}
+	if desiredMax != currentMax {
+		changes = append(changes, &Change{Field: "scaling.max", Current: fmt.Sprintf("%d", currentMax), Desired: fmt.Sprintf("%d", desiredMax)})
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return &Action{
+		Type:         ActionModifyNodePool,
+		NodePoolID:   nodePool.ID,
+		NodePoolName: nodePool.Name,
+		Changes:      changes,
+		ModifyNodePoolRequest: &models.V5ModifyNodePoolRequest{
+			Scaling: &models.V5ModifyNodePoolRequestScaling{
+				Min: &desiredMin,
+				Max: desiredMax,
+			},
+		},
+	}
+}
+
+// String returns a short, human-readable description of the action.
+func (a *Action) String() string {
+	var description string
+
+	switch a.Type {
+	case ActionCreateCluster:
+		description = "create cluster"
+	case ActionModifyCluster:
+		description = "modify cluster"
+	case ActionUpdateLabels:
+		description = "update cluster labels"
+	case ActionCreateNodePool:
+		if a.NodePoolName == "" {
+			description = "create default node pool"
+		} else {
+			description = fmt.Sprintf("create node pool '%s'", a.NodePoolName)
+		}
+	case ActionModifyNodePool:
+		description = fmt.Sprintf("modify node pool '%s' (ID %s)", a.NodePoolName, a.NodePoolID)
+	case ActionDeleteNodePool:
+		description = fmt.Sprintf("delete node pool '%s' (ID %s)", a.NodePoolName, a.NodePoolID)
+	default:
+		description = a.Type
+	}
+
+	var changes []string
+	for _, c := range a.Changes {
+		changes = append(changes, c.String())
+	}
+	if len(changes) > 0 {
+		description += ": " + strings.Join(changes, ", ")
+	}
+
+	return description
+}
+
+// String returns a human-readable description of the change.
+func (c *Change) String() string {
+	current := c.Current
+	if current == "" {
+		current = "(none)"
+	}
+	desired := c.Desired
+	if desired == "" {
+		desired = "(none)"
+	}
+
+	return fmt.Sprintf("%s %s -> %s", c.Field, current, desired)
+}
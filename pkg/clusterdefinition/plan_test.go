@@ -0,0 +1,236 @@
+package clusterdefinition
+
+import (
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+func int64P(v int64) *int64 {
+	return &v
+}
+
+func stringP(v string) *string {
+	return &v
+}
+
+func actionTypes(actions []*Action) []string {
+	result := []string{}
+	for _, a := range actions {
+		result = append(result, a.Type)
+	}
+	return result
+}
+
+func TestPlan(t *testing.T) {
+	live := &LiveState{
+		Cluster: &models.V5ClusterDetailsResponse{
+			ID:             "cluster-id",
+			Name:           "My cluster",
+			Owner:          "acme",
+			ReleaseVersion: "11.0.0",
+			Labels:         map[string]string{"env": "dev", "team": "blue"},
+		},
+		NodePools: []*models.V5GetNodePoolsResponseItems{
+			{ID: "a7k", Name: "workers", Scaling: &models.V5GetNodePoolsResponseItemsScaling{Min: int64P(3), Max: 10}},
+			{ID: "b8l", Name: "old", Scaling: &models.V5GetNodePoolsResponseItemsScaling{Min: int64P(1), Max: 2}},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		definition      *types.ClusterDefinitionV5
+		live            *LiveState
+		deleteNodePools bool
+		expectedActions []string
+	}{
+		{
+			name: "case 0: cluster does not exist",
+			definition: &types.ClusterDefinitionV5{
+				Name:      "My cluster",
+				Owner:     "acme",
+				NodePools: []*types.NodePoolDefinition{{Name: "workers"}},
+				Labels:    map[string]*string{"env": stringP("dev")},
+			},
+			live:            &LiveState{},
+			expectedActions: []string{ActionCreateCluster, ActionCreateNodePool, ActionUpdateLabels},
+		},
+		{
+			name: "case 1: cluster is up to date",
+			definition: &types.ClusterDefinitionV5{
+				Name:           "My cluster",
+				Owner:          "acme",
+				ReleaseVersion: "v11.0.0",
+				NodePools: []*types.NodePoolDefinition{
+					{Name: "workers", Scaling: &types.ScalingDefinition{Min: int64P(3), Max: 10}},
+					{Name: "old"},
+				},
+				Labels: map[string]*string{"env": stringP("dev"), "gone": nil},
+			},
+			live:            live,
+			expectedActions: []string{},
+		},
+		{
+			name: "case 2: everything differs, node pool deletion disabled",
+			definition: &types.ClusterDefinitionV5{
+				Name:           "My cluster",
+				Owner:          "acme",
+				ReleaseVersion: "12.0.0",
+				NodePools: []*types.NodePoolDefinition{
+					{Name: "workers", Scaling: &types.ScalingDefinition{Min: int64P(3), Max: 20}},
+					{Name: "new"},
+				},
+				Labels: map[string]*string{"env": stringP("prod"), "team": nil},
+			},
+			live:            live,
+			expectedActions: []string{ActionModifyCluster, ActionUpdateLabels, ActionModifyNodePool, ActionCreateNodePool},
+		},
+		{
+			name: "case 3: node pool deletion enabled",
+			definition: &types.ClusterDefinitionV5{
+				Name:      "My cluster",
+				Owner:     "acme",
+				NodePools: []*types.NodePoolDefinition{{Name: "workers"}},
+			},
+			live:            live,
+			deleteNodePools: true,
+			expectedActions: []string{ActionDeleteNodePool},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actions := Plan(tc.definition, tc.live, tc.deleteNodePools)
+			if diff := cmp.Diff(tc.expectedActions, actionTypes(actions)); diff != "" {
+				t.Errorf("Actions not as expected (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlanDetails(t *testing.T) {
+	definition := &types.ClusterDefinitionV5{
+		ReleaseVersion: "12.0.0",
+		NodePools: []*types.NodePoolDefinition{
+			{Name: "workers", Scaling: &types.ScalingDefinition{Min: int64P(2)}},
+		},
+		Labels: map[string]*string{"env": stringP("prod"), "team": nil},
+	}
+	live := &LiveState{
+		Cluster: &models.V5ClusterDetailsResponse{
+			ReleaseVersion: "11.0.0",
+			Labels:         map[string]string{"env": "dev", "team": "blue"},
+		},
+		NodePools: []*models.V5GetNodePoolsResponseItems{
+			{ID: "a7k", Name: "workers", Scaling: &models.V5GetNodePoolsResponseItemsScaling{Min: int64P(3), Max: 10}},
+		},
+	}
+
+	actions := Plan(definition, live, false)
+	if len(actions) != 3 {
+		t.Fatalf("Expected 3 actions, got %d", len(actions))
+	}
+
+	if actions[0].ModifyClusterRequest.ReleaseVersion != "12.0.0" {
+		t.Errorf("Expected release version 12.0.0, got %q", actions[0].ModifyClusterRequest.ReleaseVersion)
+	}
+
+	labels := actions[1].SetLabelsRequest.Labels
+	if labels["env"] == nil || *labels["env"] != "prod" {
+		t.Errorf("Expected label env=prod, got %#v", labels["env"])
+	}
+	if value, ok := labels["team"]; !ok || value != nil {
+		t.Errorf("Expected label team to be removed, got %#v", value)
+	}
+
+	np := actions[2]
+	if np.NodePoolID != "a7k" {
+		t.Errorf("Expected node pool ID a7k, got %q", np.NodePoolID)
+	}
+	if *np.ModifyNodePoolRequest.Scaling.Min != 2 || np.ModifyNodePoolRequest.Scaling.Max != 10 {
+		t.Errorf("Unexpected scaling %#v", np.ModifyNodePoolRequest.Scaling)
+	}
+}
+
+// TestPlanScalingWithoutMin checks that a node pool definition without a
+// minimum keeps the current minimum instead of setting it to 0.
+func TestPlanScalingWithoutMin(t *testing.T) {
+	definition := &types.ClusterDefinitionV5{
+		NodePools: []*types.NodePoolDefinition{
+			{Name: "workers", Scaling: &types.ScalingDefinition{Max: 20}},
+		},
+	}
+	live := &LiveState{
+		Cluster: &models.V5ClusterDetailsResponse{},
+		NodePools: []*models.V5GetNodePoolsResponseItems{
+			{ID: "a7k", Name: "workers", Scaling: &models.V5GetNodePoolsResponseItemsScaling{Min: int64P(3), Max: 10}},
+		},
+	}
+
+	actions := Plan(definition, live, false)
+	if len(actions) != 1 {
+		t.Fatalf("Expected 1 action, got %d", len(actions))
+	}
+	if len(actions[0].Changes) != 1 || actions[0].Changes[0].Field != "scaling.max" {
+		t.Errorf("Expected only scaling.max to change, got %v", actions[0])
+	}
+	if *actions[0].ModifyNodePoolRequest.Scaling.Min != 3 || actions[0].ModifyNodePoolRequest.Scaling.Max != 20 {
+		t.Errorf("Unexpected scaling %#v", actions[0].ModifyNodePoolRequest.Scaling)
+	}
+
+	// An explicit minimum of 0, however, is applied.
+	definition.NodePools[0].Scaling = &types.ScalingDefinition{Min: int64P(0), Max: 10}
+	actions = Plan(definition, live, false)
+	if len(actions) != 1 || *actions[0].ModifyNodePoolRequest.Scaling.Min != 0 {
+		t.Errorf("Expected minimum to be set to 0, got %v", actions)
+	}
+}
+
+func TestValidateForPlan(t *testing.T) {
+	testCases := []struct {
+		name         string
+		definition   *types.ClusterDefinitionV5
+		errorMatcher func(error) bool
+	}{
+		{
+			name:       "case 0: valid",
+			definition: &types.ClusterDefinitionV5{Name: "c", Owner: "acme", NodePools: []*types.NodePoolDefinition{{Name: "a"}, {Name: "b"}}},
+		},
+		{
+			name:         "case 1: missing name",
+			definition:   &types.ClusterDefinitionV5{Name: "c", Owner: "acme", NodePools: []*types.NodePoolDefinition{{Name: "a"}, {}}},
+			errorMatcher: IsNodePoolNameMissing,
+		},
+		{
+			name:         "case 2: duplicate name",
+			definition:   &types.ClusterDefinitionV5{Name: "c", Owner: "acme", NodePools: []*types.NodePoolDefinition{{Name: "a"}, {Name: "a"}}},
+			errorMatcher: IsDuplicateNodePoolName,
+		},
+		{
+			name:         "case 3: missing cluster name",
+			definition:   &types.ClusterDefinitionV5{Owner: "acme"},
+			errorMatcher: IsClusterNameMissing,
+		},
+		{
+			name:         "case 4: missing owner",
+			definition:   &types.ClusterDefinitionV5{Name: "c"},
+			errorMatcher: IsOwnerMissing,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateForPlan(tc.definition)
+			if tc.errorMatcher == nil {
+				if err != nil {
+					t.Errorf("Unexpected error %#v", err)
+				}
+			} else if !tc.errorMatcher(err) {
+				t.Errorf("Error did not match expected type. Got %#v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package clusterdefinition
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+// ParseV5 parses YAML data into a v5 cluster definition. Other
+// definition versions are rejected.
+func ParseV5(yamlBytes []byte) (*types.ClusterDefinitionV5, error) {
+	rawMap := map[string]interface{}{}
+
+	err := yaml.Unmarshal(yamlBytes, rawMap)
+	if err != nil {
+		return nil, microerror.Maskf(invalidDefinitionError, err.Error())
+	}
+
+	// Detecting v5 purely based on the existence of the 'api_version' key,
+	// the same way 'gsctl create cluster' does.
+	if _, ok := rawMap["api_version"]; !ok {
+		return nil, microerror.Mask(definitionNotV5Error)
+	}
+
+	err = Validate(yamlBytes, SchemaV5())
+	if err != nil {
+		return nil, microerror.Maskf(invalidDefinitionError, err.Error())
+	}
+
+	def := &types.ClusterDefinitionV5{}
+	err = yaml.UnmarshalStrict(yamlBytes, def)
+	if err != nil {
+		return nil, microerror.Maskf(invalidDefinitionError, err.Error())
+	}
+
+	return def, nil
+}
+
+// StandardInputPath is the special path meaning that the definition
+// should be read from standard input.
+const StandardInputPath = "-"
+
+// ReadV5 reads a v5 cluster definition from the given file, or from
+// standard input if path is StandardInputPath.
+func ReadV5(fs afero.Fs, path string) (*types.ClusterDefinitionV5, error) {
+	var data []byte
+	var err error
+
+	if path == StandardInputPath {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = afero.ReadFile(fs, path)
+	}
+	if err != nil {
+		return nil, microerror.Maskf(fileNotReadableError, err.Error())
+	}
+
+	def, err := ParseV5(data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return def, nil
+}
+
+// SplitDocuments splits YAML data into the documents separated by '---'
+// lines. Documents without content, e. g. only comments, are skipped. Each
+// document is prefixed with empty lines, so that line numbers reported when
+// parsing a document refer to the position in data.
+func SplitDocuments(data []byte) [][]byte {
+	var documents [][]byte
+	var current bytes.Buffer
+	startLine := 0
+	hasContent := false
+
+	flush := func() {
+		if hasContent {
+			documents = append(documents, append(bytes.Repeat([]byte("\n"), startLine), current.Bytes()...))
+		}
+		current = bytes.Buffer{}
+		hasContent = false
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for i, line := range lines {
+		trimmed := bytes.TrimSpace(line)
+		if bytes.Equal(bytes.TrimRight(line, " \t\r\n"), []byte("---")) || bytes.HasPrefix(line, []byte("--- ")) {
+			flush()
+			startLine = i + 1
+			continue
+		}
+
+		current.Write(line)
+		if len(trimmed) > 0 && trimmed[0] != '#' {
+			hasContent = true
+		}
+	}
+	flush()
+
+	return documents
+}
@@ -0,0 +1,49 @@
+package clusterdefinition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "single document",
+			input:    "owner: acme\n",
+			expected: []string{"owner: acme\n"},
+		},
+		{
+			name:     "leading separator and empty documents",
+			input:    "---\nowner: acme\n---\n# only a comment\n---\n\nowner: foo\n",
+			expected: []string{"\nowner: acme\n", "\n\n\n\n\n\nowner: foo\n"},
+		},
+		{
+			name:     "separator with comment",
+			input:    "owner: acme\n--- # second\nowner: foo",
+			expected: []string{"owner: acme\n", "\n\nowner: foo"},
+		},
+		{
+			name:     "no content",
+			input:    "# nothing here\n---\n",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var documents []string
+			for _, d := range SplitDocuments([]byte(tc.input)) {
+				documents = append(documents, string(d))
+			}
+
+			if diff := cmp.Diff(tc.expected, documents); diff != "" {
+				t.Errorf("Documents not as expected (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
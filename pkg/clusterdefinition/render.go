@@ -0,0 +1,407 @@
+package clusterdefinition
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// variablePattern matches '$$' (an escaped dollar sign), '${VAR}' and
+// '${VAR:-default}'.
+var variablePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// RenderOptions defines how a cluster definition template is rendered
+// into the actual definition.
+type RenderOptions struct {
+	// Values are used to replace '${VAR}' placeholders. They take
+	// precedence over environment variables.
+	Values map[string]string
+	// LookupEnv is used to look up environment variables for placeholders
+	// not found in Values, usually os.LookupEnv. If nil, the environment
+	// is not used.
+	LookupEnv func(string) (string, bool)
+	// Set contains overrides like 'nodepools[0].scaling.max=10', applied
+	// after variable substitution.
+	Set []string
+}
+
+// Render substitutes variables in the definition template and applies
+// the overrides given in options. The result is the YAML data to parse,
+// and the parsed YAML document to validate. As the overrides are applied
+// to the document, its nodes keep the line and column from the template,
+// so that validation errors point into the template. Nodes added via
+// overrides have line 0.
+func Render(yamlBytes []byte, options RenderOptions) ([]byte, *yamlv3.Node, error) {
+	data, err := Substitute(yamlBytes, func(name string) (string, bool) {
+		if value, ok := options.Values[name]; ok {
+			return value, true
+		}
+		if options.LookupEnv != nil {
+			return options.LookupEnv(name)
+		}
+		return "", false
+	})
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	if len(options.Set) == 0 {
+		return data, doc, nil
+	}
+
+	err = applySet(doc, options.Set)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	data, err = encodeDocument(doc)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	return data, doc, nil
+}
+
+// Substitute replaces '${VAR}' placeholders using the lookup function.
+// For undefined variables, a default can be given as '${VAR:-default}'.
+// A literal '$' can be written as '$$'. Undefined variables without a
+// default result in an error naming all of them. Comments are left as
+// they are.
+func Substitute(data []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	undefined := map[string]bool{}
+
+	replace := func(match []byte) []byte {
+		if string(match) == "$$" {
+			return []byte("$")
+		}
+
+		groups := variablePattern.FindSubmatch(match)
+		name := string(groups[1])
+		if value, ok := lookup(name); ok {
+			return []byte(value)
+		}
+		if len(groups[2]) > 0 {
+			return groups[3]
+		}
+
+		undefined[name] = true
+		return match
+	}
+
+	var result []byte
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		end := commentStart(line)
+		result = append(result, variablePattern.ReplaceAllFunc(line[:end], replace)...)
+		result = append(result, line[end:]...)
+	}
+
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return nil, microerror.Maskf(renderFailedError, "undefined variable(s): %s", strings.Join(names, ", "))
+	}
+
+	return result, nil
+}
+
+// commentStart returns the position of the comment in a line of YAML, or
+// the length of the line if there is none. A comment starts with '#' at
+// the beginning of the line or after whitespace, outside of quotes.
+func commentStart(line []byte) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return i
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[{,:-", line[i-1]) >= 0):
+			// Only quotes at the beginning of a value start a quoted string.
+			quote = c
+		}
+	}
+
+	return len(line)
+}
+
+// ReadValues reads variables for substitution from a YAML file with a
+// flat mapping of names to scalar values.
+func ReadValues(fs afero.Fs, path string) (map[string]string, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, microerror.Maskf(renderFailedError, "could not read values file: %s", err.Error())
+	}
+
+	rawValues := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &rawValues)
+	if err != nil {
+		return nil, microerror.Maskf(renderFailedError, "could not parse values file '%s': %s", path, err.Error())
+	}
+
+	values := map[string]string{}
+	for name, value := range rawValues {
+		switch v := value.(type) {
+		case nil:
+			values[name] = ""
+		case map[interface{}]interface{}, []interface{}:
+			return nil, microerror.Maskf(renderFailedError, "value '%s' in values file '%s' must be a scalar", name, path)
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+
+	return values, nil
+}
+
+// pathElement is one step in a --set path, either a mapping key or a
+// sequence index.
+type pathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// ApplySet applies overrides like 'nodepools[0].scaling.max=10' to YAML
+// data. Values are parsed as YAML, so numbers and booleans keep their
+// type. Missing keys are created, and an index equal to the length of a
+// sequence appends an item. Dots in keys can be escaped as '\.'.
+func ApplySet(data []byte, expressions []string) ([]byte, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = applySet(doc, expressions)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	data, err = encodeDocument(doc)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
+
+// parseDocument parses YAML data into a document node.
+func parseDocument(data []byte) (*yamlv3.Node, error) {
+	doc := &yamlv3.Node{}
+	err := yamlv3.Unmarshal(data, doc)
+	if err != nil {
+		return nil, microerror.Maskf(invalidDefinitionError, err.Error())
+	}
+	if doc.Kind == 0 {
+		doc = &yamlv3.Node{Kind: yamlv3.DocumentNode}
+	}
+
+	return doc, nil
+}
+
+// encodeDocument encodes a document node as YAML data.
+func encodeDocument(doc *yamlv3.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yamlv3.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err := encoder.Encode(doc)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// applySet applies overrides to a document node, see ApplySet.
+func applySet(doc *yamlv3.Node, expressions []string) error {
+	if len(doc.Content) == 0 {
+		doc.Content = []*yamlv3.Node{{Kind: yamlv3.MappingNode, Tag: "!!map"}}
+	}
+
+	for _, expression := range expressions {
+		parts := strings.SplitN(expression, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return microerror.Maskf(renderFailedError, "invalid --set expression '%s', expected 'path=value'", expression)
+		}
+
+		path, err := parsePath(parts[0])
+		if err != nil {
+			return microerror.Maskf(renderFailedError, "invalid --set path '%s': %s", parts[0], err.Error())
+		}
+
+		value, err := parseValue(parts[1])
+		if err != nil {
+			return microerror.Maskf(renderFailedError, "invalid --set value '%s': %s", parts[1], err.Error())
+		}
+
+		err = setNode(&doc.Content[0], path, value)
+		if err != nil {
+			return microerror.Maskf(renderFailedError, "could not apply --set '%s': %s", expression, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// parsePath splits a path like 'nodepools[0].scaling.max' into elements.
+func parsePath(path string) ([]pathElement, error) {
+	var elements []pathElement
+	var key strings.Builder
+	hasKey := false
+
+	flushKey := func() {
+		if hasKey {
+			elements = append(elements, pathElement{key: key.String()})
+			key.Reset()
+			hasKey = false
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '\\':
+			if i+1 < len(path) {
+				i++
+				key.WriteByte(path[i])
+				hasKey = true
+			}
+		case '.':
+			if !hasKey && (len(elements) == 0 || !elements[len(elements)-1].isIndex) {
+				return nil, fmt.Errorf("empty key at position %d", i)
+			}
+			flushKey()
+		case '[':
+			flushKey()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' at position %d", i)
+			}
+			index, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index '%s'", path[i+1:i+end])
+			}
+			elements = append(elements, pathElement{index: index, isIndex: true})
+			i += end
+		default:
+			key.WriteByte(c)
+			hasKey = true
+		}
+	}
+	flushKey()
+
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("path is empty")
+	}
+
+	return elements, nil
+}
+
+// parseValue parses a --set value as a YAML scalar or flow collection.
+// An empty value results in null. The resulting nodes have line 0, as
+// they are not part of the template.
+func parseValue(value string) (*yamlv3.Node, error) {
+	var doc yamlv3.Node
+	err := yamlv3.Unmarshal([]byte(value), &doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+
+	clearPositions(doc.Content[0])
+
+	return doc.Content[0], nil
+}
+
+// clearPositions sets the line and column of a node and its children to 0.
+func clearPositions(node *yamlv3.Node) {
+	node.Line = 0
+	node.Column = 0
+	for _, child := range node.Content {
+		clearPositions(child)
+	}
+}
+
+// setNode sets the value at the given path below the node in slot,
+// creating mappings and sequences on the way as needed.
+func setNode(slot **yamlv3.Node, path []pathElement, value *yamlv3.Node) error {
+	if len(path) == 0 {
+		*slot = value
+		return nil
+	}
+
+	node := *slot
+	element := path[0]
+	isNull := node.Kind == yamlv3.ScalarNode && node.Tag == "!!null"
+
+	if element.isIndex {
+		if isNull {
+			node = &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+			*slot = node
+		}
+		if node.Kind != yamlv3.SequenceNode {
+			return fmt.Errorf("cannot use index [%d] on a non-sequence value in line %d", element.index, node.Line)
+		}
+		if element.index > len(node.Content) {
+			return fmt.Errorf("index [%d] is out of range, the sequence in line %d has %d item(s)", element.index, node.Line, len(node.Content))
+		}
+		if element.index == len(node.Content) {
+			node.Content = append(node.Content, newContainer(path[1:]))
+		}
+
+		return setNode(&node.Content[element.index], path[1:], value)
+	}
+
+	if isNull {
+		node = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		*slot = node
+	}
+	if node.Kind != yamlv3.MappingNode {
+		return fmt.Errorf("cannot set key '%s' on a non-mapping value in line %d", element.key, node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == element.key {
+			return setNode(&node.Content[i+1], path[1:], value)
+		}
+	}
+
+	node.Content = append(node.Content,
+		&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: element.key},
+		newContainer(path[1:]))
+
+	return setNode(&node.Content[len(node.Content)-1], path[1:], value)
+}
+
+// newContainer returns an empty node suitable for the remaining path.
+func newContainer(path []pathElement) *yamlv3.Node {
+	switch {
+	case len(path) == 0:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+	case path[0].isIndex:
+		return &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+	}
+
+	return &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+}
@@ -0,0 +1,228 @@
+package clusterdefinition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+func TestSubstitute(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		values := map[string]string{"OWNER": "acme", "EMPTY": ""}
+		v, ok := values[name]
+		return v, ok
+	}
+
+	var testCases = []struct {
+		name         string
+		input        string
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "no placeholders",
+			input:    "owner: acme",
+			expected: "owner: acme",
+		},
+		{
+			name:     "defined variables and defaults",
+			input:    "owner: ${OWNER}\nname: ${NAME:-Test cluster}\nrelease_version: ${EMPTY:-1.0.0}",
+			expected: "owner: acme\nname: Test cluster\nrelease_version: ",
+		},
+		{
+			name:     "escaped dollar sign",
+			input:    "name: $${OWNER} costs $5",
+			expected: "name: ${OWNER} costs $5",
+		},
+		{
+			name:     "comments",
+			input:    "# Set ${NAME} via --values\nowner: ${OWNER} # not ${ORG}\nname: 'a # ${OWNER}'\nlabels: {env: \"#${OWNER}\"} #${ORG}",
+			expected: "# Set ${NAME} via --values\nowner: acme # not ${ORG}\nname: 'a # acme'\nlabels: {env: \"#acme\"} #${ORG}",
+		},
+		{
+			name:         "undefined variables",
+			input:        "owner: ${ORG}\nname: ${NAME}\nrelease_version: ${ORG}",
+			errorMatcher: IsRenderFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := Substitute([]byte(tc.input), lookup)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("Unexpected error: %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, string(output))
+			}
+		})
+	}
+}
+
+func TestApplySet(t *testing.T) {
+	input := `api_version: v5
+owner: acme
+labels:
+  env: dev
+nodepools:
+- name: workers
+  scaling:
+    min: 3
+    max: 5
+`
+
+	var testCases = []struct {
+		name         string
+		set          []string
+		expected     *types.ClusterDefinitionV5
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "override existing and add new values",
+			set: []string{
+				"nodepools[0].scaling.max=10",
+				"nodepools[1].name=spot",
+				"labels.giantswarm\\.io/team=blue",
+				"labels.env=",
+				"release_version=11.0",
+			},
+			expected: &types.ClusterDefinitionV5{
+				APIVersion:     "v5",
+				Owner:          "acme",
+				ReleaseVersion: "11.0",
+				Labels: map[string]*string{
+					"env":                nil,
+					"giantswarm.io/team": stringP("blue"),
+				},
+				NodePools: []*types.NodePoolDefinition{
+					{Name: "workers", Scaling: &types.ScalingDefinition{Min: int64P(3), Max: 10}},
+					{Name: "spot"},
+				},
+			},
+		},
+		{
+			name:         "index out of range",
+			set:          []string{"nodepools[2].name=spot"},
+			errorMatcher: IsRenderFailed,
+		},
+		{
+			name:         "key on a sequence",
+			set:          []string{"nodepools.name=spot"},
+			errorMatcher: IsRenderFailed,
+		},
+		{
+			name:         "missing value",
+			set:          []string{"owner"},
+			errorMatcher: IsRenderFailed,
+		},
+		{
+			name:         "invalid path",
+			set:          []string{"nodepools[x].name=spot"},
+			errorMatcher: IsRenderFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := ApplySet([]byte(input), tc.set)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Fatalf("Unexpected error: %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			def := &types.ClusterDefinitionV5{}
+			err = yaml.UnmarshalStrict(output, def)
+			if err != nil {
+				t.Fatalf("Could not parse output: %s\n%s", err, string(output))
+			}
+			if diff := cmp.Diff(tc.expected, def); diff != "" {
+				t.Errorf("Definition not as expected (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "values.yaml", []byte("OWNER: acme\nMAX: 10\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := ReadValues(fs, "values.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	env := func(name string) (string, bool) {
+		if name == "OWNER" || name == "NAME" {
+			return "from-env", true
+		}
+		return "", false
+	}
+
+	output, _, err := Render([]byte("owner: ${OWNER}\nname: ${NAME}\nscaling:\n  max: ${MAX}\n"), RenderOptions{
+		Values:    values,
+		LookupEnv: env,
+		Set:       []string{"scaling.min=3"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := "owner: acme\nname: from-env\nscaling:\n  max: 10\n  min: 3\n"
+	if string(output) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(output))
+	}
+
+	err = afero.WriteFile(fs, "nested.yaml", []byte("NODEPOOL:\n  name: foo\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadValues(fs, "nested.yaml")
+	if !IsRenderFailed(err) {
+		t.Errorf("Expected renderFailedError for nested values, got %#v", err)
+	}
+}
+
+// TestRenderPositions checks that validating the document returned by
+// Render reports the positions in the template, even with overrides.
+func TestRenderPositions(t *testing.T) {
+	template := "# Cluster for ${OWNER}\n\napi_version: v5\nowner: [${OWNER}]\n"
+
+	_, doc, err := Render([]byte(template), RenderOptions{
+		Values: map[string]string{"OWNER": "acme"},
+		Set:    []string{"name=foo", "master_nodes.high_availability=maybe"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err = ValidateDocument(doc, SchemaV5())
+	expected := ValidationErrors{
+		{Line: 0, Column: 0, Path: "master_nodes.high_availability", Message: "expected boolean or null, got string"},
+		{Line: 4, Column: 8, Path: "owner", Message: "expected string, got array"},
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("Errors not as expected (-want +got):\n%s", diff)
+	}
+	if !strings.Contains(err.Error(), "value set via --set: master_nodes.high_availability") {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
@@ -0,0 +1,137 @@
+// Package clusterdefinition provides helpers to translate cluster definitions
+// (see commands/types) into API requests, and to compare a definition with the
+// live state of a cluster.
+package clusterdefinition
+
+import (
+	"github.com/giantswarm/gsclientgen/v2/models"
+
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/pkg/provider"
+)
+
+// SetProviderDefaultsV5 sets the master node settings of a definition which
+// depend on the provider. On Azure, no availability zone is requested for the
+// master node if the installation doesn't support availability zones.
+func SetProviderDefaultsV5(def *types.ClusterDefinitionV5, providerName string, maxSupportedAZs int64) {
+	if providerName == provider.Azure {
+		def.MasterNodes = &types.MasterNodes{
+			Azure: &types.MasterNodesAzure{
+				AvailabilityZonesUnspecified: maxSupportedAZs < 1,
+			},
+		}
+	}
+}
+
+// AddClusterRequestV5 creates a models.V5AddClusterRequest from a cluster definition.
+func AddClusterRequestV5(def *types.ClusterDefinitionV5) *models.V5AddClusterRequest {
+	b := &models.V5AddClusterRequest{
+		Owner:          &def.Owner,
+		Name:           def.Name,
+		ReleaseVersion: def.ReleaseVersion,
+	}
+
+	if def.Master != nil {
+		b.Master = &models.V5AddClusterRequestMaster{
+			AvailabilityZone: def.Master.AvailabilityZone,
+		}
+	}
+
+	if def.MasterNodes != nil {
+		b.MasterNodes = &models.V5AddClusterRequestMasterNodes{
+			HighAvailability:  def.MasterNodes.HighAvailability,
+			AvailabilityZones: def.MasterNodes.AvailabilityZones,
+		}
+
+		if def.MasterNodes.Azure != nil {
+			b.MasterNodes.Azure = &models.V5AddClusterRequestMasterNodesAzure{
+				AvailabilityZonesUnspecified: def.MasterNodes.Azure.AvailabilityZonesUnspecified,
+			}
+		}
+	}
+
+	return b
+}
+
+// AddNodePoolRequest creates a models.V5AddNodePoolRequest from a node pool definition.
+func AddNodePoolRequest(def *types.NodePoolDefinition) *models.V5AddNodePoolRequest {
+	b := &models.V5AddNodePoolRequest{
+		Name:              def.Name,
+		AvailabilityZones: &models.V5AddNodePoolRequestAvailabilityZones{},
+		Scaling:           &models.V5AddNodePoolRequestScaling{},
+		NodeSpec:          &models.V5AddNodePoolRequestNodeSpec{},
+	}
+
+	if def.AvailabilityZones != nil {
+		if def.AvailabilityZones.Number != 0 {
+			b.AvailabilityZones.Number = def.AvailabilityZones.Number
+		}
+		if len(def.AvailabilityZones.Zones) != 0 {
+			b.AvailabilityZones.Zones = def.AvailabilityZones.Zones
+		}
+	}
+
+	if def.Scaling != nil {
+		// Without a minimum in the definition, 0 is requested, as before the
+		// minimum became optional for updating existing node pools.
+		var min int64
+		if def.Scaling.Min != nil {
+			min = *def.Scaling.Min
+		}
+		if min >= 0 {
+			b.Scaling.Min = &min
+		}
+		if def.Scaling.Max != 0 {
+			b.Scaling.Max = def.Scaling.Max
+		}
+	}
+
+	if def.NodeSpec != nil {
+		if def.NodeSpec.AWS != nil {
+			b.NodeSpec.Aws = &models.V5AddNodePoolRequestNodeSpecAws{}
+
+			if def.NodeSpec.AWS.InstanceDistribution != nil {
+				b.NodeSpec.Aws.InstanceDistribution = &models.V5AddNodePoolRequestNodeSpecAwsInstanceDistribution{
+					OnDemandBaseCapacity:                &def.NodeSpec.AWS.InstanceDistribution.OnDemandBaseCapacity,
+					OnDemandPercentageAboveBaseCapacity: &def.NodeSpec.AWS.InstanceDistribution.OnDemandPercentageAboveBaseCapacity,
+				}
+			}
+
+			if def.NodeSpec.AWS.InstanceType != "" {
+				b.NodeSpec.Aws.InstanceType = def.NodeSpec.AWS.InstanceType
+			}
+
+			b.NodeSpec.Aws.UseAlikeInstanceTypes = &def.NodeSpec.AWS.UseAlikeInstanceTypes
+		}
+
+		if def.NodeSpec.Azure != nil {
+			b.NodeSpec.Azure = &models.V5AddNodePoolRequestNodeSpecAzure{}
+			if def.NodeSpec.Azure.VMSize != "" {
+				b.NodeSpec.Azure.VMSize = def.NodeSpec.Azure.VMSize
+			}
+			if def.NodeSpec.Azure.AzureSpotInstances != nil {
+				b.NodeSpec.Azure.SpotInstances = &models.V5AddNodePoolRequestNodeSpecAzureSpotInstances{
+					Enabled:  &def.NodeSpec.Azure.AzureSpotInstances.Enabled,
+					MaxPrice: &def.NodeSpec.Azure.AzureSpotInstances.MaxPrice,
+				}
+			}
+		}
+	}
+
+	return b
+}
+
+// DefaultNodePoolRequest creates the request for the node pool added to a new
+// cluster if the definition has no node pools. If the master node has no
+// availability zone, the node pool doesn't get one either.
+func DefaultNodePoolRequest(cluster *models.V5ClusterDetailsResponse) *models.V5AddNodePoolRequest {
+	b := &models.V5AddNodePoolRequest{}
+
+	if cluster.MasterNodes != nil && len(cluster.MasterNodes.AvailabilityZones) < 1 {
+		b.AvailabilityZones = &models.V5AddNodePoolRequestAvailabilityZones{
+			Number: -1,
+		}
+	}
+
+	return b
+}
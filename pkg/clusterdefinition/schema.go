@@ -0,0 +1,173 @@
+package clusterdefinition
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+const (
+	// SchemaVersionV4 identifies the schema of v4 cluster definitions.
+	SchemaVersionV4 = "v4"
+	// SchemaVersionV5 identifies the schema of v5 cluster definitions.
+	SchemaVersionV5 = "v5"
+
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+	schemaTypeArray   = "array"
+	schemaTypeBoolean = "boolean"
+	schemaTypeInteger = "integer"
+	schemaTypeNull    = "null"
+	schemaTypeNumber  = "number"
+	schemaTypeObject  = "object"
+	schemaTypeString  = "string"
+)
+
+// Schema is a (sub-)schema in the JSON Schema format, limited to the
+// keywords needed to describe cluster definitions.
+type Schema struct {
+	Schema string     `json:"$schema,omitempty"`
+	Title  string     `json:"title,omitempty"`
+	Type   SchemaType `json:"type,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties is either false or a *Schema.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	Items                *Schema     `json:"items,omitempty"`
+
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	Enum    []string `json:"enum,omitempty"`
+}
+
+// SchemaType is the list of types allowed for a value.
+type SchemaType []string
+
+// MarshalJSON renders a single type as a string, multiple types as an array.
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// allows returns true if the given type is allowed.
+func (t SchemaType) allows(typeName string) bool {
+	for _, name := range t {
+		if name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaV4 returns the JSON Schema for v4 cluster definitions.
+func SchemaV4() *Schema {
+	s := schemaForType(reflect.TypeOf(types.ClusterDefinitionV4{}))
+	s.Schema = jsonSchemaDraft
+	s.Title = "gsctl cluster definition (v4)"
+
+	return s
+}
+
+// SchemaV5 returns the JSON Schema for v5 cluster definitions.
+func SchemaV5() *Schema {
+	s := schemaForType(reflect.TypeOf(types.ClusterDefinitionV5{}))
+	s.Schema = jsonSchemaDraft
+	s.Title = "gsctl cluster definition (v5)"
+
+	return s
+}
+
+// SchemaForVersion returns the schema for the given definition version,
+// or nil if the version is unknown.
+func SchemaForVersion(version string) *Schema {
+	switch version {
+	case SchemaVersionV4:
+		return SchemaV4()
+	case SchemaVersionV5:
+		return SchemaV5()
+	}
+
+	return nil
+}
+
+// schemaForType derives a schema from a Go type, using the 'yaml' struct
+// tags for property names and the 'jsonschema' struct tags for constraints.
+func schemaForType(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		// A nil pointer is represented as null in YAML.
+		s := schemaForType(t.Elem())
+		s.Type = append(s.Type, schemaTypeNull)
+		return s
+	case reflect.Struct:
+		s := &Schema{
+			Type:                 SchemaType{schemaTypeObject},
+			Properties:           map[string]*Schema{},
+			AdditionalProperties: false,
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+
+			prop := schemaForType(field.Type)
+			applyConstraints(prop, field.Tag.Get("jsonschema"))
+			s.Properties[name] = prop
+		}
+		return s
+	case reflect.Map:
+		return &Schema{
+			Type:                 SchemaType{schemaTypeObject, schemaTypeNull},
+			AdditionalProperties: schemaForType(t.Elem()),
+		}
+	case reflect.Slice:
+		return &Schema{
+			Type:  SchemaType{schemaTypeArray, schemaTypeNull},
+			Items: schemaForType(t.Elem()),
+		}
+	case reflect.Bool:
+		return &Schema{Type: SchemaType{schemaTypeBoolean}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: SchemaType{schemaTypeInteger}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaType{schemaTypeNumber}}
+	}
+
+	return &Schema{Type: SchemaType{schemaTypeString}}
+}
+
+// applyConstraints sets constraints from a 'jsonschema' struct tag, which
+// has the form "minimum=0,maximum=100" or "enum=a|b".
+func applyConstraints(s *Schema, tag string) {
+	if tag == "" {
+		return
+	}
+
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "minimum":
+			if v, err := strconv.ParseFloat(kv[1], 64); err == nil {
+				s.Minimum = &v
+			}
+		case "maximum":
+			if v, err := strconv.ParseFloat(kv[1], 64); err == nil {
+				s.Maximum = &v
+			}
+		case "enum":
+			s.Enum = strings.Split(kv[1], "|")
+		}
+	}
+}
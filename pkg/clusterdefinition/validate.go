@@ -0,0 +1,241 @@
+package clusterdefinition
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	yamlTagBool  = "!!bool"
+	yamlTagFloat = "!!float"
+	yamlTagInt   = "!!int"
+	yamlTagNull  = "!!null"
+)
+
+// ValidationError is a single violation of the definition schema,
+// located by line and column in the YAML data. Values set via --set have
+// line 0.
+type ValidationError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) String() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("value set via --set: %s: %s", e.Path, e.Message)
+	}
+	if e.Path == "" {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// ValidationErrors is the list of all violations found in a definition.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, v := range e {
+		lines[i] = "  " + v.String()
+	}
+	return fmt.Sprintf("%d schema violation(s) found:\n%s", len(e), strings.Join(lines, "\n"))
+}
+
+// Validate checks YAML data against the given schema. It reports unknown
+// keys, values of the wrong type and values out of range, as well as
+// scaling ranges where min exceeds max. The returned error is of type
+// ValidationErrors if the data could be parsed, but violates the schema.
+func Validate(yamlBytes []byte, schema *Schema) error {
+	var doc yamlv3.Node
+	err := yamlv3.Unmarshal(yamlBytes, &doc)
+	if err != nil {
+		return microerror.Maskf(invalidDefinitionError, err.Error())
+	}
+
+	return ValidateDocument(&doc, schema)
+}
+
+// ValidateDocument checks a parsed YAML document against the given schema,
+// like Validate. Use it with the document returned by Render, so that the
+// errors point into the template.
+func ValidateDocument(doc *yamlv3.Node, schema *Schema) error {
+	var errs ValidationErrors
+	if len(doc.Content) > 0 {
+		validateNode(doc.Content[0], schema, "", &errs)
+		validateScalingRanges(doc.Content[0], "", &errs)
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool {
+			if errs[i].Line != errs[j].Line {
+				return errs[i].Line < errs[j].Line
+			}
+			return errs[i].Column < errs[j].Column
+		})
+		return errs
+	}
+
+	return nil
+}
+
+func validateNode(node *yamlv3.Node, s *Schema, path string, errs *ValidationErrors) {
+	if node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	typeName := nodeType(node, s.Type)
+	if !s.Type.allows(typeName) {
+		addError(errs, node, path, fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, " or "), typeName))
+		return
+	}
+
+	switch typeName {
+	case schemaTypeObject:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinPath(path, key.Value)
+
+			if prop, ok := s.Properties[key.Value]; ok {
+				validateNode(value, prop, childPath, errs)
+				continue
+			}
+
+			switch additional := s.AdditionalProperties.(type) {
+			case *Schema:
+				validateNode(value, additional, childPath, errs)
+			default:
+				addError(errs, key, childPath, "unknown key")
+			}
+		}
+	case schemaTypeArray:
+		for i, item := range node.Content {
+			validateNode(item, s.Items, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case schemaTypeInteger, schemaTypeNumber:
+		value, err := strconv.ParseFloat(strings.ReplaceAll(node.Value, "_", ""), 64)
+		if err != nil {
+			// Non-decimal notations like 0x10 are left to the decoder.
+			return
+		}
+		if s.Minimum != nil && value < *s.Minimum {
+			addError(errs, node, path, fmt.Sprintf("value %s is below the minimum of %v", node.Value, *s.Minimum))
+		}
+		if s.Maximum != nil && value > *s.Maximum {
+			addError(errs, node, path, fmt.Sprintf("value %s is above the maximum of %v", node.Value, *s.Maximum))
+		}
+	case schemaTypeString:
+		if len(s.Enum) > 0 && !contains(s.Enum, node.Value) {
+			addError(errs, node, path, fmt.Sprintf("value '%s' is not one of '%s'", node.Value, strings.Join(s.Enum, "', '")))
+		}
+	}
+}
+
+// nodeType maps a YAML node to a JSON Schema type name. Plain scalars are
+// accepted as strings where strings are allowed, since the decoder accepts
+// e. g. release_version: 14.0 as the string "14.0".
+func nodeType(node *yamlv3.Node, allowed SchemaType) string {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		return schemaTypeObject
+	case yamlv3.SequenceNode:
+		return schemaTypeArray
+	}
+
+	switch node.Tag {
+	case yamlTagNull:
+		return schemaTypeNull
+	case yamlTagBool:
+		if allowed.allows(schemaTypeString) {
+			return schemaTypeString
+		}
+		return schemaTypeBoolean
+	case yamlTagInt:
+		if allowed.allows(schemaTypeString) {
+			return schemaTypeString
+		}
+		if allowed.allows(schemaTypeNumber) {
+			return schemaTypeNumber
+		}
+		return schemaTypeInteger
+	case yamlTagFloat:
+		if allowed.allows(schemaTypeString) {
+			return schemaTypeString
+		}
+		return schemaTypeNumber
+	}
+
+	return schemaTypeString
+}
+
+// validateScalingRanges reports 'scaling' blocks where min is greater than max,
+// which can't be expressed in the schema.
+func validateScalingRanges(node *yamlv3.Node, path string, errs *ValidationErrors) {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinPath(path, key.Value)
+
+			if key.Value == "scaling" && value.Kind == yamlv3.MappingNode {
+				min, minOK := intValue(value, "min")
+				max, maxOK := intValue(value, "max")
+				if minOK && maxOK && min > max {
+					addError(errs, value, childPath, fmt.Sprintf("min (%d) must not be greater than max (%d)", min, max))
+				}
+				continue
+			}
+
+			validateScalingRanges(value, childPath, errs)
+		}
+	case yamlv3.SequenceNode:
+		for i, item := range node.Content {
+			validateScalingRanges(item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// intValue returns the integer value of the given key in a mapping node.
+func intValue(mapping *yamlv3.Node, key string) (int64, bool) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+
+		value, err := strconv.ParseInt(mapping.Content[i+1].Value, 10, 64)
+		return value, err == nil
+	}
+
+	return 0, false
+}
+
+func addError(errs *ValidationErrors, node *yamlv3.Node, path, message string) {
+	*errs = append(*errs, ValidationError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: message,
+	})
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
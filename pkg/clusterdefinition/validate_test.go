@@ -0,0 +1,160 @@
+package clusterdefinition
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		yaml     string
+		schema   *Schema
+		expected ValidationErrors
+	}{
+		{
+			name: "valid v5",
+			yaml: `api_version: v5
+owner: acme
+release_version: 11.0
+labels:
+  env: dev
+  obsolete: null
+master_nodes:
+  high_availability: true
+nodepools:
+- name: workers
+  availability_zones:
+    number: 2
+  scaling:
+    min: 3
+    max: 10
+  node_spec:
+    aws:
+      instance_type: m5.xlarge
+      instance_distribution:
+        on_demand_base_capacity: 1
+        on_demand_percentage_above_base_capacity: 50
+`,
+			schema: SchemaV5(),
+		},
+		{
+			name: "valid v4",
+			yaml: `owner: acme
+scaling:
+  min: 3
+  max: 3
+workers:
+- memory:
+    size_gb: 16.5
+  cpu:
+    cores: 4
+`,
+			schema: SchemaV4(),
+		},
+		{
+			name: "unknown keys",
+			yaml: `api_version: v5
+owner: acme
+nodepools:
+- name: workers
+  node_specs:
+    aws:
+      instance_type: m5.xlarge
+`,
+			schema: SchemaV5(),
+			expected: ValidationErrors{
+				{Line: 5, Column: 3, Path: "nodepools[0].node_specs", Message: "unknown key"},
+			},
+		},
+		{
+			name: "wrong types",
+			yaml: `api_version: v5
+owner: [acme]
+master_nodes:
+  high_availability: maybe
+nodepools:
+  name: workers
+`,
+			schema: SchemaV5(),
+			expected: ValidationErrors{
+				{Line: 2, Column: 8, Path: "owner", Message: "expected string, got array"},
+				{Line: 4, Column: 22, Path: "master_nodes.high_availability", Message: "expected boolean or null, got string"},
+				{Line: 6, Column: 3, Path: "nodepools", Message: "expected array or null, got object"},
+			},
+		},
+		{
+			name: "out of range",
+			yaml: `api_version: v6
+nodepools:
+- name: workers
+  scaling:
+    min: 10
+    max: 3
+  node_spec:
+    aws:
+      instance_distribution:
+        on_demand_base_capacity: -1
+        on_demand_percentage_above_base_capacity: 120
+`,
+			schema: SchemaV5(),
+			expected: ValidationErrors{
+				{Line: 1, Column: 14, Path: "api_version", Message: "value 'v6' is not one of 'v5'"},
+				{Line: 5, Column: 5, Path: "nodepools[0].scaling", Message: "min (10) must not be greater than max (3)"},
+				{Line: 10, Column: 34, Path: "nodepools[0].node_spec.aws.instance_distribution.on_demand_base_capacity", Message: "value -1 is below the minimum of 0"},
+				{Line: 11, Column: 51, Path: "nodepools[0].node_spec.aws.instance_distribution.on_demand_percentage_above_base_capacity", Message: "value 120 is above the maximum of 100"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate([]byte(tc.yaml), tc.schema)
+			if tc.expected == nil {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+
+			validationErrors, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %#v", err)
+			}
+			if diff := cmp.Diff(tc.expected, validationErrors); diff != "" {
+				t.Errorf("Validation errors not as expected (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidYAML(t *testing.T) {
+	err := Validate([]byte("owner: [acme"), SchemaV5())
+	if !IsInvalidDefinition(err) {
+		t.Errorf("Expected invalidDefinitionError, got %#v", err)
+	}
+}
+
+func TestSchemaV5(t *testing.T) {
+	schema := SchemaV5()
+
+	// The schema must be serializable and must contain constraints
+	// defined via struct tags.
+	_, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	scaling := schema.Properties["nodepools"].Items.Properties["scaling"]
+	if scaling.Properties["min"].Minimum == nil || *scaling.Properties["min"].Minimum != 0 {
+		t.Errorf("Expected minimum 0 for nodepools[].scaling.min")
+	}
+	if schema.AdditionalProperties != false {
+		t.Errorf("Expected additional properties to be forbidden")
+	}
+	if diff := cmp.Diff([]string{"v5"}, schema.Properties["api_version"].Enum); diff != "" {
+		t.Errorf("Enum not as expected (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,237 @@
+package clusterwait
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+)
+
+const (
+	// ConditionTypeCondition waits for a cluster status condition like
+	// 'Created' to be true.
+	ConditionTypeCondition = "condition"
+
+	// ConditionTypeNodesReady waits for a number of ready worker nodes.
+	ConditionTypeNodesReady = "nodes-ready"
+
+	// ConditionTypeDeleted waits for a cluster or node pool to disappear.
+	ConditionTypeDeleted = "deleted"
+
+	// ConditionTypeVersion waits for the cluster status to list a release version.
+	ConditionTypeVersion = "version"
+
+	// NodesReadyMin is the nodes-ready value standing for the minimum
+	// scaling of the node pools.
+	NodesReadyMin = "min"
+)
+
+// Condition is a state to wait for, parsed from an expression like
+// 'condition=Created', 'nodes-ready=5', 'nodes-ready=min' or 'deleted'.
+type Condition struct {
+	Type  string
+	Value string
+}
+
+// ParseCondition parses a condition expression.
+func ParseCondition(expression string) (Condition, error) {
+	parts := strings.SplitN(strings.TrimSpace(expression), "=", 2)
+	c := Condition{Type: strings.ToLower(parts[0])}
+	if len(parts) == 2 {
+		c.Value = strings.TrimSpace(parts[1])
+	}
+
+	switch c.Type {
+	case ConditionTypeDeleted:
+		if len(parts) == 2 {
+			return c, microerror.Maskf(invalidConditionError, "'%s' does not take a value", ConditionTypeDeleted)
+		}
+	case ConditionTypeCondition, ConditionTypeVersion:
+		if c.Value == "" {
+			return c, microerror.Maskf(invalidConditionError, "'%s' needs a value, e. g. '%s=...'", c.Type, c.Type)
+		}
+	case ConditionTypeNodesReady:
+		if c.Value == NodesReadyMin {
+			break
+		}
+		n, err := strconv.Atoi(c.Value)
+		if err != nil || n < 0 {
+			return c, microerror.Maskf(invalidConditionError, "'%s' needs a number of nodes or '%s', got '%s'", ConditionTypeNodesReady, NodesReadyMin, c.Value)
+		}
+	case "":
+		return c, microerror.Maskf(invalidConditionError, "the condition must not be empty")
+	default:
+		return c, microerror.Maskf(invalidConditionError, "unknown condition '%s', use one of '%s', '%s', '%s' or '%s'", parts[0], ConditionTypeCondition, ConditionTypeNodesReady, ConditionTypeVersion, ConditionTypeDeleted)
+	}
+
+	return c, nil
+}
+
+// String returns the condition as an expression.
+func (c Condition) String() string {
+	if c.Value == "" {
+		return c.Type
+	}
+
+	return c.Type + "=" + c.Value
+}
+
+// requiredNodes returns the number of ready nodes required by a
+// nodes-ready condition, which is desired when waiting for the minimum.
+func (c Condition) requiredNodes(desired int) int {
+	if c.Value == NodesReadyMin {
+		return desired
+	}
+
+	n, _ := strconv.Atoi(c.Value)
+	return n
+}
+
+// WorkerNodesReady returns the number of ready worker nodes, summed up over
+// all node pools.
+func (r *Readiness) WorkerNodesReady() int {
+	ready := r.WorkersReady
+	for _, np := range r.NodePools {
+		ready += np.Ready
+	}
+
+	return ready
+}
+
+// trueConditions returns the types of all conditions of the cluster status
+// which are true, e. g. "Created".
+func trueConditions(status *v1alpha1.StatusCluster) []string {
+	var types []string
+	if status == nil {
+		return types
+	}
+
+	for _, c := range status.Conditions {
+		if c.Status == v1alpha1.StatusClusterStatusTrue {
+			types = append(types, c.Type)
+		}
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// UntilCluster waits until the cluster fulfills the condition.
+func UntilCluster(c Config, condition Condition) error {
+	if condition.Type == ConditionTypeDeleted {
+		return microerror.Mask(UntilDeleted(c))
+	}
+
+	err := c.poll(func() (bool, string, error) {
+		r, err := FetchReadiness(c.ClientWrapper, c.AuxParams, c.ClusterID)
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		switch condition.Type {
+		case ConditionTypeCondition:
+			conditions := trueConditions(r.Status)
+			for _, t := range conditions {
+				if strings.EqualFold(t, condition.Value) {
+					return true, "", nil
+				}
+			}
+			if len(conditions) == 0 {
+				return false, "no conditions", nil
+			}
+			return false, fmt.Sprintf("conditions: %s", strings.Join(conditions, ", ")), nil
+
+		case ConditionTypeVersion:
+			done := r.Status != nil && r.Status.HasVersion(condition.Value)
+			return done, fmt.Sprintf("release %s", stringOrPlaceholder(r.Version)), nil
+
+		case ConditionTypeNodesReady:
+			if condition.Value == NodesReadyMin {
+				return r.NodesReady(), r.String(), nil
+			}
+			ready, required := r.WorkerNodesReady(), condition.requiredNodes(0)
+			return ready >= required, fmt.Sprintf("worker nodes ready: %d/%d", ready, required), nil
+		}
+
+		return false, "", microerror.Maskf(invalidConditionError, "condition '%s' is not supported for clusters", condition)
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// FetchNodePoolReadiness fetches the number of ready nodes of a node pool.
+// If the node pool does not exist, the error returned satisfies
+// clienterror.IsNotFoundError.
+func FetchNodePoolReadiness(clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams, clusterID, nodePoolID string) (*NodePoolReadiness, error) {
+	response, err := clientWrapper.GetNodePool(clusterID, nodePoolID, auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	np := response.Payload
+	r := &NodePoolReadiness{ID: np.ID, Name: np.Name}
+	if np.Scaling != nil && np.Scaling.Min != nil {
+		r.Desired = int(*np.Scaling.Min)
+	}
+	if np.Status != nil {
+		r.Ready = int(np.Status.NodesReady)
+	}
+
+	return r, nil
+}
+
+// ValidateForNodePool returns an error if the condition can't be used for
+// node pools. Only 'nodes-ready' and 'deleted' are supported.
+func (c Condition) ValidateForNodePool() error {
+	if c.Type != ConditionTypeNodesReady && c.Type != ConditionTypeDeleted {
+		return microerror.Maskf(invalidConditionError, "condition '%s' is not supported for node pools, use '%s' or '%s'", c, ConditionTypeNodesReady, ConditionTypeDeleted)
+	}
+
+	return nil
+}
+
+// UntilNodePool waits until the node pool fulfills the condition.
+func UntilNodePool(c Config, nodePoolID string, condition Condition) error {
+	err := condition.ValidateForNodePool()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = c.poll(func() (bool, string, error) {
+		np, err := FetchNodePoolReadiness(c.ClientWrapper, c.AuxParams, c.ClusterID, nodePoolID)
+		if condition.Type == ConditionTypeDeleted && clienterror.IsNotFoundError(err) {
+			return true, "", nil
+		} else if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		if condition.Type == ConditionTypeDeleted {
+			return false, fmt.Sprintf("deletion in progress, node pool %s workers ready: %d", np.ID, np.Ready), nil
+		}
+
+		required := condition.requiredNodes(np.Desired)
+		return np.Ready >= required, fmt.Sprintf("node pool %s workers ready: %d/%d", np.ID, np.Ready, required), nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func stringOrPlaceholder(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+
+	return s
+}
@@ -0,0 +1,213 @@
+package clusterwait
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseCondition(t *testing.T) {
+	var testCases = []struct {
+		expression string
+		expected   Condition
+		valid      bool
+	}{
+		{"condition=Created", Condition{Type: ConditionTypeCondition, Value: "Created"}, true},
+		{"Nodes-Ready=5", Condition{Type: ConditionTypeNodesReady, Value: "5"}, true},
+		{"nodes-ready=min", Condition{Type: ConditionTypeNodesReady, Value: NodesReadyMin}, true},
+		{"version=13.0.0", Condition{Type: ConditionTypeVersion, Value: "13.0.0"}, true},
+		{"deleted", Condition{Type: ConditionTypeDeleted}, true},
+		{"", Condition{}, false},
+		{"deleted=true", Condition{}, false},
+		{"condition=", Condition{}, false},
+		{"nodes-ready=-1", Condition{}, false},
+		{"nodes-ready=max", Condition{}, false},
+		{"ready", Condition{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			c, err := ParseCondition(tc.expression)
+			if !tc.valid {
+				if !IsInvalidCondition(err) {
+					t.Errorf("Expected invalidConditionError, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if c != tc.expected {
+				t.Errorf("Expected %#v, got %#v", tc.expected, c)
+			}
+		})
+	}
+
+	c := Condition{Type: ConditionTypeCondition, Value: "Created"}
+	if !IsInvalidCondition(c.ValidateForNodePool()) {
+		t.Error("Expected 'condition' to be invalid for node pools")
+	}
+}
+
+// Test_UntilCluster tests waiting for cluster conditions and versions.
+func Test_UntilCluster(t *testing.T) {
+	var mutex sync.Mutex
+	polls := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/f01r4/status/":
+			polls++
+			condition := "Creating"
+			versions := `[]`
+			if polls > 1 {
+				condition = "Created"
+				versions = `[{"semver": "13.0.0", "lastTransitionTime": "2020-01-01T00:00:00Z"}]`
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(`{"cluster": {"conditions": [{"status": "True", "type": "%s"}], "versions": %s}}`, condition, versions)))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/f01r4/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "f01r4", "master_nodes": {"high_availability": true, "num_ready": 3}}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "a7k4", "scaling": {"min": 2, "max": 3}, "status": {"nodes_ready": 2}},
+				{"id": "b8l5", "scaling": {"min": 3, "max": 3}, "status": {"nodes_ready": 1}}
+			]`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	clientWrapper, auxParams := newClient(t, mockServer.URL)
+
+	var testCases = []struct {
+		condition        string
+		expectedProgress []string
+		expectTimeout    bool
+	}{
+		{
+			condition:        "condition=created",
+			expectedProgress: []string{"conditions: Creating"},
+		},
+		{
+			condition:        "version=13.0.0",
+			expectedProgress: []string{"release n/a", "release 13.0.0"},
+		},
+		{
+			condition:        "nodes-ready=3",
+			expectedProgress: []string{"worker nodes ready: 3/3"},
+		},
+		{
+			condition: "nodes-ready=min",
+			expectedProgress: []string{
+				"masters ready: 3/3, node pool a7k4 workers ready: 2/2, node pool b8l5 workers ready: 1/3",
+				"masters ready: 3/3, node pool a7k4 workers ready: 2/2, node pool b8l5 workers ready: 1/3, release 13.0.0",
+			},
+			expectTimeout: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.condition, func(t *testing.T) {
+			polls = 0
+
+			condition, err := ParseCondition(tc.condition)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var progress []string
+			err = UntilCluster(Config{
+				ClientWrapper: clientWrapper,
+				AuxParams:     auxParams,
+				ClusterID:     "f01r4",
+				Timeout:       50 * time.Millisecond,
+				Interval:      time.Millisecond,
+				Progress: func(status string) {
+					progress = append(progress, status)
+				},
+			}, condition)
+
+			if tc.expectTimeout {
+				if !IsTimeout(err) {
+					t.Errorf("Expected timeoutError, got %#v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+
+			if fmt.Sprint(progress) != fmt.Sprint(tc.expectedProgress) {
+				t.Errorf("Expected progress %q, got %q", tc.expectedProgress, progress)
+			}
+		})
+	}
+}
+
+// Test_UntilNodePool tests waiting for node pool readiness and deletion.
+func Test_UntilNodePool(t *testing.T) {
+	var mutex sync.Mutex
+	polls := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != "GET" || r.URL.String() != "/v5/clusters/f01r4/nodepools/a7k4/" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		polls++
+		if polls > 3 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "node pool not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf(`{"id": "a7k4", "scaling": {"min": 2, "max": 3}, "status": {"nodes_ready": %d}}`, polls)))
+	}))
+	defer mockServer.Close()
+
+	clientWrapper, auxParams := newClient(t, mockServer.URL)
+	config := Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     "f01r4",
+		Timeout:       5 * time.Second,
+		Interval:      time.Millisecond,
+	}
+
+	err := UntilNodePool(config, "a7k4", Condition{Type: ConditionTypeNodesReady, Value: NodesReadyMin})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if polls != 2 {
+		t.Errorf("Expected 2 polls until ready, got %d", polls)
+	}
+
+	err = UntilNodePool(config, "a7k4", Condition{Type: ConditionTypeDeleted})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if polls != 4 {
+		t.Errorf("Expected 4 polls until deleted, got %d", polls)
+	}
+
+	err = UntilNodePool(config, "a7k4", Condition{Type: ConditionTypeVersion, Value: "1.0.0"})
+	if !IsInvalidCondition(err) {
+		t.Errorf("Expected invalidConditionError, got %#v", err)
+	}
+}
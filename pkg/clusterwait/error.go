@@ -0,0 +1,22 @@
+package clusterwait
+
+import "github.com/giantswarm/microerror"
+
+var timeoutError = &microerror.Error{
+	Kind: "timeoutError",
+	Desc: "The cluster did not reach the expected state within the given time.",
+}
+
+// IsTimeout asserts timeoutError.
+func IsTimeout(err error) bool {
+	return microerror.Cause(err) == timeoutError
+}
+
+var invalidConditionError = &microerror.Error{
+	Kind: "invalidConditionError",
+}
+
+// IsInvalidCondition asserts invalidConditionError.
+func IsInvalidCondition(err error) bool {
+	return microerror.Cause(err) == invalidConditionError
+}
@@ -0,0 +1,192 @@
+// Package clusterwait provides functions to wait for a cluster to become
+// ready or to disappear, e. g. after creating, upgrading or deleting it.
+package clusterwait
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+)
+
+// Readiness is a snapshot of the number of ready nodes of a cluster.
+type Readiness struct {
+	// ClusterID is the ID of the cluster.
+	ClusterID string
+
+	// MastersReady is the number of ready master nodes. MastersDesired is
+	// zero if the number of master nodes is not known.
+	MastersReady   int
+	MastersDesired int
+
+	// WorkersReady and WorkersDesired are only set for clusters without
+	// node pools.
+	WorkersReady   int
+	WorkersDesired int
+
+	// NodePools contains readiness details per node pool.
+	NodePools []NodePoolReadiness
+
+	// Version is the latest release version the cluster has reached,
+	// according to its status. Empty if no status is available yet.
+	Version string
+
+	// Status is the cluster status, or nil if not available yet.
+	Status *v1alpha1.StatusCluster
+}
+
+// NodePoolReadiness is the number of ready nodes in a node pool.
+type NodePoolReadiness struct {
+	ID      string
+	Name    string
+	Ready   int
+	Desired int
+}
+
+// NodesReady returns true if all master nodes, workers and node pools
+// have at least the desired number of ready nodes.
+func (r *Readiness) NodesReady() bool {
+	if r.MastersReady < r.MastersDesired || r.WorkersReady < r.WorkersDesired {
+		return false
+	}
+
+	for _, np := range r.NodePools {
+		if np.Ready < np.Desired {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns a one-line summary of the node readiness counts,
+// e. g. "masters ready: 1/1, node pool a7k4 workers ready: 2/3".
+func (r *Readiness) String() string {
+	var parts []string
+
+	if r.MastersDesired > 0 {
+		parts = append(parts, fmt.Sprintf("masters ready: %d/%d", r.MastersReady, r.MastersDesired))
+	}
+
+	if r.NodePools == nil {
+		parts = append(parts, fmt.Sprintf("workers ready: %d/%d", r.WorkersReady, r.WorkersDesired))
+	} else if len(r.NodePools) == 0 {
+		parts = append(parts, "no node pools")
+	}
+
+	for _, np := range r.NodePools {
+		parts = append(parts, fmt.Sprintf("node pool %s workers ready: %d/%d", np.ID, np.Ready, np.Desired))
+	}
+
+	if r.Version != "" {
+		parts = append(parts, fmt.Sprintf("release %s", r.Version))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// IsMasterNode returns true if the node from a cluster's status is
+// labelled as a master node.
+func IsMasterNode(node v1alpha1.StatusClusterNode) bool {
+	val, ok := node.Labels["role"]
+	if !ok {
+		// Workaround for k8s 1.14 because the label changed.
+		val, ok = node.Labels["kubernetes.io/role"]
+	}
+
+	return ok && val == "master"
+}
+
+// CountNodes returns the number of master and worker nodes listed in
+// the cluster status.
+func CountNodes(status *client.ClusterStatus) (masters int, workers int) {
+	if status == nil || status.Cluster == nil {
+		return 0, 0
+	}
+
+	for _, node := range status.Cluster.Nodes {
+		if IsMasterNode(node) {
+			masters++
+		} else {
+			workers++
+		}
+	}
+
+	return masters, workers
+}
+
+// FetchReadiness fetches the current node readiness of a cluster. Clusters
+// with node pools are read via the v5 API, others via the v4 API and the
+// cluster status. If the cluster does not exist, the error returned
+// satisfies clienterror.IsNotFoundError.
+func FetchReadiness(clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams, clusterID string) (*Readiness, error) {
+	r := &Readiness{ClusterID: clusterID}
+
+	// The status might not exist yet while a cluster is being created.
+	status, err := clientWrapper.GetClusterStatus(clusterID, auxParams)
+	if err != nil && !clienterror.IsNotFoundError(err) {
+		return nil, microerror.Mask(err)
+	}
+	if status != nil && status.Cluster != nil {
+		r.Status = status.Cluster
+		r.Version = status.Cluster.LatestVersion()
+	}
+
+	responseV5, err := clientWrapper.GetClusterV5(clusterID, auxParams)
+	if err == nil {
+		masterNodes := responseV5.Payload.MasterNodes
+		if masterNodes != nil {
+			r.MastersDesired = 1
+			if masterNodes.HighAvailability {
+				r.MastersDesired = 3
+			}
+			if masterNodes.NumReady != nil && *masterNodes.NumReady > 0 {
+				r.MastersReady = int(*masterNodes.NumReady)
+			}
+		}
+
+		nodePoolsResponse, err := clientWrapper.GetNodePools(clusterID, auxParams)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		r.NodePools = []NodePoolReadiness{}
+		for _, np := range nodePoolsResponse.Payload {
+			item := NodePoolReadiness{ID: np.ID, Name: np.Name}
+			if np.Scaling != nil && np.Scaling.Min != nil {
+				item.Desired = int(*np.Scaling.Min)
+			}
+			if np.Status != nil {
+				item.Ready = int(np.Status.NodesReady)
+			}
+			r.NodePools = append(r.NodePools, item)
+		}
+
+		return r, nil
+	}
+
+	// A 404 or 400 response means that this is not a v5 cluster (or that
+	// it doesn't exist, which we'll learn from the v4 request).
+	if !clienterror.IsNotFoundError(err) && !clienterror.IsBadRequestError(err) && !clienterror.IsMalformedResponse(err) {
+		return nil, microerror.Mask(err)
+	}
+
+	responseV4, err := clientWrapper.GetClusterV4(clusterID, auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.MastersDesired = 1
+	r.MastersReady, r.WorkersReady = CountNodes(status)
+	if responseV4.Payload.Scaling != nil && responseV4.Payload.Scaling.Min != nil {
+		r.WorkersDesired = int(*responseV4.Payload.Scaling.Min)
+	} else {
+		r.WorkersDesired = len(responseV4.Payload.Workers)
+	}
+
+	return r, nil
+}
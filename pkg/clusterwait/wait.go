@@ -0,0 +1,122 @@
+package clusterwait
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+)
+
+const (
+	// DefaultTimeout is the time to wait for a cluster, unless specified otherwise.
+	DefaultTimeout = 30 * time.Minute
+
+	// DefaultInterval is the time between two status requests.
+	DefaultInterval = 15 * time.Second
+)
+
+// Config configures waiting for a cluster.
+type Config struct {
+	ClientWrapper *client.Wrapper
+	AuxParams     *client.AuxiliaryParams
+	ClusterID     string
+
+	// Timeout is the maximum time to wait. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Interval is the time between two polls. Defaults to DefaultInterval.
+	Interval time.Duration
+
+	// Progress, if set, gets called with a status line whenever the
+	// status has changed since the last poll.
+	Progress func(status string)
+}
+
+func (c *Config) setDefaults() {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+}
+
+// poll calls check every c.Interval until it returns true or an error,
+// or until c.Timeout has passed. Status lines returned by check are
+// passed to c.Progress if they differ from the previous one.
+func (c Config) poll(check func() (bool, string, error)) error {
+	c.setDefaults()
+
+	deadline := time.Now().Add(c.Timeout)
+	lastStatus := ""
+
+	for {
+		done, status, err := check()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if c.Progress != nil && status != "" && status != lastStatus {
+			c.Progress(status)
+		}
+		lastStatus = status
+
+		if done {
+			return nil
+		}
+
+		if time.Now().Add(c.Interval).After(deadline) {
+			if lastStatus != "" {
+				return microerror.Maskf(timeoutError, "cluster '%s' not done after %s (%s)", c.ClusterID, c.Timeout, lastStatus)
+			}
+			return microerror.Maskf(timeoutError, "cluster '%s' not done after %s", c.ClusterID, c.Timeout)
+		}
+
+		time.Sleep(c.Interval)
+	}
+}
+
+// UntilReady waits until all master nodes and node pools (or workers, for
+// clusters without node pools) of the cluster report the desired number of
+// ready nodes. If version is not empty, it also waits until the cluster
+// status reports this release version, e. g. after an upgrade.
+func UntilReady(c Config, version string) (*Readiness, error) {
+	var readiness *Readiness
+
+	err := c.poll(func() (bool, string, error) {
+		r, err := FetchReadiness(c.ClientWrapper, c.AuxParams, c.ClusterID)
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		readiness = r
+
+		return r.NodesReady() && (version == "" || r.Version == version), r.String(), nil
+	})
+	if err != nil {
+		return readiness, microerror.Mask(err)
+	}
+
+	return readiness, nil
+}
+
+// UntilDeleted waits until the cluster can no longer be found.
+func UntilDeleted(c Config) error {
+	err := c.poll(func() (bool, string, error) {
+		r, err := FetchReadiness(c.ClientWrapper, c.AuxParams, c.ClusterID)
+		if clienterror.IsNotFoundError(err) {
+			return true, "", nil
+		} else if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		return false, fmt.Sprintf("deletion in progress, %s", r.String()), nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
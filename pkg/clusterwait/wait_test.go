@@ -0,0 +1,202 @@
+package clusterwait
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsctl/client"
+)
+
+// newClient returns a client for the given mock server.
+func newClient(t *testing.T, url string) (*client.Wrapper, *client.AuxiliaryParams) {
+	clientWrapper, err := client.New(&client.Configuration{Endpoint: url})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return clientWrapper, clientWrapper.DefaultAuxiliaryParams()
+}
+
+// Test_UntilReadyV5 tests waiting for a node pool cluster whose nodes
+// become ready one poll after the other.
+func Test_UntilReadyV5(t *testing.T) {
+	var mutex sync.Mutex
+	polls := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/f01r4/status/":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "status not yet available"}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/f01r4/":
+			polls++
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(`{"id": "f01r4", "master_nodes": {"high_availability": false, "num_ready": %d}}`, min(polls-1, 1))))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(`[{"id": "a7k4", "scaling": {"min": 2, "max": 3}, "status": {"nodes": 2, "nodes_ready": %d}}]`, min(polls-1, 2))))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	clientWrapper, auxParams := newClient(t, mockServer.URL)
+
+	var progress []string
+	readiness, err := UntilReady(Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     "f01r4",
+		Timeout:       5 * time.Second,
+		Interval:      time.Millisecond,
+		Progress: func(status string) {
+			progress = append(progress, status)
+		},
+	}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !readiness.NodesReady() || polls != 3 {
+		t.Errorf("Expected ready cluster after 3 polls, got %d polls and %#v", polls, readiness)
+	}
+
+	expected := []string{
+		"masters ready: 0/1, node pool a7k4 workers ready: 0/2",
+		"masters ready: 1/1, node pool a7k4 workers ready: 1/2",
+		"masters ready: 1/1, node pool a7k4 workers ready: 2/2",
+	}
+	if fmt.Sprint(progress) != fmt.Sprint(expected) {
+		t.Errorf("Expected progress %q, got %q", expected, progress)
+	}
+}
+
+// Test_UntilReadyV4Timeout tests waiting for a v4 cluster which does not
+// reach the expected release version in time.
+func Test_UntilReadyV4Timeout(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/v4c1u/status/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"cluster": {
+				"nodes": [
+					{"name": "master-0", "labels": {"role": "master"}},
+					{"name": "worker-0", "labels": {"role": "worker"}},
+					{"name": "worker-1", "labels": {"kubernetes.io/role": "worker"}}
+				],
+				"versions": [{"semver": "8.0.0", "lastTransitionTime": "2019-01-01T00:00:00Z"}]
+			}}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/v4c1u/":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "not a v5 cluster"}`))
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/v4c1u/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "v4c1u", "scaling": {"min": 2, "max": 2}, "workers": [{}, {}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	clientWrapper, auxParams := newClient(t, mockServer.URL)
+	config := Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     "v4c1u",
+		Timeout:       20 * time.Millisecond,
+		Interval:      5 * time.Millisecond,
+	}
+
+	readiness, err := UntilReady(config, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if readiness.String() != "masters ready: 1/1, workers ready: 2/2, release 8.0.0" {
+		t.Errorf("Unexpected readiness '%s'", readiness.String())
+	}
+
+	_, err = UntilReady(config, "9.0.0")
+	if !IsTimeout(err) {
+		t.Errorf("Expected timeoutError, got %#v", err)
+	}
+}
+
+// Test_UntilDeleted tests waiting for a cluster to disappear.
+func Test_UntilDeleted(t *testing.T) {
+	var mutex sync.Mutex
+	polls := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/d3l3t/status/":
+			polls++
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "not found"}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/d3l3t/":
+			if polls > 2 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "not found"}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "d3l3t"}`))
+		case r.Method == "GET" && r.URL.String() == "/v5/clusters/d3l3t/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		case r.Method == "GET" && r.URL.String() == "/v4/clusters/d3l3t/":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "not found"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	clientWrapper, auxParams := newClient(t, mockServer.URL)
+
+	var progress []string
+	err := UntilDeleted(Config{
+		ClientWrapper: clientWrapper,
+		AuxParams:     auxParams,
+		ClusterID:     "d3l3t",
+		Timeout:       5 * time.Second,
+		Interval:      time.Millisecond,
+		Progress: func(status string) {
+			progress = append(progress, status)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if polls != 3 {
+		t.Errorf("Expected 3 polls, got %d", polls)
+	}
+	// Unchanged status lines are only reported once.
+	if len(progress) != 1 || progress[0] != "deletion in progress, no node pools" {
+		t.Errorf("Unexpected progress %q", progress)
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,60 @@
+// Package colormode decides whether gsctl prints colored output. Colors are
+// disabled via the --no-color flag, the NO_COLOR environment variable, when
+// stdout is not a terminal, and on Windows, where coloring is super slow.
+package colormode
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	// EnvNoColor disables colors when set to a non-empty value.
+	// See https://no-color.org/.
+	EnvNoColor = "NO_COLOR"
+	// EnvDisableColors is the gsctl specific variable to disable colors,
+	// kept for backwards compatibility.
+	EnvDisableColors = "GSCTL_DISABLE_COLORS"
+)
+
+// Settings is everything which determines whether colors are used.
+type Settings struct {
+	// NoColor is the value of the --no-color flag.
+	NoColor bool
+	// Getenv returns the value of an environment variable.
+	Getenv func(string) string
+	// GOOS is the operating system, as in runtime.GOOS.
+	GOOS string
+	// IsTerminal is true if stdout is a terminal.
+	IsTerminal bool
+}
+
+// Disabled returns true if output must not be colored.
+func Disabled(s Settings) bool {
+	if s.NoColor || !s.IsTerminal || s.GOOS == "windows" {
+		return true
+	}
+
+	if s.Getenv != nil {
+		if s.Getenv(EnvNoColor) != "" || s.Getenv(EnvDisableColors) != "" || s.Getenv("TERM") == "dumb" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Configure enables or disables colors for all output printed via
+// github.com/fatih/color, which includes table headers, confirmation
+// prompts and notices. noColor is the value of the --no-color flag.
+func Configure(noColor bool) {
+	color.NoColor = Disabled(Settings{
+		NoColor:    noColor,
+		Getenv:     os.Getenv,
+		GOOS:       runtime.GOOS,
+		IsTerminal: isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()),
+	})
+}
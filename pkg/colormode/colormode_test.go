@@ -0,0 +1,37 @@
+package colormode
+
+import (
+	"strconv"
+	"testing"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	testCases := []struct {
+		settings Settings
+		expected bool
+	}{
+		{Settings{Getenv: env(nil), GOOS: "linux", IsTerminal: true}, false},
+		{Settings{GOOS: "darwin", IsTerminal: true}, false},
+		{Settings{NoColor: true, Getenv: env(nil), GOOS: "linux", IsTerminal: true}, true},
+		{Settings{Getenv: env(nil), GOOS: "linux", IsTerminal: false}, true},
+		{Settings{Getenv: env(nil), GOOS: "windows", IsTerminal: true}, true},
+		{Settings{Getenv: env(map[string]string{"NO_COLOR": "1"}), GOOS: "linux", IsTerminal: true}, true},
+		{Settings{Getenv: env(map[string]string{"NO_COLOR": ""}), GOOS: "linux", IsTerminal: true}, false},
+		{Settings{Getenv: env(map[string]string{"GSCTL_DISABLE_COLORS": "true"}), GOOS: "linux", IsTerminal: true}, true},
+		{Settings{Getenv: env(map[string]string{"TERM": "dumb"}), GOOS: "linux", IsTerminal: true}, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if got := Disabled(tc.settings); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
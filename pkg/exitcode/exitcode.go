@@ -0,0 +1,77 @@
+// Package exitcode defines the exit codes of gsctl. They allow scripts to
+// tell classes of errors apart, e. g. a cluster not being found from the
+// API not being reachable. The values are documented and must not change.
+package exitcode
+
+import "net/http"
+
+const (
+	// OK means that the command succeeded.
+	OK = 0
+	// Error is used for all errors not belonging to one of the classes below.
+	Error = 1
+	// Validation means that flags, arguments or input files are invalid.
+	Validation = 2
+	// Auth means that the user is not logged in, the credentials are not
+	// valid, or access has been denied.
+	Auth = 3
+	// NotFound means that a resource like a cluster, node pool or
+	// organization does not exist.
+	NotFound = 4
+	// Conflict means that the request conflicts with the current state,
+	// e. g. because something exists already.
+	Conflict = 5
+	// API means that the API returned a server error or an unexpected
+	// response.
+	API = 6
+	// Network means that the API could not be reached, e. g. due to a
+	// timeout, a DNS or a TLS problem.
+	Network = 7
+	// Drift means that a cluster differs from its definition, as reported
+	// by 'gsctl diff'. It is not an error.
+	Drift = 8
+	// Timeout means that waiting for a cluster or node pool to reach a
+	// state took longer than allowed.
+	Timeout = 9
+)
+
+// names are the error classes as printed in machine-readable error output.
+var names = map[int]string{
+	Error:      "error",
+	Validation: "validation",
+	Auth:       "auth",
+	NotFound:   "not_found",
+	Conflict:   "conflict",
+	API:        "api",
+	Network:    "network",
+	Drift:      "drift",
+	Timeout:    "timeout",
+}
+
+// Name returns the name of the error class of an exit code.
+func Name(code int) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+
+	return names[Error]
+}
+
+// FromHTTPStatus returns the exit code for an API error with the given HTTP
+// status code. Zero means that no response has been received.
+func FromHTTPStatus(status int) int {
+	switch {
+	case status == 0:
+		return Network
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return Auth
+	case status == http.StatusNotFound:
+		return NotFound
+	case status == http.StatusConflict:
+		return Conflict
+	case status == http.StatusBadRequest:
+		return Validation
+	}
+
+	return API
+}
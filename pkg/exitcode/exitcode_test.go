@@ -0,0 +1,40 @@
+package exitcode
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestFromHTTPStatus(t *testing.T) {
+	testCases := []struct {
+		status   int
+		expected int
+	}{
+		{0, Network},
+		{http.StatusBadRequest, Validation},
+		{http.StatusUnauthorized, Auth},
+		{http.StatusForbidden, Auth},
+		{http.StatusNotFound, NotFound},
+		{http.StatusConflict, Conflict},
+		{http.StatusInternalServerError, API},
+		{http.StatusServiceUnavailable, API},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if code := FromHTTPStatus(tc.status); code != tc.expected {
+				t.Errorf("FromHTTPStatus(%d) = %d; want %d", tc.status, code, tc.expected)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	if Name(NotFound) != "not_found" {
+		t.Errorf("Expected 'not_found', got %q", Name(NotFound))
+	}
+	if Name(42) != "error" {
+		t.Errorf("Expected 'error' for unknown exit code, got %q", Name(42))
+	}
+}
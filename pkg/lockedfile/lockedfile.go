@@ -0,0 +1,73 @@
+// Package lockedfile provides locking and atomic writes for files in the
+// config directory which get modified by several goroutines or several
+// gsctl processes at the same time, like caches.
+package lockedfile
+
+import (
+	"os"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+var (
+	// mutexes maps file paths to the mutex used to serialize access within
+	// this process.
+	mutexes      = map[string]*sync.Mutex{}
+	mutexesMutex sync.Mutex
+)
+
+// Lock acquires an exclusive lock for the file with the given path. The
+// returned function releases the lock.
+//
+// Within a process, the lock is a mutex per path. On the operating system's
+// file system, an advisory lock on a file next to the file, with suffix
+// '.lock', is acquired in addition, so that other gsctl processes wait as
+// well. Other file systems, like the in-memory one used in tests, are
+// only locked within the process.
+func Lock(fs afero.Fs, filePath string) (func(), error) {
+	mutexesMutex.Lock()
+	mutex, ok := mutexes[filePath]
+	if !ok {
+		mutex = &sync.Mutex{}
+		mutexes[filePath] = mutex
+	}
+	mutexesMutex.Unlock()
+
+	mutex.Lock()
+
+	if _, ok := fs.(*afero.OsFs); !ok {
+		return mutex.Unlock, nil
+	}
+
+	f, err := os.OpenFile(filePath+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		mutex.Unlock()
+		return nil, err
+	}
+
+	err = lockFile(f)
+	if err != nil {
+		f.Close()
+		mutex.Unlock()
+		return nil, err
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+		mutex.Unlock()
+	}, nil
+}
+
+// Write writes data to a temporary file first and then moves it to the
+// given path, so that readers never see a partially written file.
+func Write(fs afero.Fs, filePath string, data []byte, perm os.FileMode) error {
+	tempFilePath := filePath + ".tmp"
+	err := afero.WriteFile(fs, tempFilePath, data, perm)
+	if err != nil {
+		return err
+	}
+
+	return fs.Rename(tempFilePath, filePath)
+}
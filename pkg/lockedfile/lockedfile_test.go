@@ -0,0 +1,66 @@
+package lockedfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// TestLock checks that read-modify-write cycles under the lock don't lose
+// updates, both on the OS file system and in memory.
+func TestLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lockedfile")
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		name string
+		fs   afero.Fs
+		path string
+	}{
+		{"os", afero.NewOsFs(), filepath.Join(dir, "counter")},
+		{"memory", afero.NewMemMapFs(), "/counter"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					unlock, err := Lock(tc.fs, tc.path)
+					if err != nil {
+						t.Errorf("Unexpected error: %#v", err)
+						return
+					}
+					defer unlock()
+
+					data, _ := afero.ReadFile(tc.fs, tc.path)
+					count, _ := strconv.Atoi(string(data))
+					err = Write(tc.fs, tc.path, []byte(strconv.Itoa(count+1)), 0600)
+					if err != nil {
+						t.Errorf("Unexpected error: %#v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			data, err := afero.ReadFile(tc.fs, tc.path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+			if string(data) != "50" {
+				t.Errorf("Expected count 50, got %s", string(data))
+			}
+		})
+	}
+}
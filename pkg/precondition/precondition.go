@@ -0,0 +1,39 @@
+// Package precondition provides the checks commands perform before sending
+// any request, so that commands of the same kind fail the same way.
+package precondition
+
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+)
+
+// VerifyCluster checks that an API endpoint is selected, that a token is
+// available and that a cluster is given.
+func VerifyCluster(apiEndpoint, authToken, userProvidedToken, clusterNameOrID string) error {
+	if apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if authToken == "" && userProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if clusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+
+	return nil
+}
+
+// VerifyApp checks the same as VerifyCluster, and that an app name is given.
+// It is used by all commands dealing with a single app.
+func VerifyApp(apiEndpoint, authToken, userProvidedToken, clusterNameOrID, appName string) error {
+	err := VerifyCluster(apiEndpoint, authToken, userProvidedToken, clusterNameOrID)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if appName == "" {
+		return microerror.Mask(errors.AppNameMissingError)
+	}
+
+	return nil
+}
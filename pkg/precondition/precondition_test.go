@@ -0,0 +1,39 @@
+package precondition
+
+import (
+	"testing"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+)
+
+func TestVerifyApp(t *testing.T) {
+	testCases := []struct {
+		name              string
+		apiEndpoint       string
+		authToken         string
+		userProvidedToken string
+		clusterNameOrID   string
+		appName           string
+		errorMatcher      func(error) bool
+	}{
+		{"case 0: complete", "https://foo", "token", "", "cluster", "app", nil},
+		{"case 1: user provided token", "https://foo", "", "token", "cluster", "app", nil},
+		{"case 2: no endpoint", "", "token", "", "cluster", "app", errors.IsEndpointMissingError},
+		{"case 3: no token", "https://foo", "", "", "cluster", "app", errors.IsNotLoggedInError},
+		{"case 4: no cluster", "https://foo", "token", "", "", "app", errors.IsClusterNameOrIDMissingError},
+		{"case 5: no app", "https://foo", "token", "", "cluster", "", errors.IsAppNameMissingError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyApp(tc.apiEndpoint, tc.authToken, tc.userProvidedToken, tc.clusterNameOrID, tc.appName)
+			if tc.errorMatcher == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %#v", err)
+				}
+			} else if !tc.errorMatcher(err) {
+				t.Errorf("Error did not match expected type, got %#v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package sortable
 
 import (
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 
@@ -88,3 +89,83 @@ func GetCompareFunc(t string) func(string, string, string) bool {
 		return CompareStrings
 	}
 }
+
+// Compare compares two values of the given type. The result is negative if
+// a is less than b, zero if both are equal, and positive if a is greater
+// than b. Strings are compared case-insensitively, in natural order. Values
+// which are not valid for the type, like 'n/a' in a semver field, are ordered
+// after all valid values.
+func Compare(t string, a string, b string) int {
+	validA := IsValid(t, a)
+	validB := IsValid(t, b)
+
+	switch {
+	case validA && !validB:
+		return -1
+	case !validA && validB:
+		return 1
+	case !validA && !validB:
+		return compareStrings(a, b)
+	}
+
+	switch t {
+	case Semver:
+		verA, _ := semver.NewVersion(a)
+		verB, _ := semver.NewVersion(b)
+
+		return verA.Compare(verB)
+
+	case Date:
+		dateA := parseDate(a)
+		dateB := parseDate(b)
+
+		switch {
+		case dateA.Before(dateB):
+			return -1
+		case dateA.After(dateB):
+			return 1
+		}
+
+		return 0
+	}
+
+	return compareStrings(a, b)
+}
+
+// IsValid returns true if the value can be interpreted as the given type.
+func IsValid(t string, v string) bool {
+	switch t {
+	case Semver:
+		_, err := semver.NewVersion(v)
+		return err == nil
+	case Date:
+		return !parseDate(v).IsZero()
+	}
+
+	return true
+}
+
+func compareStrings(a string, b string) int {
+	a = strings.ToLower(a)
+	b = strings.ToLower(b)
+
+	switch {
+	case a == b:
+		return 0
+	case stringsort.Compare(a, b):
+		return -1
+	}
+
+	return 1
+}
+
+// parseDate parses dates in the formats understood by util.ParseDate, and
+// plain dates like '2020-01-31'.
+func parseDate(v string) time.Time {
+	date, err := time.Parse("2006-01-02", v)
+	if err == nil {
+		return date
+	}
+
+	return util.ParseDate(v)
+}
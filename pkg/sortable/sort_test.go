@@ -195,3 +195,37 @@ func Test_CompareDates(t *testing.T) {
 		})
 	}
 }
+
+func Test_Compare(t *testing.T) {
+	testCases := []struct {
+		sortType       string
+		a              string
+		b              string
+		expectedResult int
+	}{
+		{String, "acme", "ACME", 0},
+		{String, "node2", "node10", -1},
+		{String, "zoo", "acme", 1},
+		{Semver, "12.0.0", "9.1.0", 1},
+		{Semver, "12.0", "12.0.0", 0},
+		{Semver, "n/a", "9.1.0", 1},
+		{Semver, "9.1.0", "n/a", -1},
+		{Date, "2017 May 16, 09:30 UTC", "2017-05-16T09:30:00Z", 0},
+		{Date, "2017-04-16T09:30:31.192170835Z", "2017-05-01", -1},
+		{Date, "2020-01-01", "2017 May 16, 09:30 UTC", 1},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			result := Compare(tc.sortType, tc.a, tc.b)
+			if result < 0 {
+				result = -1
+			} else if result > 0 {
+				result = 1
+			}
+			if result != tc.expectedResult {
+				t.Errorf("Case %d - Expected %d, got %d", i, tc.expectedResult, result)
+			}
+		})
+	}
+}
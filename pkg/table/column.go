@@ -15,6 +15,10 @@ type Column struct {
 	// DisplayName represents the table header visible in the printed table.
 	DisplayName string
 	Hidden      bool
+	// Wide columns are only displayed in wide output.
+	Wide bool
+	// AlignRight aligns the column content to the right, e. g. for numbers.
+	AlignRight bool
 }
 
 // GetHeader gets the table header for the current column.
@@ -0,0 +1,109 @@
+package table
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// customColumnNone is displayed for fields without a value.
+const customColumnNone = "<none>"
+
+// customColumn is a column defined via the custom-columns output format.
+type customColumn struct {
+	header string
+	path   *jsonpath.JSONPath
+}
+
+// parseCustomColumns parses an output format like
+// 'custom-columns=NAME:.name,RELEASE:.release_version'.
+func parseCustomColumns(outputFormat string) ([]customColumn, error) {
+	spec := strings.TrimPrefix(outputFormat, OutputFormatCustomColumns+"=")
+	if spec == "" {
+		return nil, microerror.Maskf(invalidCustomColumnsError, "no columns given, use e. g. '%s=NAME:.name'", OutputFormatCustomColumns)
+	}
+
+	var columns []customColumn
+	for _, def := range strings.Split(spec, ",") {
+		parts := strings.SplitN(def, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, microerror.Maskf(invalidCustomColumnsError, "column definition %q is not of the form <HEADER>:<JSONPath expression>", def)
+		}
+
+		// Allow expressions without curly braces, e. g. '.name'.
+		expression := parts[1]
+		if !strings.Contains(expression, "{") {
+			expression = "{" + expression + "}"
+		}
+
+		path := jsonpath.New(parts[0]).AllowMissingKeys(true)
+		err := path.Parse(expression)
+		if err != nil {
+			return nil, microerror.Maskf(invalidCustomColumnsError, "could not parse JSONPath expression %q of column %s: %s", parts[1], parts[0], err.Error())
+		}
+
+		columns = append(columns, customColumn{header: parts[0], path: path})
+	}
+
+	return columns, nil
+}
+
+// CustomColumns renders items as a table with the columns given via the
+// custom-columns output format. Column values are taken from the JSON
+// representation of each item, so the same field names as in JSON output
+// apply. Items can be a slice or a single item.
+func CustomColumns(outputFormat string, items interface{}) (string, error) {
+	columns, err := parseCustomColumns(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	jsonBytes, err := json.Marshal(items)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	var data interface{}
+	err = json.Unmarshal(jsonBytes, &data)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	list, ok := data.([]interface{})
+	if !ok {
+		list = []interface{}{data}
+	}
+
+	tableColumns := make([]Column, 0, len(columns))
+	for _, col := range columns {
+		tableColumns = append(tableColumns, Column{Name: col.header})
+	}
+
+	rows := make([][]string, 0, len(list))
+	for _, item := range list {
+		row := make([]string, 0, len(columns))
+		for _, col := range columns {
+			var value bytes.Buffer
+			err = col.path.Execute(&value, item)
+			if err != nil {
+				return "", microerror.Maskf(invalidCustomColumnsError, "could not get value of column %s: %s", col.header, err.Error())
+			}
+
+			if value.Len() == 0 {
+				row = append(row, customColumnNone)
+			} else {
+				row = append(row, value.String())
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	t := New()
+	t.SetColumns(tableColumns)
+	t.SetRows(rows)
+
+	return t.String(), nil
+}
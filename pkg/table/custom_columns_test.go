@@ -0,0 +1,99 @@
+package table
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_CustomColumns(t *testing.T) {
+	type item struct {
+		ID             string            `json:"id"`
+		Name           string            `json:"name"`
+		ReleaseVersion string            `json:"release_version,omitempty"`
+		Labels         map[string]string `json:"labels,omitempty"`
+	}
+
+	items := []item{
+		{ID: "f01r4", Name: "Production", ReleaseVersion: "12.0.0", Labels: map[string]string{"env": "prod"}},
+		{ID: "a7k4", Name: "Staging"},
+	}
+
+	testCases := []struct {
+		outputFormat   string
+		items          interface{}
+		expectedResult string
+		errorMatcher   func(error) bool
+	}{
+		{
+			outputFormat: "custom-columns=NAME:.name,REL:.release_version,ENV:{.labels.env}",
+			items:        items,
+			expectedResult: `NAME         REL      ENV
+Production   12.0.0   prod
+Staging      <none>   <none>`,
+		},
+		{
+			outputFormat: "custom-columns=ID:.id",
+			items:        items[1],
+			expectedResult: `ID
+a7k4`,
+		},
+		{
+			outputFormat: "custom-columns=",
+			items:        items,
+			errorMatcher: IsInvalidCustomColumnsError,
+		},
+		{
+			outputFormat: "custom-columns=NAME",
+			items:        items,
+			errorMatcher: IsInvalidCustomColumnsError,
+		},
+		{
+			outputFormat: "custom-columns=NAME:{.name",
+			items:        items,
+			errorMatcher: IsInvalidCustomColumnsError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			result, err := CustomColumns(tc.outputFormat, tc.items)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Unexpected error %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %s", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expectedResult, result); diff != "" {
+				t.Errorf("Case %d - Result did not match.\nOutput: %s", i, diff)
+			}
+		})
+	}
+}
+
+func Test_IsTableOutput(t *testing.T) {
+	testCases := []struct {
+		outputFormat   string
+		expectedResult bool
+	}{
+		{"table", true},
+		{"wide", true},
+		{"custom-columns=NAME:.name", true},
+		{"custom-columns", false},
+		{"json", false},
+		{"", false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if IsTableOutput(tc.outputFormat) != tc.expectedResult {
+				t.Errorf("Case %d - Expected %t for %q", i, tc.expectedResult, tc.outputFormat)
+			}
+		})
+	}
+}
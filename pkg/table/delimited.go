@@ -0,0 +1,62 @@
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+// Format renders the table in the given output format. CSV and TSV are
+// rendered as delimiter separated values, all other formats as the
+// human-friendly table.
+func (t *Table) Format(outputFormat string) (string, error) {
+	switch outputFormat {
+	case OutputFormatCSV:
+		return t.delimited(',')
+	case OutputFormatTSV:
+		return t.delimited('\t')
+	}
+
+	return t.String(), nil
+}
+
+// delimited renders the table's header and rows as values separated by
+// the given delimiter, quoted where necessary. Colors are removed, and the
+// same columns are included as in the printed table.
+func (t *Table) delimited(delimiter rune) (string, error) {
+	visible := t.visibleColumns()
+
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	w.Comma = delimiter
+
+	records := make([][]string, 0, len(t.rows)+1)
+	{
+		header := make([]string, 0, len(visible))
+		for _, i := range visible {
+			header = append(header, RemoveColors(t.columns[i].GetHeader()))
+		}
+		records = append(records, header)
+	}
+
+	for _, row := range t.rows {
+		record := make([]string, 0, len(visible))
+		for _, i := range visible {
+			value := ""
+			if i < len(row) {
+				value = RemoveColors(row[i])
+			}
+			record = append(record, value)
+		}
+		records = append(records, record)
+	}
+
+	err := w.WriteAll(records)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return strings.TrimRight(out.String(), "\n"), nil
+}
@@ -0,0 +1,97 @@
+package table
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Format(t *testing.T) {
+	columns := []Column{
+		{Name: "name", DisplayName: "NAME"},
+		{Name: "description", DisplayName: "DESCRIPTION"},
+		{Name: "nodes", DisplayName: "NODES", Wide: true},
+		{Name: "deleting", Hidden: true},
+	}
+	rows := [][]string{
+		{color.YellowString("Good dog"), "Barks, sometimes", "3", "no"},
+		{"Good cat", `Says "meow"`, "12"},
+	}
+
+	testCases := []struct {
+		outputFormat   string
+		wide           bool
+		expectedResult string
+	}{
+		{
+			outputFormat: OutputFormatCSV,
+			wide:         true,
+			expectedResult: `NAME,DESCRIPTION,NODES
+Good dog,"Barks, sometimes",3
+Good cat,"Says ""meow""",12`,
+		},
+		{
+			outputFormat: OutputFormatTSV,
+			wide:         true,
+			expectedResult: "NAME\tDESCRIPTION\tNODES\n" +
+				"Good dog\tBarks, sometimes\t3\n" +
+				"Good cat\t\"Says \"\"meow\"\"\"\t12",
+		},
+		// Wide columns are only included if enabled.
+		{
+			outputFormat: OutputFormatCSV,
+			expectedResult: `NAME,DESCRIPTION
+Good dog,"Barks, sometimes"
+Good cat,"Says ""meow"""`,
+		},
+		// Empty tables consist of the header only.
+		{
+			outputFormat:   OutputFormatCSV,
+			expectedResult: "NAME,DESCRIPTION",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			table := New()
+			table.SetColumns(columns)
+			table.SetWide(tc.wide)
+			if i < len(testCases)-1 {
+				table.SetRows(rows)
+			}
+
+			result, err := table.Format(tc.outputFormat)
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error: %s", i, err)
+			}
+			if diff := cmp.Diff(tc.expectedResult, result); diff != "" {
+				t.Errorf("Case %d - Result did not match.\nOutput: %s", i, diff)
+			}
+		})
+	}
+}
+
+func Test_IsDelimited(t *testing.T) {
+	testCases := []struct {
+		outputFormat   string
+		expectedResult bool
+	}{
+		{"csv", true},
+		{"tsv", true},
+		{"table", false},
+		{"json", false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if IsDelimited(tc.outputFormat) != tc.expectedResult {
+				t.Errorf("Case %d - Expected %t for %q", i, tc.expectedResult, tc.outputFormat)
+			}
+			if tc.expectedResult && (!IsTableOutput(tc.outputFormat) || !IsWide(tc.outputFormat)) {
+				t.Errorf("Case %d - Expected %q to be a wide table output format", i, tc.outputFormat)
+			}
+		})
+	}
+}
@@ -21,3 +21,30 @@ var multipleFieldsMatchingError = &microerror.Error{
 func IsMultipleFieldsMatchingError(err error) bool {
 	return microerror.Cause(err) == multipleFieldsMatchingError
 }
+
+var invalidCustomColumnsError = &microerror.Error{
+	Kind: "invalidCustomColumnsError",
+}
+
+// IsInvalidCustomColumnsError asserts invalidCustomColumnsError.
+func IsInvalidCustomColumnsError(err error) bool {
+	return microerror.Cause(err) == invalidCustomColumnsError
+}
+
+var invalidSortKeyError = &microerror.Error{
+	Kind: "invalidSortKeyError",
+}
+
+// IsInvalidSortKeyError asserts invalidSortKeyError.
+func IsInvalidSortKeyError(err error) bool {
+	return microerror.Cause(err) == invalidSortKeyError
+}
+
+var invalidFilterError = &microerror.Error{
+	Kind: "invalidFilterError",
+}
+
+// IsInvalidFilterError asserts invalidFilterError.
+func IsInvalidFilterError(err error) bool {
+	return microerror.Cause(err) == invalidFilterError
+}
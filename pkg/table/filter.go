@@ -0,0 +1,132 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/pkg/sortable"
+)
+
+// Filter operators.
+const (
+	OperatorEqual          = "="
+	OperatorNotEqual       = "!="
+	OperatorGreater        = ">"
+	OperatorGreaterOrEqual = ">="
+	OperatorLess           = "<"
+	OperatorLessOrEqual    = "<="
+)
+
+// filterOperators are the supported operators, two character operators
+// first so that they take precedence.
+var filterOperators = []string{
+	"==",
+	OperatorNotEqual,
+	OperatorGreaterOrEqual,
+	OperatorLessOrEqual,
+	OperatorEqual,
+	OperatorGreater,
+	OperatorLess,
+}
+
+// Filter is a condition the rows of a table have to meet, like
+// 'release>=12.0.0'.
+type Filter struct {
+	// Column is the name of the column, or its initials.
+	Column   string
+	Operator string
+	Value    string
+}
+
+// ParseFilter parses a filter expression like 'organization=acme' or
+// 'release>=12.0.0'.
+func ParseFilter(expression string) (Filter, error) {
+	index := strings.IndexAny(expression, "=!<>")
+	if index < 0 {
+		index = len(expression)
+	}
+
+	for _, operator := range filterOperators {
+		if strings.HasPrefix(expression[index:], operator) {
+			f := Filter{
+				Column:   strings.TrimSpace(expression[:index]),
+				Operator: operator,
+				Value:    strings.TrimSpace(expression[index+len(operator):]),
+			}
+			if f.Column == "" {
+				break
+			}
+			if f.Operator == "==" {
+				f.Operator = OperatorEqual
+			}
+
+			return f, nil
+		}
+	}
+
+	return Filter{}, microerror.Maskf(invalidFilterError, "filter %q must have the form '<field><operator><value>', with one of the operators =, !=, >, >=, <, <=", expression)
+}
+
+// ParseFilters parses several filter expressions.
+func ParseFilters(expressions []string) ([]Filter, error) {
+	filters := make([]Filter, 0, len(expressions))
+	for _, expression := range expressions {
+		f, err := ParseFilter(expression)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+// matches returns true if a value of the given sort type meets the filter
+// condition. Values are compared using the sortable package, so that e. g.
+// versions are compared as semantic versions. Values which are invalid for
+// the type never meet an ordering condition.
+func (f Filter) matches(sortType string, value string) bool {
+	switch f.Operator {
+	case OperatorEqual:
+		return sortable.Compare(sortType, value, f.Value) == 0
+	case OperatorNotEqual:
+		return sortable.Compare(sortType, value, f.Value) != 0
+	}
+
+	if !sortable.IsValid(sortType, value) {
+		return false
+	}
+
+	result := sortable.Compare(sortType, value, f.Value)
+
+	switch f.Operator {
+	case OperatorGreater:
+		return result > 0
+	case OperatorGreaterOrEqual:
+		return result >= 0
+	case OperatorLess:
+		return result < 0
+	case OperatorLessOrEqual:
+		return result <= 0
+	}
+
+	return false
+}
+
+// ParseSortAndFilter parses the values of the --sort and --filter flags of
+// list commands.
+func ParseSortAndFilter(sortSpec string, filterExpressions []string) ([]SortKey, []Filter, error) {
+	keys, err := ParseSortKeys(sortSpec)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	filters, err := ParseFilters(filterExpressions)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	return keys, filters, nil
+}
@@ -0,0 +1,72 @@
+package table
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ParseFilter(t *testing.T) {
+	testCases := []struct {
+		expression     string
+		expectedResult Filter
+		errorMatcher   func(error) bool
+	}{
+		{
+			expression:     "organization=acme",
+			expectedResult: Filter{Column: "organization", Operator: OperatorEqual, Value: "acme"},
+		},
+		{
+			expression:     "organization==acme",
+			expectedResult: Filter{Column: "organization", Operator: OperatorEqual, Value: "acme"},
+		},
+		{
+			expression:     "release >= 12.0.0",
+			expectedResult: Filter{Column: "release", Operator: OperatorGreaterOrEqual, Value: "12.0.0"},
+		},
+		{
+			expression:     "name!=",
+			expectedResult: Filter{Column: "name", Operator: OperatorNotEqual, Value: ""},
+		},
+		{
+			expression:     "created<2020-01-01",
+			expectedResult: Filter{Column: "created", Operator: OperatorLess, Value: "2020-01-01"},
+		},
+		{
+			expression:   "acme",
+			errorMatcher: IsInvalidFilterError,
+		},
+		{
+			expression:   "=acme",
+			errorMatcher: IsInvalidFilterError,
+		},
+		{
+			expression:   "name!acme",
+			errorMatcher: IsInvalidFilterError,
+		},
+		{
+			expression:   " =acme",
+			errorMatcher: IsInvalidFilterError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			result, err := ParseFilter(tc.expression)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Unexpected error: %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error: %s", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expectedResult, result); diff != "" {
+				t.Errorf("Case %d - Result did not match.\nOutput: %s", i, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/giantswarm/gsctl/formatting"
+)
+
+const (
+	// OutputFormatWide contains the string value to enable table output
+	// including additional columns.
+	OutputFormatWide = "wide"
+	// OutputFormatCustomColumns is the prefix of the output format to print
+	// a table with user defined columns, as in 'custom-columns=NAME:.name'.
+	OutputFormatCustomColumns = "custom-columns"
+	// OutputFormatCSV contains the string value to enable comma separated
+	// values output.
+	OutputFormatCSV = "csv"
+	// OutputFormatTSV contains the string value to enable tab separated
+	// values output.
+	OutputFormatTSV = "tsv"
+)
+
+// IsTableOutput returns true if the output format is one of the table
+// formats, i. e. the default table, wide, custom columns, CSV or TSV.
+func IsTableOutput(outputFormat string) bool {
+	return outputFormat == formatting.OutputFormatTable || IsWide(outputFormat) || IsCustomColumns(outputFormat)
+}
+
+// IsWide returns true if the output format includes the wide columns. This
+// is the case for wide table output, and for CSV and TSV, which are meant
+// for reporting and contain all columns.
+func IsWide(outputFormat string) bool {
+	return outputFormat == OutputFormatWide || IsDelimited(outputFormat)
+}
+
+// IsCustomColumns returns true if the output format defines custom columns.
+func IsCustomColumns(outputFormat string) bool {
+	return strings.HasPrefix(outputFormat, OutputFormatCustomColumns+"=")
+}
+
+// IsDelimited returns true if the output format is CSV or TSV.
+func IsDelimited(outputFormat string) bool {
+	return outputFormat == OutputFormatCSV || outputFormat == OutputFormatTSV
+}
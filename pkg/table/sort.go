@@ -2,8 +2,10 @@ package table
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/acarl005/stripansi"
+	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/gsctl/pkg/sortable"
 )
@@ -38,3 +40,41 @@ func RemoveColors(s string) string {
 
 	return t
 }
+
+// SortKey is a column to sort by, in a direction.
+type SortKey struct {
+	// Column is the name of the column, or its initials.
+	Column    string
+	Direction string
+}
+
+// ParseSortKeys parses a comma separated list of columns to sort by, like
+// 'release,-created'. Columns prefixed with '-' are sorted in descending
+// order, all others in ascending order.
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key := SortKey{Column: part, Direction: sortable.ASC}
+		switch part[0] {
+		case '-':
+			key.Column = part[1:]
+			key.Direction = sortable.DESC
+		case '+':
+			key.Column = part[1:]
+		}
+
+		if key.Column == "" {
+			return nil, microerror.Maskf(invalidSortKeyError, "sort key %q has no field name", part)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
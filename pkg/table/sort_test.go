@@ -213,3 +213,51 @@ func Test_RemoveColors(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseSortKeys(t *testing.T) {
+	testCases := []struct {
+		spec           string
+		expectedResult []SortKey
+		errorMatcher   func(error) bool
+	}{
+		{
+			spec: "release,-created",
+			expectedResult: []SortKey{
+				{Column: "release", Direction: sortable.ASC},
+				{Column: "created", Direction: sortable.DESC},
+			},
+		},
+		{
+			spec: " +name , ",
+			expectedResult: []SortKey{
+				{Column: "name", Direction: sortable.ASC},
+			},
+		},
+		{
+			spec: "",
+		},
+		{
+			spec:         "name,-",
+			errorMatcher: IsInvalidSortKeyError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			result, err := ParseSortKeys(tc.spec)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Unexpected error: %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error: %s", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expectedResult, result); diff != "" {
+				t.Errorf("Case %d - Result did not match.\nOutput: %s", i, diff)
+			}
+		})
+	}
+}
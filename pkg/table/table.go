@@ -14,6 +14,7 @@ import (
 type Table struct {
 	columns []Column
 	rows    [][]string
+	wide    bool
 
 	// columnizeConfig represents the configuration of the table formatter.
 	columnizeConfig *columnize.Config
@@ -39,6 +40,16 @@ func (t *Table) SetRows(r [][]string) {
 	t.rows = r[:][:]
 }
 
+// SetWide defines whether columns marked as Wide are displayed.
+func (t *Table) SetWide(w bool) {
+	t.wide = w
+}
+
+// SetGlue sets the string separating the columns of the printed table.
+func (t *Table) SetGlue(g string) {
+	t.columnizeConfig.Glue = g
+}
+
 // SortByColumnName sorts the table by a column name, in the given direction.
 func (t *Table) SortByColumnName(n string, direction string) error {
 	// Skip if there is nothing to sort, or if there's no column name provided.
@@ -84,6 +95,103 @@ func (t *Table) SortByColumnName(n string, direction string) error {
 	return nil
 }
 
+// SortAndFilter removes the rows not meeting all filters, and sorts the
+// remaining rows by the given keys, in order of precedence. Columns may be
+// given by their initials. It returns the original indexes of the remaining
+// rows in their new order, so that the data the rows were created from can
+// be put in the same order, e. g. for structured output.
+func (t *Table) SortAndFilter(keys []SortKey, filters []Filter) ([]int, error) {
+	type columnRef struct {
+		index     int
+		sortType  string
+		direction string
+	}
+
+	resolve := func(name string) (int, Column, error) {
+		colName, err := t.GetColumnNameFromInitials(name)
+		if err != nil {
+			return 0, Column{}, microerror.Mask(err)
+		}
+
+		return t.GetColumnByName(colName)
+	}
+
+	cellValue := func(row []string, index int) string {
+		if index >= len(row) {
+			return ""
+		}
+
+		return RemoveColors(row[index])
+	}
+
+	filterRefs := make([]columnRef, 0, len(filters))
+	for _, f := range filters {
+		index, column, err := resolve(f.Column)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		isOrdering := f.Operator != OperatorEqual && f.Operator != OperatorNotEqual
+		if isOrdering && !sortable.IsValid(column.SortType, f.Value) {
+			return nil, microerror.Maskf(invalidFilterError, "value %q is not valid for field %q of type %s", f.Value, column.Name, column.SortType)
+		}
+
+		filterRefs = append(filterRefs, columnRef{index: index, sortType: column.SortType})
+	}
+
+	sortRefs := make([]columnRef, 0, len(keys))
+	for _, key := range keys {
+		index, column, err := resolve(key.Column)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		sortRefs = append(sortRefs, columnRef{index: index, sortType: column.SortType, direction: key.Direction})
+	}
+
+	var indexes []int
+	for i, row := range t.rows {
+		matches := true
+		for j, f := range filters {
+			if !f.matches(filterRefs[j].sortType, cellValue(row, filterRefs[j].index)) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			indexes = append(indexes, i)
+		}
+	}
+
+	sort.SliceStable(indexes, func(a, b int) bool {
+		rowA := t.rows[indexes[a]]
+		rowB := t.rows[indexes[b]]
+
+		for _, ref := range sortRefs {
+			result := sortable.Compare(ref.sortType, cellValue(rowA, ref.index), cellValue(rowB, ref.index))
+			if result == 0 {
+				continue
+			}
+			if ref.direction == sortable.DESC {
+				return result > 0
+			}
+
+			return result < 0
+		}
+
+		return false
+	})
+
+	rows := make([][]string, 0, len(indexes))
+	for _, i := range indexes {
+		rows = append(rows, t.rows[i])
+	}
+	t.rows = rows
+
+	return indexes, nil
+}
+
 // GetColumnByName fetches the index and data structure of a column, by knowing its name.
 func (t *Table) GetColumnByName(n string) (int, Column, error) {
 	var (
@@ -130,36 +238,65 @@ func (t *Table) GetColumnNameFromInitials(i string) (string, error) {
 	}
 
 	if len(matchingNames) == 0 {
-		return "", microerror.Maskf(fieldNotFoundError, "available fields for sorting: %v", strings.Join(columnNames, ", "))
+		return "", microerror.Maskf(fieldNotFoundError, "no field matches %q, available fields: %v", i, strings.Join(columnNames, ", "))
 	} else if len(matchingNames) > 1 {
-		return "", microerror.Maskf(multipleFieldsMatchingError, "%v", strings.Join(matchingNames, ", "))
+		return "", microerror.Maskf(multipleFieldsMatchingError, "%q matches several fields: %v", i, strings.Join(matchingNames, ", "))
 	}
 
 	return matchingNames[0], nil
 }
 
 // String makes the Table data structure implement the Stringer interface,
-// so we can easily pretty-print it.
+// so we can easily pretty-print it. Hidden columns, and wide columns unless
+// wide output is enabled, are omitted.
 func (t *Table) String() string {
+	visible := t.visibleColumns()
+
 	rows := make([]string, 0, len(t.rows)+1)
+	config := *t.columnizeConfig
+	config.ColumnSpec = make([]*columnize.ColumnSpecification, 0, len(visible))
 
 	{
-		columns := make([]string, 0, len(t.columns))
-		for _, col := range t.columns {
-			if !col.Hidden {
-				columns = append(columns, col.GetHeader())
+		columns := make([]string, 0, len(visible))
+		for _, i := range visible {
+			columns = append(columns, t.columns[i].GetHeader())
+
+			alignment := columnize.AlignLeft
+			if t.columns[i].AlignRight {
+				alignment = columnize.AlignRight
 			}
+			config.ColumnSpec = append(config.ColumnSpec, &columnize.ColumnSpecification{Alignment: alignment})
 		}
 		rows = append(rows, strings.Join(columns, "|"))
 	}
 
 	{
 		for _, row := range t.rows {
-			rows = append(rows, strings.Join(row, "|"))
+			cells := make([]string, 0, len(visible))
+			for _, i := range visible {
+				// Rows may omit the values of trailing hidden columns.
+				if i < len(row) {
+					cells = append(cells, row[i])
+				}
+			}
+			rows = append(rows, strings.Join(cells, "|"))
 		}
 	}
 
-	formattedTable := columnize.Format(rows, t.columnizeConfig)
+	formattedTable := columnize.Format(rows, &config)
 
 	return formattedTable
 }
+
+// visibleColumns returns the indexes of the columns to display.
+func (t *Table) visibleColumns() []int {
+	var visible []int
+	for i, col := range t.columns {
+		if col.Hidden || (col.Wide && !t.wide) {
+			continue
+		}
+		visible = append(visible, i)
+	}
+
+	return visible
+}
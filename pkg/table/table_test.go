@@ -886,6 +886,7 @@ func Test_String(t *testing.T) {
 	testCases := []struct {
 		columns        []Column
 		rows           [][]string
+		wide           bool
 		expectedResult string
 	}{
 		{
@@ -924,6 +925,54 @@ Good dog      2016 Dec 05, 14:41 UTC   12.0.1
 Good cat      2016 Dec 25, 14:41 UTC   12.0.1
 Good parrot   2016 Dec 25, 15:41 UTC   9.0.1`,
 		},
+		// Wide and hidden columns are omitted by default.
+		{
+			columns: []Column{
+				{
+					Name: "name",
+				},
+				{
+					Name: "nodes",
+					Wide: true,
+				},
+				{
+					Name:   "deleting",
+					Hidden: true,
+				},
+			},
+			rows: [][]string{
+				{"Good dog", "3"},
+				{"Good cat", "12"},
+			},
+			expectedResult: `name
+Good dog
+Good cat`,
+		},
+		// Wide columns are displayed in wide output, right-aligned if desired.
+		{
+			columns: []Column{
+				{
+					Name: "name",
+				},
+				{
+					Name:       "nodes",
+					Wide:       true,
+					AlignRight: true,
+				},
+				{
+					Name:   "deleting",
+					Hidden: true,
+				},
+			},
+			rows: [][]string{
+				{"Good dog", "3"},
+				{"Good cat", "12"},
+			},
+			wide: true,
+			expectedResult: `name       nodes
+Good dog       3
+Good cat      12`,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -931,6 +980,7 @@ Good parrot   2016 Dec 25, 15:41 UTC   9.0.1`,
 			table := New()
 			table.SetColumns(tc.columns)
 			table.SetRows(tc.rows)
+			table.SetWide(tc.wide)
 
 			result := table.String()
 
@@ -940,3 +990,83 @@ Good parrot   2016 Dec 25, 15:41 UTC   9.0.1`,
 		})
 	}
 }
+
+func Test_SortAndFilter(t *testing.T) {
+	columns := []Column{
+		{Name: "name", Sortable: sortable.Sortable{SortType: sortable.String}},
+		{Name: "organization", Sortable: sortable.Sortable{SortType: sortable.String}},
+		{Name: "release", Sortable: sortable.Sortable{SortType: sortable.Semver}},
+		{Name: "created", Sortable: sortable.Sortable{SortType: sortable.Date}},
+	}
+	rows := [][]string{
+		{"Production", "acme", "12.0.0", "2017 May 16, 09:30 UTC"},
+		{"Staging", "acme", "9.1.0", "2017 Apr 16, 09:30 UTC"},
+		{"Testing", "umbrella", "12.0.0", "2017 Jun 16, 09:30 UTC"},
+		{"Old", "acme", "n/a", "2016 Jan 01, 00:00 UTC"},
+	}
+
+	testCases := []struct {
+		keys            []SortKey
+		filters         []Filter
+		expectedIndexes []int
+		errorMatcher    func(error) bool
+	}{
+		// Several keys, with different directions.
+		{
+			keys:            []SortKey{{Column: "release", Direction: sortable.DESC}, {Column: "c", Direction: sortable.ASC}},
+			expectedIndexes: []int{3, 0, 2, 1},
+		},
+		{
+			keys:            []SortKey{{Column: "o", Direction: sortable.ASC}, {Column: "release", Direction: sortable.ASC}},
+			expectedIndexes: []int{1, 0, 3, 2},
+		},
+		// Filters on strings, versions and dates.
+		{
+			keys:            []SortKey{{Column: "name", Direction: sortable.ASC}},
+			filters:         []Filter{{Column: "organization", Operator: OperatorEqual, Value: "ACME"}, {Column: "release", Operator: OperatorGreaterOrEqual, Value: "10.0.0"}},
+			expectedIndexes: []int{0},
+		},
+		{
+			filters:         []Filter{{Column: "created", Operator: OperatorLess, Value: "2017-05-01"}},
+			expectedIndexes: []int{1, 3},
+		},
+		{
+			filters:         []Filter{{Column: "organization", Operator: OperatorNotEqual, Value: "acme"}},
+			expectedIndexes: []int{2},
+		},
+		{
+			filters:      []Filter{{Column: "release", Operator: OperatorGreater, Value: "latest"}},
+			errorMatcher: IsInvalidFilterError,
+		},
+		{
+			keys:         []SortKey{{Column: "size", Direction: sortable.ASC}},
+			errorMatcher: IsFieldNotFoundError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			table := New()
+			table.SetColumns(columns)
+			table.SetRows(rows)
+
+			indexes, err := table.SortAndFilter(tc.keys, tc.filters)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Unexpected error: %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error: %s", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expectedIndexes, indexes); diff != "" {
+				t.Errorf("Case %d - Indexes did not match.\nOutput: %s", i, diff)
+			}
+			if len(table.rows) != len(indexes) || (len(indexes) > 0 && table.rows[0][0] != rows[indexes[0]][0]) {
+				t.Errorf("Case %d - Rows were not reordered", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Package watch implements polling resources at an interval and printing
+// what has changed, as used by the --watch flag of list and show commands.
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
+	"github.com/mattn/go-isatty"
+
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/table"
+)
+
+const (
+	// DefaultInterval is the time between two polls, unless specified otherwise.
+	DefaultInterval = 5 * time.Second
+
+	// EventAdded is the type of events for items which appeared since the
+	// previous poll. In the first poll, all items are reported as added.
+	EventAdded = "ADDED"
+	// EventModified is the type of events for items which have changed since
+	// the previous poll.
+	EventModified = "MODIFIED"
+	// EventDeleted is the type of events for items which have disappeared
+	// since the previous poll.
+	EventDeleted = "DELETED"
+
+	// Markers prepended to table lines.
+	markerNone     = "  "
+	markerAdded    = "+ "
+	markerModified = "~ "
+	markerDeleted  = "- "
+
+	// Terminal control sequences to move the cursor up a number of lines
+	// and to clear everything below the cursor.
+	cursorUp     = "\033[%dA"
+	clearToEnd   = "\r\033[J"
+	statusFormat = "2006-01-02 15:04:05"
+)
+
+// Item is one watched object, like a cluster or a node pool.
+type Item struct {
+	// Key identifies the item across polls, e. g. the cluster ID.
+	Key string
+	// Line is the human-friendly representation, e. g. a table row.
+	Line string
+	// Object is the representation printed in JSON and YAML events.
+	Object interface{}
+}
+
+// Snapshot is the state of all watched items after one poll.
+type Snapshot struct {
+	// Header is printed above the items in table output, e. g. the
+	// table header.
+	Header string
+	// Items are the watched items, in the order to display them.
+	Items []Item
+	// Footer is printed below the items in table output.
+	Footer string
+}
+
+// Event is printed in JSON and YAML output for every item which has been
+// added, modified or deleted since the previous poll.
+type Event struct {
+	Type   string      `json:"type"`
+	Object interface{} `json:"object"`
+}
+
+// Config configures watching.
+type Config struct {
+	// Fetch returns the current state of the watched items.
+	Fetch func() (*Snapshot, error)
+
+	// Interval is the time between two polls. Defaults to DefaultInterval.
+	Interval time.Duration
+	// OutputFormat is 'json' or 'yaml' for events, everything else
+	// results in tables.
+	OutputFormat string
+	// Title is printed above tables, e. g. the command executed.
+	Title string
+
+	// Output is where results are printed to. Defaults to os.Stdout.
+	Output io.Writer
+	// Redraw enables redrawing tables in place, which requires the output
+	// to be a terminal. Otherwise every table is printed below the
+	// previous one.
+	Redraw bool
+
+	// Polls is the number of polls after which Run returns. Zero means
+	// polling until the program gets interrupted.
+	Polls int
+}
+
+func (c *Config) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+}
+
+// NewTableSnapshot creates a snapshot from a table. The table rows must
+// belong to the items with the given keys and objects, in the same order.
+func NewTableSnapshot(t *table.Table, keys []string, objects []interface{}) *Snapshot {
+	lines := strings.Split(t.String(), "\n")
+
+	s := &Snapshot{Header: lines[0]}
+	for i, key := range keys {
+		if i+1 >= len(lines) || i >= len(objects) {
+			break
+		}
+		s.Items = append(s.Items, Item{Key: key, Line: lines[i+1], Object: objects[i]})
+	}
+
+	return s
+}
+
+// IsTerminal returns true if the file is a terminal, which is required to
+// redraw tables in place.
+func IsTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// SupportsOutputFormat returns true if the output format can be used for
+// watching: JSON and YAML for events, or a table.
+func SupportsOutputFormat(outputFormat string) bool {
+	switch outputFormat {
+	case "", formatting.OutputFormatTable, formatting.OutputFormatJSON, formatting.OutputFormatYAML, table.OutputFormatWide:
+		return true
+	}
+
+	return false
+}
+
+// Run polls c.Fetch every c.Interval and prints the changes. An error in the
+// first poll is returned. Errors in later polls are reported, but polling
+// continues, as they are often temporary.
+func Run(c Config) error {
+	c.setDefaults()
+
+	isEvents := c.OutputFormat == formatting.OutputFormatJSON || c.OutputFormat == formatting.OutputFormatYAML
+
+	var previous *Snapshot
+	linesPrinted := 0
+
+	for poll := 0; c.Polls == 0 || poll < c.Polls; poll++ {
+		if poll > 0 {
+			time.Sleep(c.Interval)
+		}
+
+		current, err := c.Fetch()
+		if err != nil && previous == nil {
+			return microerror.Mask(err)
+		}
+
+		if isEvents {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not update: %s\n", err.Error())
+				continue
+			}
+
+			out, err := renderEvents(c.OutputFormat, Diff(previous, current))
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			fmt.Fprint(c.Output, out)
+
+			previous = current
+			continue
+		}
+
+		status := fmt.Sprintf("Every %s: %s", c.Interval, c.Title)
+		if err != nil {
+			// Keep showing the last state, without changes.
+			status += "  " + color.RedString("could not update: %s", err.Error())
+			current = previous
+		} else {
+			status += "  " + time.Now().UTC().Format(statusFormat) + " UTC"
+		}
+
+		frame := status + "\n\n" + renderTable(previous, current)
+
+		if c.Redraw && linesPrinted > 0 {
+			fmt.Fprintf(c.Output, cursorUp+clearToEnd, linesPrinted)
+		} else if linesPrinted > 0 {
+			fmt.Fprintln(c.Output)
+		}
+		fmt.Fprint(c.Output, frame)
+		linesPrinted = strings.Count(frame, "\n")
+
+		previous = current
+	}
+
+	return nil
+}
+
+// Diff returns events for all items added, modified or deleted between the
+// previous and the current snapshot. Items are compared based on their
+// JSON representation. If previous is nil, all current items are added.
+func Diff(previous, current *Snapshot) []Event {
+	var events []Event
+
+	previousItems := map[string]Item{}
+	if previous != nil {
+		for _, item := range previous.Items {
+			previousItems[item.Key] = item
+		}
+	}
+
+	currentKeys := map[string]bool{}
+	for _, item := range current.Items {
+		currentKeys[item.Key] = true
+
+		previousItem, ok := previousItems[item.Key]
+		if !ok {
+			events = append(events, Event{Type: EventAdded, Object: item.Object})
+		} else if !equalObjects(previousItem.Object, item.Object) {
+			events = append(events, Event{Type: EventModified, Object: item.Object})
+		}
+	}
+
+	if previous != nil {
+		for _, item := range previous.Items {
+			if !currentKeys[item.Key] {
+				events = append(events, Event{Type: EventDeleted, Object: item.Object})
+			}
+		}
+	}
+
+	return events
+}
+
+// equalObjects compares two objects based on their JSON representation.
+func equalObjects(a, b interface{}) bool {
+	aBytes, aErr := json.Marshal(a)
+	bBytes, bErr := json.Marshal(b)
+
+	return aErr == nil && bErr == nil && string(aBytes) == string(bBytes)
+}
+
+// renderEvents returns events as one JSON document per line, or as a stream
+// of YAML documents.
+func renderEvents(outputFormat string, events []Event) (string, error) {
+	var out strings.Builder
+
+	for _, event := range events {
+		if outputFormat == formatting.OutputFormatYAML {
+			eventBytes, err := formatting.Marshal(outputFormat, event)
+			if err != nil {
+				return "", microerror.Mask(err)
+			}
+
+			out.WriteString("---\n" + string(eventBytes) + "\n")
+			continue
+		}
+
+		eventBytes, err := json.Marshal(event)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		out.Write(eventBytes)
+		out.WriteString("\n")
+	}
+
+	return out.String(), nil
+}
+
+// renderTable returns the lines of the current snapshot, marking lines
+// added, modified or deleted since the previous snapshot. Deleted lines
+// are shown once, at their previous position.
+func renderTable(previous, current *Snapshot) string {
+	type markedLine struct {
+		key    string
+		marker string
+		line   string
+	}
+
+	previousLines := map[string]string{}
+	if previous != nil {
+		for _, item := range previous.Items {
+			previousLines[item.Key] = item.Line
+		}
+	}
+
+	lines := make([]markedLine, 0, len(current.Items))
+	for _, item := range current.Items {
+		marker := markerNone
+		line := item.Line
+
+		if previous != nil {
+			previousLine, ok := previousLines[item.Key]
+			if !ok {
+				marker = markerAdded
+				line = color.GreenString(table.RemoveColors(line))
+			} else if previousLine != line {
+				marker = markerModified
+				line = color.YellowString(table.RemoveColors(line))
+			}
+		}
+
+		lines = append(lines, markedLine{key: item.Key, marker: marker, line: line})
+	}
+
+	if previous != nil {
+		// Insert deleted items after the item they followed before.
+		insertAt := 0
+		for _, item := range previous.Items {
+			found := false
+			for i, l := range lines {
+				if l.key == item.Key && l.marker != markerDeleted {
+					insertAt = i + 1
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+
+			deleted := markedLine{key: item.Key, marker: markerDeleted, line: color.RedString(table.RemoveColors(item.Line))}
+			lines = append(lines[:insertAt], append([]markedLine{deleted}, lines[insertAt:]...)...)
+			insertAt++
+		}
+	}
+
+	var out strings.Builder
+	if current.Header != "" {
+		out.WriteString(markerNone + current.Header + "\n")
+	}
+	for _, l := range lines {
+		out.WriteString(l.marker + l.line + "\n")
+	}
+	if current.Footer != "" {
+		out.WriteString("\n" + current.Footer + "\n")
+	}
+
+	return out.String()
+}
@@ -0,0 +1,213 @@
+package watch
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/formatting"
+)
+
+type cluster struct {
+	ID      string `json:"id"`
+	Workers int    `json:"workers"`
+}
+
+func snapshot(clusters ...cluster) *Snapshot {
+	s := &Snapshot{Header: "ID     WORKERS"}
+	for _, c := range clusters {
+		s.Items = append(s.Items, Item{
+			Key:    c.ID,
+			Line:   c.ID + "  " + strconv.Itoa(c.Workers),
+			Object: c,
+		})
+	}
+
+	return s
+}
+
+func Test_Diff(t *testing.T) {
+	testCases := []struct {
+		previous *Snapshot
+		current  *Snapshot
+		expected []Event
+	}{
+		{
+			nil,
+			snapshot(cluster{"f01r4", 3}),
+			[]Event{{EventAdded, cluster{"f01r4", 3}}},
+		},
+		{
+			snapshot(cluster{"f01r4", 3}, cluster{"a7k4", 1}),
+			snapshot(cluster{"f01r4", 3}, cluster{"a7k4", 1}),
+			nil,
+		},
+		{
+			snapshot(cluster{"f01r4", 3}, cluster{"a7k4", 1}),
+			snapshot(cluster{"f01r4", 4}, cluster{"x9z1", 1}),
+			[]Event{
+				{EventModified, cluster{"f01r4", 4}},
+				{EventAdded, cluster{"x9z1", 1}},
+				{EventDeleted, cluster{"a7k4", 1}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			events := Diff(tc.previous, tc.current)
+			if len(events) != len(tc.expected) {
+				t.Fatalf("Expected %d events, got %#v", len(tc.expected), events)
+			}
+			for j := range events {
+				if events[j].Type != tc.expected[j].Type || events[j].Object != tc.expected[j].Object {
+					t.Errorf("Event %d: expected %#v, got %#v", j, tc.expected[j], events[j])
+				}
+			}
+		})
+	}
+}
+
+func Test_renderTable(t *testing.T) {
+	previous := snapshot(cluster{"f01r4", 3}, cluster{"a7k4", 1}, cluster{"b8s2", 2})
+	current := snapshot(cluster{"f01r4", 4}, cluster{"b8s2", 2}, cluster{"x9z1", 1})
+	current.Footer = "There is 1 additional cluster currently being deleted."
+
+	expected := `  ID     WORKERS
+~ f01r4  4
+- a7k4  1
+  b8s2  2
++ x9z1  1
+
+There is 1 additional cluster currently being deleted.
+`
+	if out := renderTable(previous, current); out != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, out)
+	}
+
+	// Without a previous snapshot, nothing is marked.
+	expected = "  ID     WORKERS\n  f01r4  3\n  a7k4  1\n  b8s2  2\n"
+	if out := renderTable(nil, previous); out != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, out)
+	}
+}
+
+func Test_renderEvents(t *testing.T) {
+	events := []Event{
+		{EventAdded, cluster{"f01r4", 3}},
+		{EventDeleted, cluster{"a7k4", 1}},
+	}
+
+	out, err := renderEvents(formatting.OutputFormatJSON, events)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `{"type":"ADDED","object":{"id":"f01r4","workers":3}}
+{"type":"DELETED","object":{"id":"a7k4","workers":1}}
+`
+	if out != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, out)
+	}
+
+	out, err = renderEvents(formatting.OutputFormatYAML, events[:1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected = "---\nobject:\n  id: f01r4\n  workers: 3\ntype: ADDED\n"
+	if out != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, out)
+	}
+}
+
+// Test_Run tests polling with events, where a failing poll in between
+// is skipped.
+func Test_Run(t *testing.T) {
+	responses := []*Snapshot{
+		snapshot(cluster{"f01r4", 3}),
+		nil,
+		snapshot(cluster{"f01r4", 4}),
+	}
+	polls := 0
+
+	var out bytes.Buffer
+	err := Run(Config{
+		Fetch: func() (*Snapshot, error) {
+			s := responses[polls]
+			polls++
+			if s == nil {
+				return nil, microerror.Mask(&microerror.Error{Kind: "testError"})
+			}
+			return s, nil
+		},
+		Interval:     time.Millisecond,
+		OutputFormat: formatting.OutputFormatJSON,
+		Output:       &out,
+		Polls:        3,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `{"type":"ADDED","object":{"id":"f01r4","workers":3}}
+{"type":"MODIFIED","object":{"id":"f01r4","workers":4}}
+`
+	if out.String() != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+// Test_RunTable tests that tables are printed one after the other when not
+// redrawing, and that an error in the first poll is returned.
+func Test_RunTable(t *testing.T) {
+	polls := 0
+
+	var out bytes.Buffer
+	err := Run(Config{
+		Fetch: func() (*Snapshot, error) {
+			polls++
+			return snapshot(cluster{"f01r4", polls}), nil
+		},
+		Interval: time.Millisecond,
+		Title:    "gsctl list clusters",
+		Output:   &out,
+		Polls:    2,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if strings.Count(out.String(), "Every 1ms: gsctl list clusters") != 2 {
+		t.Errorf("Expected two tables, got\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "\n~ f01r4  2\n") {
+		t.Errorf("Expected modified line to be marked, got\n%s", out.String())
+	}
+
+	err = Run(Config{
+		Fetch: func() (*Snapshot, error) {
+			return nil, microerror.Mask(&microerror.Error{Kind: "testError"})
+		},
+		Output: &out,
+		Polls:  1,
+	})
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func Test_SupportsOutputFormat(t *testing.T) {
+	for _, f := range []string{"", "table", "wide", "json", "yaml"} {
+		if !SupportsOutputFormat(f) {
+			t.Errorf("Expected output format %q to be supported", f)
+		}
+	}
+	for _, f := range []string{"csv", "jsonpath={.id}", "custom-columns=ID:.id"} {
+		if SupportsOutputFormat(f) {
+			t.Errorf("Expected output format %q not to be supported", f)
+		}
+	}
+}
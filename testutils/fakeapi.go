@@ -0,0 +1,1456 @@
+package testutils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+)
+
+// FakeAPI is an in-memory fake of the Giant Swarm API, for testing commands
+// end to end without an installation. It keeps state, so that e. g. a
+// cluster created via the API can be shown, scaled and deleted afterwards.
+//
+// FakeAPI is a http.Handler, to be used with httptest.NewServer or via
+// 'gsctl dev-api-server'. Any auth token is accepted.
+type FakeAPI struct {
+	mutex sync.Mutex
+
+	provider         string
+	installationName string
+
+	releases      []map[string]interface{}
+	organizations map[string]*fakeOrganization
+	clusters      map[string]*fakeCluster
+	// clusterIDs keeps the clusters in the order of creation.
+	clusterIDs []string
+
+	random *rand.Rand
+	// now returns the current time, e. g. for creation dates.
+	now func() time.Time
+}
+
+type fakeOrganization struct {
+	credentials []map[string]interface{}
+}
+
+type fakeCluster struct {
+	// v5 is true for clusters supporting node pools.
+	v5        bool
+	details   map[string]interface{}
+	nodePools []map[string]interface{}
+	keyPairs  []map[string]interface{}
+	apps      []map[string]interface{}
+}
+
+// fakeRoute maps a request method and path pattern to a handler. In the
+// pattern, '*' matches any path segment, which is then passed to the
+// handler as a parameter.
+type fakeRoute struct {
+	method  string
+	pattern string
+	handler func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+const (
+	// nodePoolsReleaseAWS is the first release supporting node pools on AWS.
+	nodePoolsReleaseAWS = "10.0.0"
+	// nodePoolsReleaseAzure is the first release supporting node pools on Azure.
+	nodePoolsReleaseAzure = "13.0.0"
+	// haMastersRelease is the first release supporting high-availability
+	// masters on AWS.
+	haMastersRelease = "11.5.0"
+
+	fakeBaseDomain = "k8s.fake.example.com"
+)
+
+// NewFakeAPI creates a fake API for the given provider ('aws', 'azure' or
+// 'kvm'), with a few releases and the organization 'acme'.
+func NewFakeAPI(provider string) *FakeAPI {
+	if provider == "" {
+		provider = "aws"
+	}
+
+	f := &FakeAPI{
+		provider:         provider,
+		installationName: "fake",
+		organizations:    map[string]*fakeOrganization{},
+		clusters:         map[string]*fakeCluster{},
+		random:           rand.New(rand.NewSource(1)),
+		now:              time.Now,
+	}
+
+	for _, r := range []struct {
+		version    string
+		timestamp  string
+		kubernetes string
+		active     bool
+	}{
+		{"9.3.5", "2020-05-12T10:00:00Z", "1.15.11", false},
+		{"11.5.0", "2020-07-23T12:00:00Z", "1.16.13", true},
+		{"12.1.4", "2020-09-30T09:00:00Z", "1.17.11", true},
+		{"13.0.1", "2020-10-14T14:00:00Z", "1.18.9", true},
+	} {
+		f.releases = append(f.releases, map[string]interface{}{
+			"version":   r.version,
+			"timestamp": r.timestamp,
+			"active":    r.active,
+			"components": []interface{}{
+				map[string]interface{}{"name": "kubernetes", "version": r.kubernetes},
+				map[string]interface{}{"name": "containerlinux", "version": "2512.5.0"},
+			},
+			"changelog": []interface{}{
+				map[string]interface{}{"component": "kubernetes", "description": "Updated to " + r.kubernetes + "."},
+			},
+		})
+	}
+
+	f.AddOrganization("acme")
+
+	return f
+}
+
+// AddOrganization adds an organization, so that clusters can be created
+// for it.
+func (f *FakeAPI) AddOrganization(id string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.organizations[id]; !ok {
+		f.organizations[id] = &fakeOrganization{}
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (f *FakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	// The root path is used by 'gsctl ping'.
+	if r.URL.Path == "/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Creating an auth token is the only operation not requiring one.
+	if !(r.URL.Path == "/v4/auth-tokens/" && r.Method == http.MethodPost) && r.Header.Get("Authorization") == "" {
+		writeFakeError(w, http.StatusUnauthorized, "PERMISSION_DENIED", "The requested resource cannot be accessed.")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	pathMatched := false
+	for _, route := range f.routes() {
+		params, ok := matchFakeRoute(route.pattern, segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if route.method == r.Method {
+			route.handler(w, r, params)
+			return
+		}
+	}
+
+	if pathMatched {
+		writeFakeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", fmt.Sprintf("Method %s is not supported for this resource.", r.Method))
+		return
+	}
+
+	writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "The requested resource could not be found.")
+}
+
+func (f *FakeAPI) routes() []fakeRoute {
+	routes := []fakeRoute{
+		{http.MethodPost, "v4/auth-tokens", f.createAuthToken},
+		{http.MethodDelete, "v4/auth-tokens", f.deleteAuthToken},
+		{http.MethodGet, "v4/info", f.getInfo},
+		{http.MethodGet, "v4/releases", f.getReleases},
+		{http.MethodGet, "v4/organizations", f.getOrganizations},
+		{http.MethodGet, "v4/organizations/*/credentials", f.getCredentials},
+		{http.MethodPost, "v4/organizations/*/credentials", f.createCredentials},
+		{http.MethodGet, "v4/organizations/*/credentials/*", f.getCredential},
+		{http.MethodGet, "v4/clusters", f.getClusters},
+		{http.MethodPost, "v4/clusters", f.createClusterV4},
+		{http.MethodGet, "v4/clusters/*", f.getClusterV4},
+		{http.MethodPatch, "v4/clusters/*", f.modifyClusterV4},
+		{http.MethodDelete, "v4/clusters/*", f.deleteCluster},
+		{http.MethodGet, "v4/clusters/*/status", f.getClusterStatus},
+		{http.MethodGet, "v4/clusters/*/key-pairs", f.getKeyPairs},
+		{http.MethodPost, "v4/clusters/*/key-pairs", f.createKeyPair},
+		{http.MethodPost, "v5/clusters", f.createClusterV5},
+		{http.MethodPost, "v5/clusters/by_label", f.getClustersByLabel},
+		{http.MethodGet, "v5/clusters/*", f.getClusterV5},
+		{http.MethodPatch, "v5/clusters/*", f.modifyClusterV5},
+		{http.MethodGet, "v5/clusters/*/labels", f.getClusterLabels},
+		{http.MethodPut, "v5/clusters/*/labels", f.setClusterLabels},
+		{http.MethodGet, "v5/clusters/*/nodepools", f.getNodePools},
+		{http.MethodPost, "v5/clusters/*/nodepools", f.createNodePool},
+		{http.MethodGet, "v5/clusters/*/nodepools/*", f.getNodePool},
+		{http.MethodPatch, "v5/clusters/*/nodepools/*", f.modifyNodePool},
+		{http.MethodDelete, "v5/clusters/*/nodepools/*", f.deleteNodePool},
+	}
+
+	for _, version := range []string{"v4", "v5"} {
+		routes = append(routes,
+			fakeRoute{http.MethodGet, version + "/clusters/*/apps", f.getApps},
+			fakeRoute{http.MethodPut, version + "/clusters/*/apps/*", f.createApp},
+			fakeRoute{http.MethodPatch, version + "/clusters/*/apps/*", f.modifyApp},
+			fakeRoute{http.MethodDelete, version + "/clusters/*/apps/*", f.deleteApp},
+		)
+	}
+
+	return routes
+}
+
+// matchFakeRoute returns the path segments matching the '*' placeholders
+// of a pattern, and whether the path matches.
+func matchFakeRoute(pattern string, segments []string) ([]string, bool) {
+	patternSegments := strings.Split(pattern, "/")
+	if len(patternSegments) != len(segments) {
+		return nil, false
+	}
+
+	var params []string
+	for i, p := range patternSegments {
+		if p == "*" {
+			params = append(params, segments[i])
+		} else if p != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (f *FakeAPI) createAuthToken(w http.ResponseWriter, r *http.Request, params []string) {
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	password, _ := base64.StdEncoding.DecodeString(stringValue(body["password_base64"]))
+	if stringValue(body["email"]) == "" || len(password) == 0 {
+		writeFakeError(w, http.StatusUnauthorized, "PERMISSION_DENIED", "The email or password are not correct.")
+		return
+	}
+
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{"auth_token": f.newID(32)})
+}
+
+func (f *FakeAPI) deleteAuthToken(w http.ResponseWriter, r *http.Request, params []string) {
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{"code": "RESOURCE_DELETED", "message": "The authentication token has been successfully deleted."})
+}
+
+func (f *FakeAPI) getInfo(w http.ResponseWriter, r *http.Request, params []string) {
+	general := map[string]interface{}{
+		"installation_name": f.installationName,
+		"provider":          f.provider,
+	}
+	workers := map[string]interface{}{
+		"count_per_cluster": map[string]interface{}{"default": 3, "max": 20},
+	}
+	features := map[string]interface{}{}
+
+	switch f.provider {
+	case "aws":
+		general["datacenter"] = "eu-central-1"
+		general["availability_zones"] = map[string]interface{}{"default": 1, "max": 3, "zones": f.zones()}
+		workers["instance_type"] = map[string]interface{}{"default": "m5.xlarge", "options": []string{"m5.large", "m5.xlarge", "m5.2xlarge"}}
+		features["nodepools"] = map[string]interface{}{"release_version_minimum": nodePoolsReleaseAWS}
+		features["ha_masters"] = map[string]interface{}{"release_version_minimum": haMastersRelease}
+	case "azure":
+		general["datacenter"] = "westeurope"
+		general["availability_zones"] = map[string]interface{}{"default": 1, "max": 3, "zones": f.zones()}
+		workers["vm_size"] = map[string]interface{}{"default": "Standard_D4s_v3", "options": []string{"Standard_D4s_v3", "Standard_D8s_v3"}}
+		features["nodepools"] = map[string]interface{}{"release_version_minimum": nodePoolsReleaseAzure}
+	}
+
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+		"general":  general,
+		"workers":  workers,
+		"features": features,
+	})
+}
+
+func (f *FakeAPI) getReleases(w http.ResponseWriter, r *http.Request, params []string) {
+	writeFakeJSON(w, http.StatusOK, f.releases)
+}
+
+func (f *FakeAPI) getOrganizations(w http.ResponseWriter, r *http.Request, params []string) {
+	ids := make([]string, 0, len(f.organizations))
+	for id := range f.organizations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	items := []interface{}{}
+	for _, id := range ids {
+		items = append(items, map[string]interface{}{"id": id})
+	}
+
+	writeFakeJSON(w, http.StatusOK, items)
+}
+
+func (f *FakeAPI) getCredentials(w http.ResponseWriter, r *http.Request, params []string) {
+	org, ok := f.organization(w, params[0])
+	if !ok {
+		return
+	}
+
+	items := []interface{}{}
+	for _, c := range org.credentials {
+		items = append(items, c)
+	}
+
+	writeFakeJSON(w, http.StatusOK, items)
+}
+
+func (f *FakeAPI) createCredentials(w http.ResponseWriter, r *http.Request, params []string) {
+	org, ok := f.organization(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	if len(org.credentials) > 0 {
+		writeFakeError(w, http.StatusConflict, "RESOURCE_ALREADY_EXISTS", "The organization already has credentials set.")
+		return
+	}
+	if stringValue(body["provider"]) != f.provider {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The provider must be '%s'.", f.provider))
+		return
+	}
+
+	credential := map[string]interface{}{
+		"id":       f.newID(6),
+		"provider": f.provider,
+	}
+	switch f.provider {
+	case "aws":
+		credential["aws"] = body["aws"]
+	case "azure":
+		azure := objectValue(objectValue(body["azure"])["credential"])
+		credential["azure"] = map[string]interface{}{
+			"credential": map[string]interface{}{
+				"client_id":       azure["client_id"],
+				"subscription_id": azure["subscription_id"],
+				"tenant_id":       azure["tenant_id"],
+			},
+		}
+	}
+	org.credentials = append(org.credentials, credential)
+
+	w.Header().Set("Location", fmt.Sprintf("/v4/organizations/%s/credentials/%s/", params[0], credential["id"]))
+	writeFakeJSON(w, http.StatusCreated, map[string]interface{}{"code": "RESOURCE_CREATED", "message": "A new set of credentials has been created."})
+}
+
+func (f *FakeAPI) getCredential(w http.ResponseWriter, r *http.Request, params []string) {
+	org, ok := f.organization(w, params[0])
+	if !ok {
+		return
+	}
+
+	for _, c := range org.credentials {
+		if c["id"] == params[1] {
+			writeFakeJSON(w, http.StatusOK, c)
+			return
+		}
+	}
+
+	writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "The credentials could not be found.")
+}
+
+func (f *FakeAPI) getClusters(w http.ResponseWriter, r *http.Request, params []string) {
+	items := []interface{}{}
+	for _, id := range f.clusterIDs {
+		items = append(items, f.clusterListItem(f.clusters[id]))
+	}
+
+	writeFakeJSON(w, http.StatusOK, items)
+}
+
+func (f *FakeAPI) getClustersByLabel(w http.ResponseWriter, r *http.Request, params []string) {
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	selector, err := parseFakeSelector(stringValue(body["labels"]))
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		return
+	}
+
+	items := []interface{}{}
+	for _, id := range f.clusterIDs {
+		c := f.clusters[id]
+		if c.v5 && selector(stringMapValue(c.details["labels"])) {
+			items = append(items, f.clusterListItem(c))
+		}
+	}
+
+	writeFakeJSON(w, http.StatusOK, items)
+}
+
+func (f *FakeAPI) createClusterV4(w http.ResponseWriter, r *http.Request, params []string) {
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+	details, ok := f.newClusterDetails(w, body)
+	if !ok {
+		return
+	}
+
+	if f.supportsNodePools(stringValue(details["release_version"])) {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "Clusters with this release must be created via the v5 API.")
+		return
+	}
+
+	numZones := intValue(body["availability_zones"], 1)
+	if f.provider != "kvm" {
+		if numZones < 1 || numZones > len(f.zones()) {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The number of availability zones must be between 1 and %d.", len(f.zones())))
+			return
+		}
+		details["availability_zones"] = f.zones()[:numZones]
+	}
+
+	scaling := objectValue(body["scaling"])
+	min, max := intValue(scaling["min"], 3), intValue(scaling["max"], 3)
+	if min > max {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "The minimum number of workers must not be greater than the maximum.")
+		return
+	}
+	details["scaling"] = map[string]interface{}{"min": min, "max": max}
+
+	worker := f.defaultWorker()
+	if workers, ok := body["workers"].([]interface{}); ok && len(workers) > 0 {
+		mergeFakePatch(worker, objectValue(workers[0]))
+	}
+	details["workers"] = fakeWorkers(worker, min)
+
+	if f.provider == "kvm" {
+		details["kvm"] = map[string]interface{}{
+			"port_mappings": []interface{}{
+				map[string]interface{}{"port": 30010, "protocol": "http"},
+				map[string]interface{}{"port": 30011, "protocol": "https"},
+			},
+		}
+	}
+
+	f.addCluster(&fakeCluster{details: details})
+
+	w.Header().Set("Location", fmt.Sprintf("/v4/clusters/%s/", details["id"]))
+	writeFakeJSON(w, http.StatusCreated, map[string]interface{}{
+		"code":    "RESOURCE_CREATED",
+		"message": fmt.Sprintf("A new cluster has been created with ID '%s'", details["id"]),
+	})
+}
+
+func (f *FakeAPI) createClusterV5(w http.ResponseWriter, r *http.Request, params []string) {
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+	details, ok := f.newClusterDetails(w, body)
+	if !ok {
+		return
+	}
+
+	if !f.supportsNodePools(stringValue(details["release_version"])) {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "Clusters with this release must be created via the v4 API.")
+		return
+	}
+
+	masterNodes := objectValue(body["master_nodes"])
+	highAvailability := f.provider == "aws" && releaseAtLeast(stringValue(details["release_version"]), haMastersRelease)
+	if value, ok := masterNodes["high_availability"].(bool); ok {
+		highAvailability = value
+	}
+	if highAvailability && (f.provider != "aws" || !releaseAtLeast(stringValue(details["release_version"]), haMastersRelease)) {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "High-availability masters are not supported with this release.")
+		return
+	}
+	details["master_nodes"] = f.masterNodes(highAvailability)
+	details["master"] = map[string]interface{}{"availability_zone": f.zones()[0]}
+
+	details["labels"] = map[string]interface{}{
+		"giantswarm.io/cluster":      details["id"],
+		"giantswarm.io/organization": details["owner"],
+	}
+	details["conditions"] = []interface{}{
+		map[string]interface{}{"condition": "Created", "last_transition_time": details["create_date"]},
+	}
+	details["versions"] = []interface{}{
+		map[string]interface{}{"version": details["release_version"], "last_transition_time": details["create_date"]},
+	}
+
+	f.addCluster(&fakeCluster{v5: true, details: details})
+
+	w.Header().Set("Location", fmt.Sprintf("/v5/clusters/%s/", details["id"]))
+	writeFakeJSON(w, http.StatusCreated, details)
+}
+
+func (f *FakeAPI) getClusterV4(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.cluster(w, params[0])
+	if !ok {
+		return
+	}
+
+	writeFakeJSON(w, http.StatusOK, f.clusterDetailsV4(c))
+}
+
+func (f *FakeAPI) getClusterV5(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.clusterV5(w, params[0])
+	if !ok {
+		return
+	}
+
+	writeFakeJSON(w, http.StatusOK, c.details)
+}
+
+func (f *FakeAPI) modifyClusterV4(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.cluster(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	if release := stringValue(body["release_version"]); release != "" && f.release(release) == nil {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("Release version '%s' does not exist.", release))
+		return
+	}
+	if owner := stringValue(body["owner"]); owner != "" && f.organizations[owner] == nil {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("Organization '%s' does not exist.", owner))
+		return
+	}
+	if !c.v5 && body["scaling"] != nil {
+		scaling := objectValue(c.details["scaling"])
+		newScaling := objectValue(body["scaling"])
+		min, max := intValue(newScaling["min"], intValue(scaling["min"], 0)), intValue(newScaling["max"], intValue(scaling["max"], 0))
+		if min > max {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "The minimum number of workers must not be greater than the maximum.")
+			return
+		}
+		body["scaling"] = map[string]interface{}{"min": min, "max": max}
+
+		worker := f.defaultWorker()
+		if workers, ok := c.details["workers"].([]interface{}); ok && len(workers) > 0 {
+			worker = objectValue(workers[0])
+		}
+		body["workers"] = fakeWorkers(worker, min)
+	} else {
+		delete(body, "scaling")
+		delete(body, "workers")
+	}
+
+	mergeFakePatch(c.details, body)
+	if c.v5 {
+		f.updateLabels(c)
+	}
+
+	writeFakeJSON(w, http.StatusOK, f.clusterDetailsV4(c))
+}
+
+func (f *FakeAPI) modifyClusterV5(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.clusterV5(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	patch := map[string]interface{}{}
+	if name := stringValue(body["name"]); name != "" {
+		patch["name"] = name
+	}
+	if release := stringValue(body["release_version"]); release != "" {
+		if f.release(release) == nil {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("Release version '%s' does not exist.", release))
+			return
+		}
+		patch["release_version"] = release
+	}
+	if highAvailability, ok := objectValue(body["master_nodes"])["high_availability"].(bool); ok {
+		current := objectValue(c.details["master_nodes"])["high_availability"] == true
+		if current && !highAvailability {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "Switching from high-availability masters to a single master is not supported.")
+			return
+		}
+		patch["master_nodes"] = f.masterNodes(highAvailability)
+	}
+
+	mergeFakePatch(c.details, patch)
+
+	writeFakeJSON(w, http.StatusOK, c.details)
+}
+
+func (f *FakeAPI) deleteCluster(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := f.cluster(w, params[0]); !ok {
+		return
+	}
+
+	delete(f.clusters, params[0])
+	for i, id := range f.clusterIDs {
+		if id == params[0] {
+			f.clusterIDs = append(f.clusterIDs[:i], f.clusterIDs[i+1:]...)
+			break
+		}
+	}
+
+	writeFakeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"code":    "RESOURCE_DELETION_STARTED",
+		"message": fmt.Sprintf("The cluster with ID '%s' is being deleted.", params[0]),
+	})
+}
+
+func (f *FakeAPI) getClusterStatus(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.cluster(w, params[0])
+	if !ok {
+		return
+	}
+
+	masters := 1
+	workers := len(arrayValue(c.details["workers"]))
+	if c.v5 {
+		masters = intValue(objectValue(c.details["master_nodes"])["num_ready"], 1)
+		workers = 0
+		for _, np := range c.nodePools {
+			workers += intValue(objectValue(np["status"])["nodes_ready"], 0)
+		}
+	}
+
+	nodes := []interface{}{}
+	for i := 0; i < masters+workers; i++ {
+		role := "worker"
+		if i < masters {
+			role = "master"
+		}
+		nodes = append(nodes, map[string]interface{}{
+			"name":    fmt.Sprintf("ip-10-1-%d-%d.%s", i/250, i%250+10, fakeBaseDomain),
+			"labels":  map[string]interface{}{"role": role},
+			"version": "3.1.1",
+		})
+	}
+
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+		"cluster": map[string]interface{}{
+			// Clusters are created instantly.
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"lastTransitionTime": c.details["create_date"],
+					"status":             "True",
+					"type":               "Created",
+				},
+			},
+			"nodes":   nodes,
+			"scaling": map[string]interface{}{"desiredCapacity": workers},
+			"versions": []interface{}{
+				map[string]interface{}{
+					"date":               c.details["create_date"],
+					"lastTransitionTime": c.details["create_date"],
+					"semver":             c.details["release_version"],
+				},
+			},
+		},
+	})
+}
+
+func (f *FakeAPI) getClusterLabels(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.clusterV5(w, params[0])
+	if !ok {
+		return
+	}
+
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{"labels": c.details["labels"]})
+}
+
+func (f *FakeAPI) setClusterLabels(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.clusterV5(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	labels := objectValue(body["labels"])
+	for key := range labels {
+		if strings.Contains(key, "giantswarm.io") {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("Label '%s' is reserved and cannot be changed.", key))
+			return
+		}
+	}
+
+	current := objectValue(c.details["labels"])
+	mergeFakePatch(current, labels)
+	c.details["labels"] = current
+
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{"labels": current})
+}
+
+func (f *FakeAPI) getNodePools(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.clusterV5(w, params[0])
+	if !ok {
+		return
+	}
+
+	items := []interface{}{}
+	for _, np := range c.nodePools {
+		items = append(items, np)
+	}
+
+	writeFakeJSON(w, http.StatusOK, items)
+}
+
+func (f *FakeAPI) createNodePool(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.clusterV5(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	zones := f.zones()[:1]
+	requestedZones := objectValue(body["availability_zones"])
+	if list := arrayValue(requestedZones["zones"]); len(list) > 0 {
+		zones = nil
+		for _, z := range list {
+			zones = append(zones, stringValue(z))
+		}
+	} else if number := intValue(requestedZones["number"], 0); number > 0 {
+		if number > len(f.zones()) {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The number of availability zones must be between 1 and %d.", len(f.zones())))
+			return
+		}
+		zones = f.zones()[:number]
+	}
+
+	scaling := objectValue(body["scaling"])
+	min, max := intValue(scaling["min"], 3), intValue(scaling["max"], 10)
+	if min > max {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "The minimum number of nodes must not be greater than the maximum.")
+		return
+	}
+
+	nodeSpec := map[string]interface{}{
+		"volume_sizes_gb": map[string]interface{}{"docker": 100, "kubelet": 100},
+	}
+	instanceType := ""
+	switch f.provider {
+	case "aws":
+		nodeSpec["aws"] = map[string]interface{}{
+			"instance_type":            "m5.xlarge",
+			"use_alike_instance_types": false,
+			"instance_distribution": map[string]interface{}{
+				"on_demand_base_capacity":                  0,
+				"on_demand_percentage_above_base_capacity": 100,
+			},
+		}
+	case "azure":
+		nodeSpec["azure"] = map[string]interface{}{
+			"vm_size":        "Standard_D4s_v3",
+			"spot_instances": map[string]interface{}{"enabled": false, "max_price": 0},
+		}
+	}
+	mergeFakePatch(nodeSpec, objectValue(body["node_spec"]))
+	if f.provider == "aws" {
+		instanceType = stringValue(objectValue(nodeSpec["aws"])["instance_type"])
+	} else {
+		instanceType = stringValue(objectValue(nodeSpec["azure"])["vm_size"])
+	}
+
+	name := stringValue(body["name"])
+	if name == "" {
+		name = "Unnamed node pool"
+	}
+
+	np := map[string]interface{}{
+		"id":                 f.newID(5),
+		"name":               name,
+		"availability_zones": zones,
+		"node_spec":          nodeSpec,
+		"scaling":            map[string]interface{}{"min": min, "max": max},
+		"status": map[string]interface{}{
+			"nodes":          min,
+			"nodes_ready":    min,
+			"spot_instances": 0,
+			"instance_types": []string{instanceType},
+		},
+		"subnet": fmt.Sprintf("10.1.%d.0/24", len(c.nodePools)),
+	}
+	c.nodePools = append(c.nodePools, np)
+
+	w.Header().Set("Location", fmt.Sprintf("/v5/clusters/%s/nodepools/%s/", params[0], np["id"]))
+	writeFakeJSON(w, http.StatusCreated, np)
+}
+
+func (f *FakeAPI) getNodePool(w http.ResponseWriter, r *http.Request, params []string) {
+	_, np, ok := f.nodePool(w, params[0], params[1])
+	if !ok {
+		return
+	}
+
+	writeFakeJSON(w, http.StatusOK, np)
+}
+
+func (f *FakeAPI) modifyNodePool(w http.ResponseWriter, r *http.Request, params []string) {
+	_, np, ok := f.nodePool(w, params[0], params[1])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	if name := stringValue(body["name"]); name != "" {
+		np["name"] = name
+	}
+	if body["scaling"] != nil {
+		scaling := objectValue(np["scaling"])
+		newScaling := objectValue(body["scaling"])
+		min, max := intValue(newScaling["min"], intValue(scaling["min"], 0)), intValue(newScaling["max"], intValue(scaling["max"], 0))
+		if min > max {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "The minimum number of nodes must not be greater than the maximum.")
+			return
+		}
+		np["scaling"] = map[string]interface{}{"min": min, "max": max}
+
+		// Nodes are added or removed instantly to fit the new limits.
+		status := objectValue(np["status"])
+		nodes := intValue(status["nodes"], 0)
+		if nodes < min {
+			nodes = min
+		} else if nodes > max {
+			nodes = max
+		}
+		status["nodes"] = nodes
+		status["nodes_ready"] = nodes
+	}
+
+	writeFakeJSON(w, http.StatusOK, np)
+}
+
+func (f *FakeAPI) deleteNodePool(w http.ResponseWriter, r *http.Request, params []string) {
+	c, np, ok := f.nodePool(w, params[0], params[1])
+	if !ok {
+		return
+	}
+
+	for i := range c.nodePools {
+		if c.nodePools[i]["id"] == np["id"] {
+			c.nodePools = append(c.nodePools[:i], c.nodePools[i+1:]...)
+			break
+		}
+	}
+
+	writeFakeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"code":    "RESOURCE_DELETION_STARTED",
+		"message": fmt.Sprintf("The node pool with ID '%s' is being deleted.", params[1]),
+	})
+}
+
+func (f *FakeAPI) getKeyPairs(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.cluster(w, params[0])
+	if !ok {
+		return
+	}
+
+	items := []interface{}{}
+	for _, kp := range c.keyPairs {
+		items = append(items, kp)
+	}
+
+	writeFakeJSON(w, http.StatusOK, items)
+}
+
+func (f *FakeAPI) createKeyPair(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.cluster(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	if stringValue(body["description"]) == "" {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "A description must be given.")
+		return
+	}
+
+	cnPrefix := stringValue(body["cn_prefix"])
+	if cnPrefix == "" {
+		cnPrefix = f.newID(8)
+	}
+
+	idParts := make([]string, 20)
+	for i := range idParts {
+		idParts[i] = fmt.Sprintf("%02x", f.random.Intn(256))
+	}
+
+	keyPair := map[string]interface{}{
+		"id":                        strings.Join(idParts, ":"),
+		"description":               body["description"],
+		"ttl_hours":                 intValue(body["ttl_hours"], 720),
+		"create_date":               f.now().UTC().Format(time.RFC3339),
+		"common_name":               fmt.Sprintf("%s.user.api.%s.%s", cnPrefix, params[0], fakeBaseDomain),
+		"certificate_organizations": stringValue(body["certificate_organizations"]),
+	}
+	c.keyPairs = append(c.keyPairs, keyPair)
+
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":                         keyPair["id"],
+		"description":                keyPair["description"],
+		"ttl_hours":                  keyPair["ttl_hours"],
+		"create_date":                keyPair["create_date"],
+		"certificate_authority_data": fakePEM("CERTIFICATE", "fake certificate authority"),
+		"client_certificate_data":    fakePEM("CERTIFICATE", "fake client certificate "+keyPair["common_name"].(string)),
+		"client_key_data":            fakePEM("RSA PRIVATE KEY", "fake client key"),
+	})
+}
+
+func (f *FakeAPI) getApps(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.cluster(w, params[0])
+	if !ok {
+		return
+	}
+
+	items := []interface{}{}
+	for _, app := range c.apps {
+		items = append(items, app)
+	}
+
+	writeFakeJSON(w, http.StatusOK, items)
+}
+
+func (f *FakeAPI) createApp(w http.ResponseWriter, r *http.Request, params []string) {
+	c, ok := f.cluster(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	for _, app := range c.apps {
+		if objectValue(app["metadata"])["name"] == params[1] {
+			writeFakeError(w, http.StatusConflict, "RESOURCE_ALREADY_EXISTS", fmt.Sprintf("An app named '%s' already exists.", params[1]))
+			return
+		}
+	}
+
+	spec := objectValue(body["spec"])
+	for _, field := range []string{"catalog", "name", "namespace", "version"} {
+		if stringValue(spec[field]) == "" {
+			writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The field 'spec.%s' must be given.", field))
+			return
+		}
+	}
+
+	app := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": params[1]},
+		"spec": map[string]interface{}{
+			"catalog":   spec["catalog"],
+			"name":      spec["name"],
+			"namespace": spec["namespace"],
+			"version":   spec["version"],
+			"user_config": map[string]interface{}{
+				"configmap": map[string]interface{}{"name": "", "namespace": ""},
+				"secret":    map[string]interface{}{"name": "", "namespace": ""},
+			},
+		},
+	}
+	setFakeAppStatus(app, f.now())
+	c.apps = append(c.apps, app)
+
+	writeFakeJSON(w, http.StatusOK, app)
+}
+
+func (f *FakeAPI) modifyApp(w http.ResponseWriter, r *http.Request, params []string) {
+	_, app, ok := f.app(w, params[0], params[1])
+	if !ok {
+		return
+	}
+	body, ok := readFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	spec := objectValue(app["spec"])
+	if version := stringValue(objectValue(body["spec"])["version"]); version != "" {
+		spec["version"] = version
+	}
+	setFakeAppStatus(app, f.now())
+
+	writeFakeJSON(w, http.StatusOK, app)
+}
+
+func (f *FakeAPI) deleteApp(w http.ResponseWriter, r *http.Request, params []string) {
+	c, app, ok := f.app(w, params[0], params[1])
+	if !ok {
+		return
+	}
+
+	for i := range c.apps {
+		if objectValue(c.apps[i]["metadata"])["name"] == objectValue(app["metadata"])["name"] {
+			c.apps = append(c.apps[:i], c.apps[i+1:]...)
+			break
+		}
+	}
+
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+		"code":    "RESOURCE_DELETED",
+		"message": fmt.Sprintf("The app '%s' has been deleted.", params[1]),
+	})
+}
+
+// newClusterDetails validates the fields common to v4 and v5 cluster
+// creation and returns the details of the new cluster.
+func (f *FakeAPI) newClusterDetails(w http.ResponseWriter, body map[string]interface{}) (map[string]interface{}, bool) {
+	owner := stringValue(body["owner"])
+	if owner == "" {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "The owner organization must be given.")
+		return nil, false
+	}
+	if f.organizations[owner] == nil {
+		writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("Organization '%s' does not exist.", owner))
+		return nil, false
+	}
+
+	release := stringValue(body["release_version"])
+	if release == "" {
+		release = f.latestRelease()
+	} else if f.release(release) == nil {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("Release version '%s' does not exist.", release))
+		return nil, false
+	}
+
+	name := stringValue(body["name"])
+	if name == "" {
+		name = "Unnamed cluster"
+	}
+
+	id := f.newID(5)
+	details := map[string]interface{}{
+		"id":              id,
+		"name":            name,
+		"owner":           owner,
+		"release_version": release,
+		"create_date":     f.now().UTC().Format(time.RFC3339Nano),
+		"api_endpoint":    fmt.Sprintf("https://api.%s.%s", id, fakeBaseDomain),
+	}
+
+	org := f.organizations[owner]
+	if len(org.credentials) > 0 {
+		details["credential_id"] = org.credentials[0]["id"]
+	}
+
+	return details, true
+}
+
+func (f *FakeAPI) addCluster(c *fakeCluster) {
+	id := stringValue(c.details["id"])
+	f.clusters[id] = c
+	f.clusterIDs = append(f.clusterIDs, id)
+}
+
+// updateLabels keeps the reserved labels of a v5 cluster in sync with its
+// details.
+func (f *FakeAPI) updateLabels(c *fakeCluster) {
+	labels := objectValue(c.details["labels"])
+	labels["giantswarm.io/organization"] = c.details["owner"]
+	c.details["labels"] = labels
+}
+
+func (f *FakeAPI) clusterListItem(c *fakeCluster) map[string]interface{} {
+	version := "v4"
+	if c.v5 {
+		version = "v5"
+	}
+
+	item := map[string]interface{}{
+		"id":              c.details["id"],
+		"name":            c.details["name"],
+		"owner":           c.details["owner"],
+		"create_date":     c.details["create_date"],
+		"release_version": c.details["release_version"],
+		"path":            fmt.Sprintf("/%s/clusters/%s/", version, c.details["id"]),
+	}
+	if c.v5 {
+		item["labels"] = c.details["labels"]
+	}
+
+	return item
+}
+
+// clusterDetailsV4 returns the details of a cluster as returned by the v4
+// API, which only contains the common fields for v5 clusters.
+func (f *FakeAPI) clusterDetailsV4(c *fakeCluster) map[string]interface{} {
+	if !c.v5 {
+		return c.details
+	}
+
+	details := map[string]interface{}{"workers": []interface{}{}}
+	for _, key := range []string{"id", "name", "owner", "release_version", "create_date", "api_endpoint", "credential_id"} {
+		if value, ok := c.details[key]; ok {
+			details[key] = value
+		}
+	}
+	details["availability_zones"] = objectValue(c.details["master_nodes"])["availability_zones"]
+
+	return details
+}
+
+func (f *FakeAPI) masterNodes(highAvailability bool) map[string]interface{} {
+	zones := f.zones()[:1]
+	if highAvailability {
+		zones = f.zones()
+	}
+
+	return map[string]interface{}{
+		"availability_zones": zones,
+		"high_availability":  highAvailability,
+		"num_ready":          len(zones),
+	}
+}
+
+func (f *FakeAPI) defaultWorker() map[string]interface{} {
+	worker := map[string]interface{}{
+		"cpu":     map[string]interface{}{"cores": 4},
+		"memory":  map[string]interface{}{"size_gb": 16},
+		"storage": map[string]interface{}{"size_gb": 100},
+	}
+
+	switch f.provider {
+	case "aws":
+		worker["aws"] = map[string]interface{}{"instance_type": "m5.xlarge"}
+	case "azure":
+		worker["azure"] = map[string]interface{}{"vm_size": "Standard_D4s_v3"}
+	}
+
+	return worker
+}
+
+func (f *FakeAPI) zones() []string {
+	switch f.provider {
+	case "aws":
+		return []string{"eu-central-1a", "eu-central-1b", "eu-central-1c"}
+	case "azure":
+		return []string{"1", "2", "3"}
+	}
+
+	return []string{"default"}
+}
+
+func (f *FakeAPI) supportsNodePools(release string) bool {
+	switch f.provider {
+	case "aws":
+		return releaseAtLeast(release, nodePoolsReleaseAWS)
+	case "azure":
+		return releaseAtLeast(release, nodePoolsReleaseAzure)
+	}
+
+	return false
+}
+
+func (f *FakeAPI) release(version string) map[string]interface{} {
+	for _, r := range f.releases {
+		if r["version"] == version {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (f *FakeAPI) latestRelease() string {
+	latest := ""
+	for _, r := range f.releases {
+		version := stringValue(r["version"])
+		if r["active"] == true && (latest == "" || releaseAtLeast(version, latest)) {
+			latest = version
+		}
+	}
+
+	return latest
+}
+
+func (f *FakeAPI) organization(w http.ResponseWriter, id string) (*fakeOrganization, bool) {
+	org, ok := f.organizations[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("Organization '%s' does not exist.", id))
+		return nil, false
+	}
+
+	return org, true
+}
+
+func (f *FakeAPI) cluster(w http.ResponseWriter, id string) (*fakeCluster, bool) {
+	c, ok := f.clusters[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("The cluster with ID '%s' could not be found.", id))
+		return nil, false
+	}
+
+	return c, true
+}
+
+// clusterV5 returns a cluster supporting node pools. Other clusters are
+// reported as not found, like by the v5 API.
+func (f *FakeAPI) clusterV5(w http.ResponseWriter, id string) (*fakeCluster, bool) {
+	c, ok := f.clusters[id]
+	if !ok || !c.v5 {
+		writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("The cluster with ID '%s' could not be found.", id))
+		return nil, false
+	}
+
+	return c, true
+}
+
+func (f *FakeAPI) nodePool(w http.ResponseWriter, clusterID, nodePoolID string) (*fakeCluster, map[string]interface{}, bool) {
+	c, ok := f.clusterV5(w, clusterID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	for _, np := range c.nodePools {
+		if np["id"] == nodePoolID {
+			return c, np, true
+		}
+	}
+
+	writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("The node pool with ID '%s' could not be found.", nodePoolID))
+	return nil, nil, false
+}
+
+func (f *FakeAPI) app(w http.ResponseWriter, clusterID, name string) (*fakeCluster, map[string]interface{}, bool) {
+	c, ok := f.cluster(w, clusterID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	for _, app := range c.apps {
+		if objectValue(app["metadata"])["name"] == name {
+			return c, app, true
+		}
+	}
+
+	writeFakeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("The app '%s' could not be found.", name))
+	return nil, nil, false
+}
+
+// newID returns a random ID of lowercase letters and digits, starting with
+// a letter.
+func (f *FakeAPI) newID(length int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	const chars = letters + "0123456789"
+
+	for {
+		b := make([]byte, length)
+		b[0] = letters[f.random.Intn(len(letters))]
+		for i := 1; i < length; i++ {
+			b[i] = chars[f.random.Intn(len(chars))]
+		}
+
+		if _, exists := f.clusters[string(b)]; !exists {
+			return string(b)
+		}
+	}
+}
+
+func setFakeAppStatus(app map[string]interface{}, now time.Time) {
+	version := objectValue(app["spec"])["version"]
+	app["status"] = map[string]interface{}{
+		"app_version": version,
+		"version":     version,
+		"release": map[string]interface{}{
+			"last_deployed": now.UTC().Format(time.RFC3339),
+			"status":        "DEPLOYED",
+		},
+	}
+}
+
+func fakeWorkers(worker map[string]interface{}, count int) []interface{} {
+	workers := []interface{}{}
+	for i := 0; i < count; i++ {
+		workers = append(workers, worker)
+	}
+
+	return workers
+}
+
+func fakePEM(blockType, content string) string {
+	return fmt.Sprintf("-----BEGIN %s-----\n%s\n-----END %s-----\n", blockType, base64.StdEncoding.EncodeToString([]byte(content)), blockType)
+}
+
+// parseFakeSelector parses a label selector like 'env=prod,team!=rocket'
+// and returns a function matching labels against it.
+func parseFakeSelector(selector string) (func(map[string]string) bool, error) {
+	type requirement struct {
+		key, value string
+		negate     bool
+		exists     bool
+	}
+
+	var requirements []requirement
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+			return nil, fmt.Errorf("label selector '%s' is invalid", selector)
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			requirements = append(requirements, requirement{key: kv[0], value: kv[1], negate: true})
+		case strings.Contains(part, "="):
+			kv := strings.SplitN(strings.Replace(part, "==", "=", 1), "=", 2)
+			requirements = append(requirements, requirement{key: kv[0], value: kv[1]})
+		case strings.HasPrefix(part, "!"):
+			requirements = append(requirements, requirement{key: part[1:], exists: true, negate: true})
+		default:
+			requirements = append(requirements, requirement{key: part, exists: true})
+		}
+	}
+
+	return func(labels map[string]string) bool {
+		for _, req := range requirements {
+			value, ok := labels[req.key]
+			matches := ok
+			if !req.exists {
+				matches = ok && value == req.value
+			}
+			if matches == req.negate {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// mergeFakePatch applies a JSON merge patch (RFC 7386) to an object.
+func mergeFakePatch(target, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+
+		patchObject, isObject := value.(map[string]interface{})
+		targetObject, targetIsObject := target[key].(map[string]interface{})
+		if isObject && targetIsObject {
+			mergeFakePatch(targetObject, patchObject)
+			continue
+		}
+
+		target[key] = value
+	}
+}
+
+func readFakeBody(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	body := map[string]interface{}{}
+	if r.Body == nil {
+		return body, true
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && err != io.EOF {
+		writeFakeError(w, http.StatusBadRequest, "INVALID_INPUT", "The request body is not valid JSON.")
+		return nil, false
+	}
+
+	return body, true
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, code, message string) {
+	writeFakeJSON(w, status, map[string]interface{}{"code": code, "message": message})
+}
+
+func releaseAtLeast(version, minimum string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	m, err := semver.NewVersion(minimum)
+	if err != nil {
+		return false
+	}
+
+	return !v.LessThan(m)
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func intValue(v interface{}, defaultValue int) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+
+	return defaultValue
+}
+
+func objectValue(v interface{}) map[string]interface{} {
+	if o, ok := v.(map[string]interface{}); ok {
+		return o
+	}
+
+	return map[string]interface{}{}
+}
+
+func arrayValue(v interface{}) []interface{} {
+	switch a := v.(type) {
+	case []interface{}:
+		return a
+	case []string:
+		items := make([]interface{}, len(a))
+		for i := range a {
+			items[i] = a[i]
+		}
+		return items
+	}
+
+	return nil
+}
+
+func stringMapValue(v interface{}) map[string]string {
+	labels := map[string]string{}
+	for key, value := range objectValue(v) {
+		labels[key] = stringValue(value)
+	}
+
+	return labels
+}
@@ -0,0 +1,193 @@
+package testutils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeRequest sends a request to the fake API and decodes the JSON response.
+func fakeRequest(t *testing.T, server *httptest.Server, method, path, body string, response interface{}) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	req.Header.Set("Authorization", "giantswarm test-token")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	defer resp.Body.Close()
+
+	if response != nil {
+		err = json.NewDecoder(resp.Body).Decode(response)
+		if err != nil {
+			t.Fatalf("%s %s - unexpected error decoding response: %#v", method, path, err)
+		}
+	}
+
+	return resp
+}
+
+// TestFakeAPIUnauthorized checks that requests without a token are rejected.
+func TestFakeAPIUnauthorized(t *testing.T) {
+	server := httptest.NewServer(NewFakeAPI("aws"))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v4/clusters/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", resp.StatusCode)
+	}
+}
+
+// TestFakeAPIClusterV4 creates, shows, scales and deletes a cluster without
+// node pools.
+func TestFakeAPIClusterV4(t *testing.T) {
+	server := httptest.NewServer(NewFakeAPI("aws"))
+	defer server.Close()
+
+	resp := fakeRequest(t, server, http.MethodPost, "/v4/clusters/", `{"owner": "unknown"}`, nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown owner, got %d", resp.StatusCode)
+	}
+
+	resp = fakeRequest(t, server, http.MethodPost, "/v4/clusters/", `{"owner": "acme", "name": "Test", "release_version": "9.3.5", "scaling": {"min": 2, "max": 2}}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+	location := strings.Split(resp.Header.Get("Location"), "/")
+	if len(location) < 4 || location[3] == "" {
+		t.Fatalf("Expected location with cluster ID, got %q", resp.Header.Get("Location"))
+	}
+	id := location[3]
+
+	var details map[string]interface{}
+	fakeRequest(t, server, http.MethodGet, "/v4/clusters/"+id+"/", "", &details)
+	if details["name"] != "Test" || len(details["workers"].([]interface{})) != 2 {
+		t.Errorf("Expected cluster 'Test' with 2 workers, got %#v", details)
+	}
+
+	// Clusters without node pools are not available via v5.
+	resp = fakeRequest(t, server, http.MethodGet, "/v5/clusters/"+id+"/", "", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 via v5, got %d", resp.StatusCode)
+	}
+
+	fakeRequest(t, server, http.MethodPatch, "/v4/clusters/"+id+"/", `{"scaling": {"min": 5, "max": 5}}`, &details)
+	if len(details["workers"].([]interface{})) != 5 {
+		t.Errorf("Expected 5 workers after scaling, got %#v", details["workers"])
+	}
+
+	var status struct {
+		Cluster struct {
+			Nodes   []interface{} `json:"nodes"`
+			Scaling struct {
+				DesiredCapacity int `json:"desiredCapacity"`
+			} `json:"scaling"`
+		} `json:"cluster"`
+	}
+	fakeRequest(t, server, http.MethodGet, "/v4/clusters/"+id+"/status/", "", &status)
+	if len(status.Cluster.Nodes) != 6 || status.Cluster.Scaling.DesiredCapacity != 5 {
+		t.Errorf("Expected 1 master and 5 workers in status, got %#v", status)
+	}
+
+	resp = fakeRequest(t, server, http.MethodDelete, "/v4/clusters/"+id+"/", "", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", resp.StatusCode)
+	}
+
+	var clusters []interface{}
+	fakeRequest(t, server, http.MethodGet, "/v4/clusters/", "", &clusters)
+	if len(clusters) != 0 {
+		t.Errorf("Expected no clusters after deletion, got %#v", clusters)
+	}
+}
+
+// TestFakeAPIClusterV5 checks node pools and labels of a cluster created
+// via v5.
+func TestFakeAPIClusterV5(t *testing.T) {
+	server := httptest.NewServer(NewFakeAPI("aws"))
+	defer server.Close()
+
+	var details map[string]interface{}
+	resp := fakeRequest(t, server, http.MethodPost, "/v5/clusters/", `{"owner": "acme", "name": "Test"}`, &details)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %#v", resp.StatusCode, details)
+	}
+	id := details["id"].(string)
+	if details["release_version"] != "13.0.1" {
+		t.Errorf("Expected latest active release, got %v", details["release_version"])
+	}
+
+	var nodePool map[string]interface{}
+	fakeRequest(t, server, http.MethodPost, "/v5/clusters/"+id+"/nodepools/", `{"name": "pool", "scaling": {"min": 2, "max": 4}}`, &nodePool)
+	npID := nodePool["id"].(string)
+
+	fakeRequest(t, server, http.MethodPatch, "/v5/clusters/"+id+"/nodepools/"+npID+"/", `{"scaling": {"min": 3}}`, &nodePool)
+	if nodePool["status"].(map[string]interface{})["nodes_ready"] != float64(3) {
+		t.Errorf("Expected 3 nodes after scaling, got %#v", nodePool["status"])
+	}
+
+	resp = fakeRequest(t, server, http.MethodPatch, "/v5/clusters/"+id+"/nodepools/"+npID+"/", `{"scaling": {"min": 5}}`, nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for min > max, got %d", resp.StatusCode)
+	}
+
+	var labels map[string]map[string]string
+	fakeRequest(t, server, http.MethodPut, "/v5/clusters/"+id+"/labels/", `{"labels": {"env": "prod"}}`, &labels)
+	if labels["labels"]["env"] != "prod" || labels["labels"]["giantswarm.io/organization"] != "acme" {
+		t.Errorf("Expected label env=prod next to reserved labels, got %#v", labels)
+	}
+
+	resp = fakeRequest(t, server, http.MethodPut, "/v5/clusters/"+id+"/labels/", `{"labels": {"giantswarm.io/cluster": "other"}}`, nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for reserved label, got %d", resp.StatusCode)
+	}
+
+	var clusters []map[string]interface{}
+	fakeRequest(t, server, http.MethodPost, "/v5/clusters/by_label/", `{"labels": "env=prod"}`, &clusters)
+	if len(clusters) != 1 || clusters[0]["id"] != id {
+		t.Errorf("Expected cluster %s to match, got %#v", id, clusters)
+	}
+	fakeRequest(t, server, http.MethodPost, "/v5/clusters/by_label/", `{"labels": "env!=prod"}`, &clusters)
+	if len(clusters) != 0 {
+		t.Errorf("Expected no cluster to match, got %#v", clusters)
+	}
+
+	resp = fakeRequest(t, server, http.MethodDelete, "/v5/clusters/"+id+"/nodepools/"+npID+"/", "", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", resp.StatusCode)
+	}
+	resp = fakeRequest(t, server, http.MethodGet, "/v5/clusters/"+id+"/nodepools/"+npID+"/", "", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 after deletion, got %d", resp.StatusCode)
+	}
+}
+
+// TestMergeFakePatch tests applying JSON merge patches.
+func TestMergeFakePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"name":   "old",
+		"labels": map[string]interface{}{"a": "1", "b": "2"},
+	}
+	mergeFakePatch(target, map[string]interface{}{
+		"name":   "new",
+		"labels": map[string]interface{}{"a": nil, "c": "3"},
+	})
+
+	expected := `{"labels":{"b":"2","c":"3"},"name":"new"}`
+	out, _ := json.Marshal(target)
+	if string(out) != expected {
+		t.Errorf("Expected %s, got %s", expected, string(out))
+	}
+}